@@ -2,17 +2,26 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/achievement"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/admission"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/anticheat"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/config"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/handler"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/leaderboard"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/nickname"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/store"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
@@ -21,9 +30,6 @@ import (
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins for development
-	},
 }
 
 func main() {
@@ -41,21 +47,113 @@ func main() {
 	}
 	slog.Info("database connected")
 
-	// Initialize Game Center verifier
+	if err := ensureSeason(ctx, accountStore, cfg.SeasonID); err != nil {
+		if errors.Is(err, leaderboard.ErrSeasonEnded) {
+			slog.Error("SEASON_ID names a season that has already ended; set a new season ID", "season", cfg.SeasonID)
+		} else {
+			slog.Error("failed to start season", "season", cfg.SeasonID, "error", err)
+		}
+		os.Exit(1)
+	}
+
+	// Initialize Game Center verifier and optional auth providers
 	gcVerifier := auth.NewGameCenterVerifier(cfg.GCBundleIDs, cfg.GCTimestampTolerance)
+	authProviders, err := loadAuthProviders(cfg)
+	if err != nil {
+		slog.Error("failed to load auth providers", "error", err)
+		os.Exit(1)
+	}
+
+	if cfg.GuestCredentialKey == "" {
+		slog.Warn("GUEST_CREDENTIAL_KEY is not set, guest credentials will not survive a restart")
+	}
+	if cfg.SessionTokenKey == "" {
+		slog.Warn("SESSION_TOKEN_KEY is not set, session tokens will not survive a restart")
+	}
+	sessionTokens := auth.NewSessionTokens([]byte(cfg.SessionTokenKey), cfg.SessionTokenTTL)
+	if err := sessionTokens.Persist(ctx, accountStore); err != nil {
+		slog.Error("failed to load session token revocations", "error", err)
+		os.Exit(1)
+	}
+
+	nicknamePolicy, err := loadNicknamePolicy(cfg.NicknameBannedWordsFile)
+	if err != nil {
+		slog.Error("failed to load banned nickname words", "error", err)
+		os.Exit(1)
+	}
+
+	cheatConfig := anticheat.DefaultConfig()
+	cheatConfig.KickScore = float64(cfg.AntiCheatKickScore)
+	cheatConfig.FlagScore = float64(cfg.AntiCheatFlagScore)
+
+	// Load achievement definitions
+	achievements, err := loadAchievements(cfg.AchievementsFile)
+	if err != nil {
+		slog.Error("failed to load achievements", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("achievements loaded", "count", len(achievements))
+
+	trustedProxies, err := admission.ParsePrefixes(cfg.TrustedProxies)
+	if err != nil {
+		slog.Error("invalid TRUSTED_PROXIES", "error", err)
+		os.Exit(1)
+	}
+	admit := admission.New(admission.Config{
+		AllowedOrigins:     cfg.WSAllowedOrigins,
+		MaxConnections:     cfg.WSMaxConnections,
+		MaxPerIP:           cfg.WSMaxConnectionsPerIP,
+		MaxUnauthenticated: cfg.WSMaxUnauthenticated,
+		TrustedProxies:     trustedProxies,
+	})
+	upgrader.CheckOrigin = admit.CheckOrigin
+	if len(cfg.WSAllowedOrigins) == 0 {
+		slog.Warn("WS_ALLOWED_ORIGINS is not set; accepting WebSocket connections from any origin")
+	}
 
 	hub := ws.NewHub()
+	rateLimits := ws.DefaultRateLimits()
+	rateLimits.Connection = ws.Limit{Rate: float64(cfg.WSMessageRate), Burst: cfg.WSMessageBurst}
+	rateLimits.MaxStrikes = cfg.WSMaxStrikes
+	hub.RateLimits = &rateLimits
 	rm := room.NewManager()
-	router := handler.NewRouter(rm, gcVerifier, accountStore)
+	router := handler.NewRouter(rm, gcVerifier, accountStore, handler.Options{
+		AchievementStore: accountStore,
+		Achievements:     achievements,
+		LeaderboardStore: accountStore,
+		Season:           cfg.SeasonID,
+		FriendStore:      accountStore,
+		AuthProviders:    authProviders,
+		GuestCredentials: auth.NewGuestCredentials([]byte(cfg.GuestCredentialKey)),
+		SessionTokens:    sessionTokens,
+		NicknamePolicy:   nicknamePolicy,
+		NicknameCooldown: cfg.NicknameChangeCooldown,
+		ModerationStore:  accountStore,
+		AdminAccountIDs:  cfg.AdminAccountIDs,
+		AntiCheat:        &cheatConfig,
+	})
 
 	hub.OnMessage = router.HandleMessage
 	hub.OnDisconnect = router.HandleDisconnect
+	hub.OnFlood = router.HandleFlood
 
 	go hub.Run()
+	go rm.MonitorAFK(ctx, room.AFKPolicy{
+		LobbyWarn:    cfg.AFKLobbyWarn,
+		LobbyTimeout: cfg.AFKLobbyTimeout,
+		GameWarn:     cfg.AFKGameWarn,
+		GameTimeout:  cfg.AFKGameTimeout,
+	}, time.Second)
 
-	http.HandleFunc("/health", handleHealth)
+	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		handleHealth(hub, admit, w, r)
+	})
+	http.Handle("/leaderboard", handler.OptionalBearer(sessionTokens, router.Leaderboard()))
+	if admin := router.Admin(); admin != nil {
+		http.Handle("/admin/", handler.RequireBearer(sessionTokens, admin))
+	}
 	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		handleWebSocket(hub, router, w, r)
+		handleWebSocket(hub, router, admit, w, r)
 	})
 
 	addr := fmt.Sprintf(":%d", cfg.Port)
@@ -71,6 +169,7 @@ func main() {
 		if err := server.Shutdown(context.Background()); err != nil {
 			slog.Error("server shutdown error", "error", err)
 		}
+		router.FlushAntiCheat()
 		if err := accountStore.Close(); err != nil {
 			slog.Error("database close error", "error", err)
 		}
@@ -85,20 +184,37 @@ func main() {
 	}
 }
 
-func handleHealth(w http.ResponseWriter, _ *http.Request) {
+func handleHealth(hub *ws.Hub, admit *admission.Controller, w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"ok"}`))
+	json.NewEncoder(w).Encode(struct {
+		Status    string          `json:"status"`
+		WS        ws.HubStats     `json:"ws"`
+		Admission admission.Stats `json:"admission"`
+	}{"ok", hub.Stats(), admit.Stats()})
 }
 
-func handleWebSocket(hub *ws.Hub, router *handler.Router, w http.ResponseWriter, r *http.Request) {
+func handleWebSocket(hub *ws.Hub, router *handler.Router, admit *admission.Controller, w http.ResponseWriter, r *http.Request) {
+	// Refuse over-limit connections with a plain HTTP error before upgrading
+	ticket, err := admit.Admit(r)
+	if err != nil {
+		slog.Warn("websocket connection refused", "ip", admit.ClientIP(r), "origin", r.Header.Get("Origin"), "reason", err)
+		if admission.StatusCode(err) != http.StatusForbidden {
+			w.Header().Set("Retry-After", "5")
+		}
+		http.Error(w, err.Error(), admission.StatusCode(err))
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
+		ticket.Release()
 		slog.Error("websocket upgrade failed", "error", err)
 		return
 	}
 
-	client := ws.NewClient(fmt.Sprintf("client-%d", hub.ClientCount()+1), hub, conn)
+	client := ws.NewClient(uuid.New().String(), hub, conn)
+	client.Admission = ticket
 	hub.Register <- client
 
 	router.StartAuthTimeout(client)
@@ -107,6 +223,51 @@ func handleWebSocket(hub *ws.Hub, router *handler.Router, w http.ResponseWriter,
 	go client.ReadPump()
 }
 
+// ensureSeason rolls over to seasonID if it differs from the active season,
+// archiving the previous season's standings.
+func ensureSeason(ctx context.Context, lb store.LeaderboardStore, seasonID string) error {
+	if seasonID == "" {
+		return nil
+	}
+	current, err := lb.CurrentSeason(ctx)
+	if err != nil {
+		return err
+	}
+	if current == seasonID {
+		return nil
+	}
+	if err := lb.StartSeason(ctx, seasonID); err != nil {
+		return err
+	}
+	slog.Info("season started", "season", seasonID, "previous", current)
+	return nil
+}
+
+func loadAchievements(path string) ([]achievement.Definition, error) {
+	if path == "" {
+		return achievement.Default()
+	}
+	return achievement.LoadFile(path)
+}
+
+// loadAuthProviders builds the auth providers enabled by the configuration.
+func loadAuthProviders(cfg *config.Config) ([]auth.AuthProvider, error) {
+	var providers []auth.AuthProvider
+	if len(cfg.AppleAudiences) > 0 {
+		var keys auth.KeySet = auth.NewRemoteKeySet(cfg.AppleJWKSURL)
+		if cfg.AppleJWKSFile != "" {
+			fileKeys, err := auth.LoadJWKSFile(cfg.AppleJWKSFile)
+			if err != nil {
+				return nil, err
+			}
+			keys = fileKeys
+		}
+		providers = append(providers, auth.NewAppleProvider(keys, cfg.AppleAudiences))
+		slog.Info("sign in with apple enabled", "audiences", cfg.AppleAudiences)
+	}
+	return providers, nil
+}
+
 func setupLogger(cfg *config.Config) {
 	var h slog.Handler
 	opts := &slog.HandlerOptions{}
@@ -131,3 +292,14 @@ func setupLogger(cfg *config.Config) {
 
 	slog.SetDefault(slog.New(h))
 }
+
+func loadNicknamePolicy(path string) (*nickname.Policy, error) {
+	if path == "" {
+		return nickname.NewPolicy(nil), nil
+	}
+	words, err := nickname.LoadWords(path)
+	if err != nil {
+		return nil, err
+	}
+	return nickname.NewPolicy(words), nil
+}
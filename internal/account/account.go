@@ -14,6 +14,9 @@ type Account struct {
 	IsGuest      bool      `json:"is_guest"`
 	CreatedAt    time.Time `json:"created_at"`
 	LastLoginAt  time.Time `json:"last_login_at"`
+
+	// NicknameChangedAt is when the player last changed their nickname, nil if never.
+	NicknameChangedAt *time.Time `json:"nickname_changed_at,omitempty"`
 }
 
 // NewGameCenterAccount creates a new account linked to a Game Center player ID.
@@ -40,3 +43,15 @@ func NewGuestAccount(nickname string) *Account {
 		LastLoginAt: now,
 	}
 }
+
+// NewAccount creates a new non-guest account, such as one linked to
+// a Sign in with Apple identity.
+func NewAccount(nickname string) *Account {
+	now := time.Now()
+	return &Account{
+		ID:          uuid.New().String(),
+		Nickname:    nickname,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	}
+}
@@ -36,3 +36,13 @@ func TestNewGameCenterAccount_UniqueIDs(t *testing.T) {
 
 	assert.NotEqual(t, acc1.ID, acc2.ID)
 }
+
+func TestNewAccount(t *testing.T) {
+	acc := NewAccount("사과")
+
+	assert.NotEmpty(t, acc.ID)
+	assert.Nil(t, acc.GameCenterID)
+	assert.Equal(t, "사과", acc.Nickname)
+	assert.False(t, acc.IsGuest)
+	assert.False(t, acc.CreatedAt.IsZero())
+}
@@ -0,0 +1,105 @@
+package achievement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+)
+
+func TestDefault_Valid(t *testing.T) {
+	defs, err := Default()
+	require.NoError(t, err)
+	assert.NotEmpty(t, defs)
+}
+
+func TestParse_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"missing id", `[{"metric":"arrests","threshold":1,"scope":"game"}]`},
+		{"unknown metric", `[{"id":"a","metric":"jumps","threshold":1,"scope":"game"}]`},
+		{"zero threshold", `[{"id":"a","metric":"arrests","threshold":0,"scope":"game"}]`},
+		{"unknown scope", `[{"id":"a","metric":"arrests","threshold":1,"scope":"weekly"}]`},
+		{"unknown role", `[{"id":"a","metric":"arrests","threshold":1,"scope":"game","role":"polise"}]`},
+		{"duplicate id", `[{"id":"a","metric":"arrests","threshold":1,"scope":"game"},{"id":"a","metric":"wins","threshold":1,"scope":"game"}]`},
+		{"malformed", `{`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.json))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParse_Role(t *testing.T) {
+	defs, err := Parse([]byte(`[{"id":"a","metric":"wins","threshold":1,"scope":"lifetime","role":"police"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, game.RolePolice, defs[0].Role)
+
+	defs, err = Parse([]byte(`[{"id":"a","metric":"wins","threshold":1,"scope":"lifetime"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, game.RoleNone, defs[0].Role)
+}
+
+func TestEvaluate_GameScope(t *testing.T) {
+	defs := []Definition{{ID: "triple", Metric: MetricArrests, Threshold: 3, Scope: ScopeGame}}
+	now := time.Now()
+
+	// Two arrests: progress recorded but not unlocked
+	changed, unlocked := Evaluate(defs, nil, Outcome{Stats: game.PlayerStats{Role: game.RolePolice, Arrests: 2}}, now)
+	require.Len(t, changed, 1)
+	assert.Equal(t, 2.0, changed[0].Value)
+	assert.Empty(t, unlocked)
+
+	// Game scope does not accumulate: another two arrests keep the best of 2
+	current := map[string]Progress{"triple": changed[0]}
+	changed, unlocked = Evaluate(defs, current, Outcome{Stats: game.PlayerStats{Role: game.RolePolice, Arrests: 2}}, now)
+	assert.Empty(t, changed)
+	assert.Empty(t, unlocked)
+
+	// Three in one game unlocks
+	changed, unlocked = Evaluate(defs, current, Outcome{Stats: game.PlayerStats{Role: game.RolePolice, Arrests: 3}}, now)
+	require.Len(t, unlocked, 1)
+	assert.True(t, changed[0].Unlocked())
+}
+
+func TestEvaluate_LifetimeScope(t *testing.T) {
+	defs := []Definition{{ID: "wins", Metric: MetricWins, Threshold: 2, Scope: ScopeLifetime, Role: game.RolePolice}}
+	now := time.Now()
+	police := game.PlayerStats{Role: game.RolePolice}
+
+	changed, unlocked := Evaluate(defs, nil, Outcome{Stats: police, Won: true}, now)
+	require.Len(t, changed, 1)
+	assert.Empty(t, unlocked)
+
+	// Win as thief does not count toward a police-only achievement
+	current := map[string]Progress{"wins": changed[0]}
+	changed, _ = Evaluate(defs, current, Outcome{Stats: game.PlayerStats{Role: game.RoleThief}, Won: true}, now)
+	assert.Empty(t, changed)
+
+	changed, unlocked = Evaluate(defs, current, Outcome{Stats: police, Won: true}, now)
+	require.Len(t, unlocked, 1)
+	assert.Equal(t, 2.0, changed[0].Value)
+}
+
+func TestEvaluate_AlreadyUnlockedSkipped(t *testing.T) {
+	defs := []Definition{{ID: "first", Metric: MetricArrests, Threshold: 1, Scope: ScopeLifetime}}
+	unlockedAt := time.Now()
+	current := map[string]Progress{"first": {AchievementID: "first", Value: 1, UnlockedAt: &unlockedAt}}
+
+	changed, unlocked := Evaluate(defs, current, Outcome{Stats: game.PlayerStats{Arrests: 5}}, time.Now())
+	assert.Empty(t, changed)
+	assert.Empty(t, unlocked)
+}
+
+func TestOutcome_LastFreeThiefRequiresWin(t *testing.T) {
+	assert.Equal(t, 0.0, Outcome{LastFreeThief: true}.Metric(MetricLastFreeThiefWins))
+	assert.Equal(t, 1.0, Outcome{LastFreeThief: true, Won: true}.Metric(MetricLastFreeThiefWins))
+}
@@ -0,0 +1,99 @@
+package achievement
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+)
+
+//go:embed definitions.json
+var defaultDefinitions []byte
+
+// Scope controls how a metric accumulates toward an achievement.
+type Scope string
+
+const (
+	// ScopeGame requires the threshold to be reached within a single game.
+	ScopeGame Scope = "game"
+	// ScopeLifetime accumulates the metric across all games.
+	ScopeLifetime Scope = "lifetime"
+)
+
+// Definition describes an achievement. Definitions are data, loaded from JSON,
+// so new achievements can be added without code changes.
+type Definition struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Metric      string    `json:"metric"`
+	Threshold   float64   `json:"threshold"`
+	Scope       Scope     `json:"scope"`
+	Role        game.Role `json:"role,omitempty"`        // only counts games played in this role
+	RequireWin  bool      `json:"require_win,omitempty"` // only counts games that were won
+}
+
+// Default returns the built-in achievement definitions.
+func Default() ([]Definition, error) {
+	return Parse(defaultDefinitions)
+}
+
+// LoadFile reads achievement definitions from a JSON file.
+func LoadFile(path string) ([]Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates a JSON array of definitions.
+func Parse(data []byte) ([]Definition, error) {
+	var defs []Definition
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("invalid achievement definitions: %w", err)
+	}
+	// game.Role reads an unknown role as no role, so roles are checked as written
+	var roles []struct {
+		Role string `json:"role"`
+	}
+	if err := json.Unmarshal(data, &roles); err != nil {
+		return nil, fmt.Errorf("invalid achievement definitions: %w", err)
+	}
+
+	seen := make(map[string]bool, len(defs))
+	for i, d := range defs {
+		if err := d.validate(roles[i].Role); err != nil {
+			return nil, fmt.Errorf("achievement %q: %w", d.ID, err)
+		}
+		if seen[d.ID] {
+			return nil, fmt.Errorf("achievement %q: duplicate id", d.ID)
+		}
+		seen[d.ID] = true
+	}
+	return defs, nil
+}
+
+func (d Definition) validate(role string) error {
+	if d.ID == "" {
+		return errors.New("id is required")
+	}
+	if !knownMetrics[d.Metric] {
+		return fmt.Errorf("unknown metric %q", d.Metric)
+	}
+	if d.Threshold <= 0 {
+		return errors.New("threshold must be positive")
+	}
+	if d.Scope != ScopeGame && d.Scope != ScopeLifetime {
+		return fmt.Errorf("unknown scope %q", d.Scope)
+	}
+	switch role {
+	case "", game.RoleNone.String(), game.RolePolice.String(), game.RoleThief.String():
+	default:
+		return fmt.Errorf("unknown role %q", role)
+	}
+	return nil
+}
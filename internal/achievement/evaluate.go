@@ -0,0 +1,143 @@
+package achievement
+
+import (
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+)
+
+// Metric names usable in definitions.
+const (
+	MetricArrests           = "arrests"
+	MetricRescues           = "rescues"
+	MetricTimesArrested     = "times_arrested"
+	MetricBoostersCollected = "boosters_collected"
+	MetricStonesHit         = "stones_hit"
+	MetricDistance          = "distance"
+	MetricTimeNearThieves   = "time_near_thieves"
+	MetricTimeFree          = "time_free"
+	MetricGamesPlayed       = "games_played"
+	MetricWins              = "wins"
+	MetricMVPs              = "mvps"
+	MetricLastFreeThiefWins = "last_free_thief_wins"
+)
+
+var knownMetrics = map[string]bool{
+	MetricArrests:           true,
+	MetricRescues:           true,
+	MetricTimesArrested:     true,
+	MetricBoostersCollected: true,
+	MetricStonesHit:         true,
+	MetricDistance:          true,
+	MetricTimeNearThieves:   true,
+	MetricTimeFree:          true,
+	MetricGamesPlayed:       true,
+	MetricWins:              true,
+	MetricMVPs:              true,
+	MetricLastFreeThiefWins: true,
+}
+
+// Outcome is one player's result from a finished game.
+type Outcome struct {
+	Stats         game.PlayerStats
+	Won           bool
+	MVP           bool
+	LastFreeThief bool
+}
+
+// Metric returns the value of a named metric for this outcome.
+func (o Outcome) Metric(name string) float64 {
+	switch name {
+	case MetricArrests:
+		return float64(o.Stats.Arrests)
+	case MetricRescues:
+		return float64(o.Stats.Rescues)
+	case MetricTimesArrested:
+		return float64(o.Stats.TimesArrested)
+	case MetricBoostersCollected:
+		return float64(o.Stats.BoostersCollected)
+	case MetricStonesHit:
+		return float64(o.Stats.StonesHit)
+	case MetricDistance:
+		return o.Stats.Distance
+	case MetricTimeNearThieves:
+		return o.Stats.TimeNearThieves
+	case MetricTimeFree:
+		return o.Stats.TimeFree
+	case MetricGamesPlayed:
+		return 1
+	case MetricWins:
+		return boolMetric(o.Won)
+	case MetricMVPs:
+		return boolMetric(o.MVP)
+	case MetricLastFreeThiefWins:
+		return boolMetric(o.Won && o.LastFreeThief)
+	default:
+		return 0
+	}
+}
+
+func boolMetric(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Progress is an account's persisted progress toward one achievement.
+// For game-scoped achievements Value holds the best single-game value.
+type Progress struct {
+	AchievementID string     `json:"achievement_id"`
+	Value         float64    `json:"value"`
+	UnlockedAt    *time.Time `json:"unlocked_at,omitempty"`
+}
+
+// Unlocked reports whether the achievement has been unlocked.
+func (p Progress) Unlocked() bool {
+	return p.UnlockedAt != nil
+}
+
+// Evaluate applies a game outcome to existing progress.
+// It returns the progress entries that changed and the definitions newly unlocked.
+func Evaluate(defs []Definition, current map[string]Progress, o Outcome, now time.Time) ([]Progress, []Definition) {
+	var changed []Progress
+	var unlocked []Definition
+
+	for _, d := range defs {
+		p := current[d.ID]
+		p.AchievementID = d.ID
+		if p.Unlocked() {
+			continue
+		}
+		if d.Role != game.RoleNone && d.Role != o.Stats.Role {
+			continue
+		}
+		if d.RequireWin && !o.Won {
+			continue
+		}
+
+		value := o.Metric(d.Metric)
+		if value == 0 {
+			continue
+		}
+
+		switch d.Scope {
+		case ScopeGame:
+			if value <= p.Value {
+				continue
+			}
+			p.Value = value
+		case ScopeLifetime:
+			p.Value += value
+		}
+
+		if p.Value >= d.Threshold {
+			unlockedAt := now
+			p.UnlockedAt = &unlockedAt
+			unlocked = append(unlocked, d)
+		}
+		changed = append(changed, p)
+	}
+
+	return changed, unlocked
+}
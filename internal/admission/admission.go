@@ -0,0 +1,286 @@
+// Package admission decides which WebSocket connections the server accepts
+// before upgrading them: allowed origins, global and per-IP connection caps,
+// and a budget for connections that have not authenticated yet.
+package admission
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrOriginNotAllowed       = errors.New("origin not allowed")
+	ErrTooManyConnections     = errors.New("server is at its connection limit")
+	ErrTooManyFromIP          = errors.New("too many connections from this address")
+	ErrTooManyUnauthenticated = errors.New("too many connections waiting to authenticate")
+)
+
+// Config sets the admission limits. Zero limits are unlimited.
+type Config struct {
+	// AllowedOrigins lists accepted Origin headers (scheme://host[:port]),
+	// compared case-insensitively by scheme and host only, so a trailing
+	// slash on either side does not matter. Empty allows any origin.
+	// Requests without an Origin header, as sent by native clients, are
+	// always allowed.
+	AllowedOrigins []string
+
+	MaxConnections     int
+	MaxPerIP           int
+	MaxUnauthenticated int
+
+	// TrustedProxies are the peers whose X-Forwarded-For and X-Real-IP
+	// headers are believed when finding a client's address.
+	TrustedProxies []netip.Prefix
+}
+
+// Stats counts admitted connections and rejections.
+type Stats struct {
+	Connections     int            `json:"connections"`
+	Unauthenticated int            `json:"unauthenticated"`
+	Rejected        map[string]int `json:"rejected"` // by reason
+}
+
+// Controller tracks open connections against the configured limits.
+type Controller struct {
+	cfg     Config
+	origins map[string]bool
+
+	mu       sync.Mutex
+	total    int
+	unauth   int
+	perIP    map[string]int
+	rejected map[string]int
+}
+
+// New creates a controller with the given limits.
+func New(cfg Config) *Controller {
+	c := &Controller{
+		cfg:      cfg,
+		origins:  make(map[string]bool, len(cfg.AllowedOrigins)),
+		perIP:    make(map[string]int),
+		rejected: make(map[string]int),
+	}
+	for _, o := range cfg.AllowedOrigins {
+		if o = normalizeOrigin(o); o != "" {
+			c.origins[o] = true
+		}
+	}
+	return c
+}
+
+// normalizeOrigin reduces an origin to its lowercase scheme://host[:port],
+// dropping any path, or returns "" if it is not an absolute URL.
+func normalizeOrigin(origin string) string {
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host)
+}
+
+// CheckOrigin reports whether the request's origin is allowed. It fits
+// websocket.Upgrader.CheckOrigin.
+func (c *Controller) CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || len(c.cfg.AllowedOrigins) == 0 {
+		return true
+	}
+	return c.origins[normalizeOrigin(origin)]
+}
+
+// ClientIP returns the address the request came from. Forwarding headers
+// are used only when the direct peer is a trusted proxy; the client is the
+// rightmost X-Forwarded-For entry that is not itself a trusted proxy.
+// A chain with an unparsable entry before that is not believed at all.
+func (c *Controller) ClientIP(r *http.Request) string {
+	peer := remoteAddr(r.RemoteAddr)
+	if !peer.IsValid() {
+		return r.RemoteAddr
+	}
+	if !c.trusted(peer) {
+		return peer.String()
+	}
+
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				// Whoever wrote the chain cannot be told apart from the proxy
+				return peer.String()
+			}
+			addr = addr.Unmap()
+			if !c.trusted(addr) {
+				return addr.String()
+			}
+		}
+	}
+	if realIP, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return realIP.Unmap().String()
+	}
+	return peer.String()
+}
+
+func (c *Controller) trusted(addr netip.Addr) bool {
+	for _, p := range c.cfg.TrustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteAddr(hostport string) netip.Addr {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+// Admit reserves a connection slot for the request, or returns why it is
+// refused. The ticket must be released when the connection closes.
+func (c *Controller) Admit(r *http.Request) (*Ticket, error) {
+	if !c.CheckOrigin(r) {
+		c.reject(ErrOriginNotAllowed)
+		return nil, ErrOriginNotAllowed
+	}
+	ip := c.ClientIP(r)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	switch {
+	case c.cfg.MaxConnections > 0 && c.total >= c.cfg.MaxConnections:
+		err = ErrTooManyConnections
+	case c.cfg.MaxPerIP > 0 && c.perIP[ip] >= c.cfg.MaxPerIP:
+		err = ErrTooManyFromIP
+	case c.cfg.MaxUnauthenticated > 0 && c.unauth >= c.cfg.MaxUnauthenticated:
+		err = ErrTooManyUnauthenticated
+	}
+	if err != nil {
+		c.rejected[reason(err)]++
+		return nil, err
+	}
+
+	c.total++
+	c.unauth++
+	c.perIP[ip]++
+	return &Ticket{c: c, IP: ip}, nil
+}
+
+func (c *Controller) reject(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rejected[reason(err)]++
+}
+
+// Stats returns current connection counts and rejections so far.
+func (c *Controller) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rejected := make(map[string]int, len(c.rejected))
+	for k, v := range c.rejected {
+		rejected[k] = v
+	}
+	return Stats{Connections: c.total, Unauthenticated: c.unauth, Rejected: rejected}
+}
+
+// StatusCode is the HTTP status for refusing a connection with err.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrOriginNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, ErrTooManyFromIP):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
+
+func reason(err error) string {
+	switch {
+	case errors.Is(err, ErrOriginNotAllowed):
+		return "origin"
+	case errors.Is(err, ErrTooManyConnections):
+		return "max_connections"
+	case errors.Is(err, ErrTooManyFromIP):
+		return "max_per_ip"
+	case errors.Is(err, ErrTooManyUnauthenticated):
+		return "max_unauthenticated"
+	default:
+		return "other"
+	}
+}
+
+// Ticket is an admitted connection's slot. Its methods are safe to call
+// more than once and from any goroutine.
+type Ticket struct {
+	c  *Controller
+	IP string
+
+	authenticated bool
+	released      bool
+}
+
+// Authenticated moves the connection out of the unauthenticated budget.
+func (t *Ticket) Authenticated() {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+
+	if t.authenticated || t.released {
+		return
+	}
+	t.authenticated = true
+	t.c.unauth--
+}
+
+// Release frees the connection's slot.
+func (t *Ticket) Release() {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+
+	if t.released {
+		return
+	}
+	t.released = true
+	t.c.total--
+	if !t.authenticated {
+		t.c.unauth--
+	}
+	if t.c.perIP[t.IP]--; t.c.perIP[t.IP] <= 0 {
+		delete(t.c.perIP, t.IP)
+	}
+}
+
+// ParsePrefixes parses CIDR prefixes; bare addresses become single-address prefixes.
+func ParsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		if !strings.Contains(v, "/") {
+			addr, err := netip.ParseAddr(v)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, p.Masked())
+	}
+	return prefixes, nil
+}
@@ -0,0 +1,136 @@
+package admission
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest(remoteAddr string, headers map[string]string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	r.RemoteAddr = remoteAddr
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	return r
+}
+
+func TestCheckOrigin(t *testing.T) {
+	open := New(Config{})
+	assert.True(t, open.CheckOrigin(newRequest("1.2.3.4:1000", map[string]string{"Origin": "https://evil.example"})))
+
+	c := New(Config{AllowedOrigins: []string{"https://game.example.com/", "https://admin.example.com", "not an origin"}})
+	tests := []struct {
+		name   string
+		origin string
+		want   bool
+	}{
+		{"allowed", "https://game.example.com", true},
+		{"case insensitive", "HTTPS://Game.Example.com", true},
+		{"trailing slash", "https://game.example.com/", true},
+		{"trailing slash on request only", "https://admin.example.com/", true},
+		{"opaque origin", "null", false},
+		{"native client without origin", "", true},
+		{"other origin", "https://evil.example", false},
+		{"other scheme", "http://game.example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.CheckOrigin(newRequest("1.2.3.4:1000", map[string]string{"Origin": tt.origin})))
+		})
+	}
+
+	invalid := New(Config{AllowedOrigins: []string{"game.example.com"}})
+	assert.False(t, invalid.CheckOrigin(newRequest("1.2.3.4:1000", map[string]string{"Origin": "https://evil.example"})),
+		"an allowlist of invalid entries does not allow every origin")
+}
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParsePrefixes([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+	c := New(Config{TrustedProxies: proxies})
+
+	tests := []struct {
+		name    string
+		remote  string
+		headers map[string]string
+		want    string
+	}{
+		{"direct", "203.0.113.7:5555", nil, "203.0.113.7"},
+		{"untrusted peer spoofing", "203.0.113.7:5555", map[string]string{"X-Forwarded-For": "1.1.1.1"}, "203.0.113.7"},
+		{"trusted proxy", "10.0.0.2:80", map[string]string{"X-Forwarded-For": "198.51.100.9"}, "198.51.100.9"},
+		{"proxy chain", "10.0.0.2:80", map[string]string{"X-Forwarded-For": "1.1.1.1, 198.51.100.9, 192.168.1.1"}, "198.51.100.9"},
+		{"real ip header", "192.168.1.1:80", map[string]string{"X-Real-IP": "198.51.100.10"}, "198.51.100.10"},
+		{"unparsable hop", "10.0.0.2:80", map[string]string{"X-Forwarded-For": "198.51.100.9, bogus", "X-Real-IP": "198.51.100.10"}, "10.0.0.2"},
+		{"proxy without headers", "10.0.0.2:80", nil, "10.0.0.2"},
+		{"ipv4-mapped ipv6", "[::ffff:203.0.113.7]:5555", nil, "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.ClientIP(newRequest(tt.remote, tt.headers)))
+		})
+	}
+}
+
+func TestAdmit_Limits(t *testing.T) {
+	c := New(Config{MaxConnections: 3, MaxPerIP: 2, MaxUnauthenticated: 2})
+	a := newRequest("203.0.113.1:1", nil)
+	b := newRequest("203.0.113.2:1", nil)
+
+	t1, err := c.Admit(a)
+	require.NoError(t, err)
+	t2, err := c.Admit(a)
+	require.NoError(t, err)
+
+	_, err = c.Admit(a)
+	assert.ErrorIs(t, err, ErrTooManyFromIP)
+	assert.Equal(t, http.StatusTooManyRequests, StatusCode(err))
+
+	_, err = c.Admit(b)
+	assert.ErrorIs(t, err, ErrTooManyUnauthenticated, "two connections are still waiting to authenticate")
+
+	t1.Authenticated()
+	t1.Authenticated()
+	t3, err := c.Admit(b)
+	require.NoError(t, err)
+
+	t3.Authenticated()
+	_, err = c.Admit(b)
+	assert.ErrorIs(t, err, ErrTooManyConnections)
+	assert.Equal(t, http.StatusServiceUnavailable, StatusCode(err))
+
+	t2.Release()
+	t2.Release()
+	_, err = c.Admit(b)
+	require.NoError(t, err)
+
+	stats := c.Stats()
+	assert.Equal(t, 3, stats.Connections)
+	assert.Equal(t, 1, stats.Unauthenticated)
+	assert.Equal(t, map[string]int{"max_per_ip": 1, "max_unauthenticated": 1, "max_connections": 1}, stats.Rejected)
+}
+
+func TestAdmit_Origin(t *testing.T) {
+	c := New(Config{AllowedOrigins: []string{"https://game.example.com"}})
+	_, err := c.Admit(newRequest("203.0.113.1:1", map[string]string{"Origin": "https://evil.example"}))
+	assert.ErrorIs(t, err, ErrOriginNotAllowed)
+	assert.Equal(t, http.StatusForbidden, StatusCode(err))
+	assert.Zero(t, c.Stats().Connections)
+}
+
+func TestParsePrefixes(t *testing.T) {
+	prefixes, err := ParsePrefixes([]string{"10.1.2.3/8", "::1", "127.0.0.1"})
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("::1/128"),
+		netip.MustParsePrefix("127.0.0.1/32"),
+	}, prefixes)
+
+	_, err = ParsePrefixes([]string{"not-an-ip"})
+	assert.Error(t, err)
+}
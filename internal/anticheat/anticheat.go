@@ -0,0 +1,190 @@
+// Package anticheat scores suspicious player behaviour and escalates from
+// correcting a player to kicking them and flagging the account for review.
+package anticheat
+
+import (
+	"sync"
+	"time"
+)
+
+// Kind identifies a type of violation.
+type Kind string
+
+const (
+	KindSpeed           Kind = "speed"            // moved faster than allowed
+	KindTeleport        Kind = "teleport"         // moved through an obstacle
+	KindArrestProximity Kind = "arrest_proximity" // police closer to a thief than bodies allow
+	KindFlood           Kind = "flood"            // exceeded the connection's message rate limit
+)
+
+// Action is the response to a violation, escalating with the player's score.
+type Action string
+
+const (
+	// ActionRubberBand rejects the offending input; moves are sent back to
+	// the last valid position.
+	ActionRubberBand Action = "rubber_band"
+	// ActionKick disconnects the player.
+	ActionKick Action = "kick"
+	// ActionFlag disconnects the player and flags the account for review.
+	ActionFlag Action = "flag"
+)
+
+// AuditActor is the actor recorded in the moderation audit log for
+// anti-cheat actions.
+const AuditActor = "anticheat"
+
+// HistorySize bounds the recent events kept per account for review.
+const HistorySize = 20
+
+// Config tunes violation scoring.
+type Config struct {
+	// Weights is the score each kind of violation adds.
+	Weights map[Kind]float64
+	// DecayPerSecond is how fast a score falls back towards zero.
+	DecayPerSecond float64
+	// KickScore and FlagScore are the scores at which players are kicked
+	// and their accounts flagged.
+	KickScore float64
+	FlagScore float64
+}
+
+// DefaultConfig returns the default scoring. A speedhacker is kicked after
+// five rejected moves in quick succession and flagged if they keep going
+// after reconnecting.
+func DefaultConfig() Config {
+	return Config{
+		Weights: map[Kind]float64{
+			KindSpeed:           10,
+			KindTeleport:        25,
+			KindArrestProximity: 20,
+			KindFlood:           15,
+		},
+		DecayPerSecond: 1,
+		KickScore:      45,
+		FlagScore:      95,
+	}
+}
+
+// Violation describes one suspicious input.
+type Violation struct {
+	AccountID string `json:"account_id"`
+	PlayerID  string `json:"player_id,omitempty"`
+	Room      string `json:"room,omitempty"`
+	Kind      Kind   `json:"kind"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// Event is a scored violation and the action taken for it.
+type Event struct {
+	Violation
+	Score  float64   `json:"score"` // account score after this violation
+	Action Action    `json:"action"`
+	Time   time.Time `json:"time"`
+}
+
+// Monitor keeps violation scores per account. Scores outlive connections,
+// so reconnecting does not reset them.
+type Monitor struct {
+	cfg     Config
+	records map[string]*record // account ID -> score
+	now     func() time.Time
+	mu      sync.Mutex
+}
+
+type record struct {
+	score   float64
+	updated time.Time
+	flagged bool
+	history []Event
+}
+
+// NewMonitor creates a monitor with the given scoring.
+func NewMonitor(cfg Config) *Monitor {
+	return &Monitor{
+		cfg:     cfg,
+		records: make(map[string]*record),
+		now:     time.Now,
+	}
+}
+
+// Record scores a violation and returns the action to take. Each account
+// is flagged at most once until its score decays back to zero.
+func (m *Monitor) Record(v Violation) Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	rec := m.records[v.AccountID]
+	if rec == nil {
+		m.prune(now)
+		rec = &record{updated: now}
+		m.records[v.AccountID] = rec
+	}
+	rec.decay(now, m.cfg.DecayPerSecond)
+	if rec.score == 0 {
+		rec.flagged = false
+	}
+	rec.score += m.cfg.Weights[v.Kind]
+
+	action := ActionRubberBand
+	switch {
+	case rec.score >= m.cfg.FlagScore && !rec.flagged:
+		action = ActionFlag
+		rec.flagged = true
+	case rec.score >= m.cfg.KickScore:
+		action = ActionKick
+	}
+
+	ev := Event{Violation: v, Score: rec.score, Action: action, Time: now}
+	if len(rec.history) == HistorySize {
+		copy(rec.history, rec.history[1:])
+		rec.history = rec.history[:HistorySize-1]
+	}
+	rec.history = append(rec.history, ev)
+	return ev
+}
+
+// Score returns an account's current score.
+func (m *Monitor) Score(accountID string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec := m.records[accountID]
+	if rec == nil {
+		return 0
+	}
+	rec.decay(m.now(), m.cfg.DecayPerSecond)
+	return rec.score
+}
+
+// History returns an account's recent events, oldest first.
+func (m *Monitor) History(accountID string) []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec := m.records[accountID]
+	if rec == nil {
+		return nil
+	}
+	return append([]Event(nil), rec.history...)
+}
+
+// prune drops accounts whose score has decayed to zero. Caller must hold m.mu.
+func (m *Monitor) prune(now time.Time) {
+	for id, rec := range m.records {
+		rec.decay(now, m.cfg.DecayPerSecond)
+		if rec.score == 0 {
+			delete(m.records, id)
+		}
+	}
+}
+
+func (r *record) decay(now time.Time, perSecond float64) {
+	elapsed := now.Sub(r.updated).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	r.score = max(0, r.score-elapsed*perSecond)
+	r.updated = now
+}
@@ -0,0 +1,78 @@
+package anticheat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMonitor() (*Monitor, *time.Time) {
+	m := NewMonitor(DefaultConfig())
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestMonitor_Escalation(t *testing.T) {
+	m, _ := newTestMonitor()
+	speed := Violation{AccountID: "acc-1", Kind: KindSpeed}
+
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, ActionRubberBand, m.Record(speed).Action)
+	}
+	ev := m.Record(speed)
+	assert.Equal(t, ActionKick, ev.Action)
+	assert.Equal(t, 50.0, ev.Score)
+
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, ActionKick, m.Record(speed).Action)
+	}
+	assert.Equal(t, ActionFlag, m.Record(speed).Action)
+	assert.Equal(t, ActionKick, m.Record(speed).Action, "accounts are flagged once")
+
+	assert.Zero(t, m.Score("acc-2"), "scores are per account")
+}
+
+func TestMonitor_Decay(t *testing.T) {
+	m, now := newTestMonitor()
+	m.Record(Violation{AccountID: "acc-1", Kind: KindTeleport})
+	assert.Equal(t, 25.0, m.Score("acc-1"))
+
+	*now = now.Add(10 * time.Second)
+	assert.Equal(t, 15.0, m.Score("acc-1"))
+
+	*now = now.Add(time.Minute)
+	assert.Zero(t, m.Score("acc-1"))
+
+	// Decayed accounts are pruned when new ones are tracked
+	m.Record(Violation{AccountID: "acc-2", Kind: KindSpeed})
+	assert.Nil(t, m.History("acc-1"))
+	assert.Len(t, m.History("acc-2"), 1)
+}
+
+func TestMonitor_FlagAgainAfterDecay(t *testing.T) {
+	m, now := newTestMonitor()
+	teleport := Violation{AccountID: "acc-1", Kind: KindTeleport}
+	for i := 0; i < 3; i++ {
+		m.Record(teleport)
+	}
+	require.Equal(t, ActionFlag, m.Record(teleport).Action)
+
+	*now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		m.Record(teleport)
+	}
+	assert.Equal(t, ActionFlag, m.Record(teleport).Action)
+}
+
+func TestMonitor_HistoryIsBounded(t *testing.T) {
+	m, _ := newTestMonitor()
+	for i := 0; i < HistorySize+5; i++ {
+		m.Record(Violation{AccountID: "acc-1", Kind: KindFlood})
+	}
+	history := m.History("acc-1")
+	require.Len(t, history, HistorySize)
+	assert.Equal(t, ActionKick, history[HistorySize-1].Action)
+}
@@ -0,0 +1,340 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AppleIssuer is the iss claim of Sign in with Apple identity tokens.
+	AppleIssuer = "https://appleid.apple.com"
+	// AppleJWKSURL serves Apple's current identity token signing keys.
+	AppleJWKSURL = "https://appleid.apple.com/auth/keys"
+
+	jwksCacheTTL  = 24 * time.Hour
+	maxJWKSSize   = 64 * 1024 // 64KB
+	tokenLeeway   = time.Minute
+	jwksFetchWait = 10 * time.Second
+	// jwksMinRefresh spaces out refetches, so tokens with made-up key IDs
+	// cannot make the server fetch the JWKS on every attempt.
+	jwksMinRefresh = time.Minute
+)
+
+// KeySet resolves the RSA public key a JWT was signed with.
+type KeySet interface {
+	Key(ctx context.Context, kid string) (*rsa.PublicKey, error)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// parseJWKS decodes the RSA keys of a JSON Web Key Set by key ID.
+func parseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus for key %q: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent for key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// StaticKeySet is a fixed set of keys, such as a local JWKS file.
+type StaticKeySet map[string]*rsa.PublicKey
+
+// LoadJWKSFile reads a JWKS document from disk.
+func LoadJWKSFile(path string) (StaticKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	return StaticKeySet(keys), nil
+}
+
+// Key implements KeySet.
+func (s StaticKeySet) Key(_ context.Context, kid string) (*rsa.PublicKey, error) {
+	key, ok := s[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	}
+	return key, nil
+}
+
+// RemoteKeySet fetches a JWKS over HTTP and caches it. An unknown key ID
+// triggers a refetch so rotated keys are picked up before the cache expires,
+// but at most once per jwksMinRefresh; until then unknown key IDs fail
+// without a fetch and known keys are served even if the cache has expired.
+type RemoteKeySet struct {
+	url        string
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	expiresAt time.Time
+	lastFetch time.Time
+	fetchErr  error         // error of the last fetch, nil if it succeeded
+	fetching  chan struct{} // closed when the fetch in flight ends, nil if none
+}
+
+// NewRemoteKeySet creates a key set backed by the JWKS at url.
+func NewRemoteKeySet(url string) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:        url,
+		httpClient: &http.Client{Timeout: jwksFetchWait},
+		now:        time.Now,
+	}
+}
+
+// Key implements KeySet.
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	if key, ok := s.keys[kid]; ok && s.now().Before(s.expiresAt) {
+		s.mu.Unlock()
+		return key, nil
+	}
+	s.mu.Unlock()
+
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if s.fetchErr != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", s.fetchErr)
+	}
+	return nil, fmt.Errorf("unknown key ID %q", kid)
+}
+
+// refresh refetches the key set unless it was fetched within jwksMinRefresh,
+// or waits for a fetch already in flight. The lock is not held during the
+// fetch, so lookups of cached keys never wait on the network.
+func (s *RemoteKeySet) refresh(ctx context.Context) error {
+	s.mu.Lock()
+	if done := s.fetching; done != nil {
+		s.mu.Unlock()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if !s.lastFetch.IsZero() && s.now().Sub(s.lastFetch) < jwksMinRefresh {
+		s.mu.Unlock()
+		return nil
+	}
+	done := make(chan struct{})
+	s.fetching = done
+	s.lastFetch = s.now()
+	s.mu.Unlock()
+
+	keys, err := s.fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.keys = keys
+		s.expiresAt = s.now().Add(jwksCacheTTL)
+	}
+	s.fetchErr = err
+	s.fetching = nil
+	close(done)
+	return nil
+}
+
+// fetch downloads and parses the key set.
+func (s *RemoteKeySet) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+	if err != nil {
+		return nil, err
+	}
+	return parseJWKS(data)
+}
+
+// AppleClaims are the identity token claims the server relies on.
+type AppleClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	IssuedAt  int64    `json:"iat"`
+}
+
+// audience accepts both the string and array forms of the aud claim.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// AppleProvider verifies Sign in with Apple identity tokens.
+type AppleProvider struct {
+	keys      KeySet
+	audiences map[string]bool
+	now       func() time.Time
+}
+
+// NewAppleProvider creates an Apple provider that accepts tokens issued for
+// any of the given audiences (app bundle or service IDs). An empty list
+// accepts no tokens.
+func NewAppleProvider(keys KeySet, audiences []string) *AppleProvider {
+	allowed := make(map[string]bool, len(audiences))
+	for _, a := range audiences {
+		allowed[a] = true
+	}
+	return &AppleProvider{keys: keys, audiences: allowed, now: time.Now}
+}
+
+// Name implements AuthProvider.
+func (p *AppleProvider) Name() string { return ProviderApple }
+
+// Authenticate implements AuthProvider.
+func (p *AppleProvider) Authenticate(ctx context.Context, data json.RawMessage) (*Identity, error) {
+	var req struct {
+		IdentityToken string `json:"identity_token"`
+		Nickname      string `json:"nickname"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil || req.IdentityToken == "" {
+		return nil, ErrInvalidRequest
+	}
+	claims, err := p.Verify(ctx, req.IdentityToken)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{Provider: ProviderApple, Subject: claims.Subject, Nickname: req.Nickname}, nil
+}
+
+// Verify checks an identity token's signature and claims.
+func (p *AppleProvider) Verify(ctx context.Context, token string) (*AppleClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported algorithm %q", header.Alg)
+	}
+
+	key, err := p.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature); err != nil {
+		return nil, fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	var claims AppleClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+	if err := p.validateClaims(&claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+func (p *AppleProvider) validateClaims(c *AppleClaims) error {
+	if c.Issuer != AppleIssuer {
+		return fmt.Errorf("unexpected issuer %q", c.Issuer)
+	}
+	if c.Subject == "" {
+		return errors.New("missing subject")
+	}
+	if p.now().After(time.Unix(c.ExpiresAt, 0).Add(tokenLeeway)) {
+		return errors.New("token expired")
+	}
+	for _, a := range c.Audience {
+		if p.audiences[a] {
+			return nil
+		}
+	}
+	return fmt.Errorf("audience %v is not allowed", []string(c.Audience))
+}
+
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,228 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestJWKS stores key's public half as a single-key JWKS file.
+func writeTestJWKS(t *testing.T, kid string, key *rsa.PrivateKey) string {
+	t.Helper()
+	set := map[string]any{"keys": []jwk{{
+		Kty: "RSA",
+		Kid: kid,
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func signTestToken(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hash := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss": AppleIssuer,
+		"sub": "001234.abcdef",
+		"aud": "com.example.game",
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func setupAppleProvider(t *testing.T) (*AppleProvider, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keys, err := LoadJWKSFile(writeTestJWKS(t, "test-kid", key))
+	require.NoError(t, err)
+	return NewAppleProvider(keys, []string{"com.example.game"}), key
+}
+
+func TestAppleProvider_ValidToken(t *testing.T) {
+	p, key := setupAppleProvider(t)
+	token := signTestToken(t, key, "test-kid", validClaims())
+
+	data, _ := json.Marshal(map[string]string{"identity_token": token, "nickname": "사과"})
+	id, err := p.Authenticate(context.Background(), data)
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{Provider: ProviderApple, Subject: "001234.abcdef", Nickname: "사과"}, id)
+}
+
+func TestAppleProvider_RejectsBadTokens(t *testing.T) {
+	p, key := setupAppleProvider(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	with := func(k string, v any) map[string]any {
+		c := validClaims()
+		c[k] = v
+		return c
+	}
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"wrong key", signTestToken(t, otherKey, "test-kid", validClaims())},
+		{"unknown kid", signTestToken(t, key, "other-kid", validClaims())},
+		{"wrong issuer", signTestToken(t, key, "test-kid", with("iss", "https://evil.example"))},
+		{"wrong audience", signTestToken(t, key, "test-kid", with("aud", []string{"com.other.app"}))},
+		{"expired", signTestToken(t, key, "test-kid", with("exp", time.Now().Add(-time.Hour).Unix()))},
+		{"no subject", signTestToken(t, key, "test-kid", with("sub", ""))},
+		{"malformed", "not-a-jwt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.Verify(context.Background(), tt.token)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAppleProvider_NoAudiencesAcceptsNothing(t *testing.T) {
+	p, key := setupAppleProvider(t)
+	p = NewAppleProvider(p.keys, nil)
+	_, err := p.Verify(context.Background(), signTestToken(t, key, "test-kid", validClaims()))
+	assert.ErrorContains(t, err, "audience")
+}
+
+func TestRemoteKeySet_FetchesAndCaches(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks, err := os.ReadFile(writeTestJWKS(t, "remote-kid", key))
+	require.NoError(t, err)
+
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+		w.Write(jwks)
+	}))
+	defer srv.Close()
+
+	p := NewAppleProvider(NewRemoteKeySet(srv.URL), []string{"com.example.game"})
+	token := signTestToken(t, key, "remote-kid", validClaims())
+	for i := 0; i < 2; i++ {
+		_, err := p.Verify(context.Background(), token)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, fetches)
+}
+
+func TestRemoteKeySet_ThrottlesUnknownKeyRefetches(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks, err := os.ReadFile(writeTestJWKS(t, "remote-kid", key))
+	require.NoError(t, err)
+
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		w.Write(jwks)
+	}))
+	defer srv.Close()
+
+	s := NewRemoteKeySet(srv.URL)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err = s.Key(ctx, "remote-kid")
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		_, err = s.Key(ctx, "made-up-kid")
+		assert.ErrorContains(t, err, "unknown key ID")
+	}
+	assert.Equal(t, int32(1), fetches.Load(), "unknown key IDs do not refetch within the minimum interval")
+
+	now = now.Add(jwksMinRefresh)
+	_, err = s.Key(ctx, "made-up-kid")
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// Known keys outlive the cache while refetches are throttled
+	now = now.Add(jwksCacheTTL)
+	_, err = s.Key(ctx, "remote-kid")
+	require.NoError(t, err)
+	_, err = s.Key(ctx, "remote-kid")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), fetches.Load())
+}
+
+func TestRemoteKeySet_CachedKeysDoNotWaitForFetch(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks, err := os.ReadFile(writeTestJWKS(t, "remote-kid", key))
+	require.NoError(t, err)
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		w.Write(jwks)
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	s := NewRemoteKeySet(srv.URL)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+	_, err = s.Key(ctx, "remote-kid")
+	require.NoError(t, err)
+
+	s.mu.Lock()
+	s.lastFetch = s.lastFetch.Add(-jwksMinRefresh)
+	s.mu.Unlock()
+	go s.Key(ctx, "made-up-kid")
+	require.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, 10*time.Millisecond)
+
+	_, err = s.Key(ctx, "remote-kid")
+	assert.NoError(t, err, "served from the cache while the refetch is in flight")
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(NewGuestProvider(nil))
+	assert.Equal(t, []string{ProviderGuest}, r.Names())
+
+	id, err := r.Authenticate(context.Background(), ProviderGuest, json.RawMessage(`{"nickname":"게스트"}`))
+	require.NoError(t, err)
+	assert.True(t, id.Guest)
+	assert.Empty(t, id.Subject)
+
+	_, err = r.Authenticate(context.Background(), ProviderGuest, json.RawMessage(`{}`))
+	assert.ErrorIs(t, err, ErrNicknameRequired)
+
+	_, err = r.Authenticate(context.Background(), "unknown", nil)
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+)
+
+// ErrInvalidCredential is returned for guest credentials that fail verification.
+var ErrInvalidCredential = errors.New("invalid guest credential")
+
+// GuestCredentials issues and verifies device credentials that let a guest
+// sign back in to the same account. A credential is the account ID and an
+// HMAC of it under the server's key, so no per-guest secret is stored.
+type GuestCredentials struct {
+	key []byte
+}
+
+// NewGuestCredentials creates a signer with the given key. An empty key
+// generates a random one, which invalidates credentials on restart.
+func NewGuestCredentials(key []byte) *GuestCredentials {
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic("auth: failed to generate guest credential key: " + err.Error())
+		}
+	}
+	return &GuestCredentials{key: key}
+}
+
+// Issue returns the credential for a guest account.
+func (g *GuestCredentials) Issue(accountID string) string {
+	return accountID + "." + base64.RawURLEncoding.EncodeToString(g.sign(accountID))
+}
+
+// Verify checks a credential and returns the account ID it was issued for.
+func (g *GuestCredentials) Verify(credential string) (string, error) {
+	accountID, sig, ok := strings.Cut(credential, ".")
+	if !ok || accountID == "" {
+		return "", ErrInvalidCredential
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, g.sign(accountID)) {
+		return "", ErrInvalidCredential
+	}
+	return accountID, nil
+}
+
+func (g *GuestCredentials) sign(accountID string) []byte {
+	h := hmac.New(sha256.New, g.key)
+	h.Write([]byte("guest:" + accountID))
+	return h.Sum(nil)
+}
+
+// GuestProvider creates guest identities. A valid device credential
+// identifies a returning guest; otherwise a nickname starts a new guest.
+type GuestProvider struct {
+	credentials *GuestCredentials
+}
+
+// NewGuestProvider creates a guest provider. A nil credentials signer
+// disables returning guests.
+func NewGuestProvider(credentials *GuestCredentials) *GuestProvider {
+	return &GuestProvider{credentials: credentials}
+}
+
+// Name implements AuthProvider.
+func (p *GuestProvider) Name() string { return ProviderGuest }
+
+// Authenticate implements AuthProvider.
+func (p *GuestProvider) Authenticate(_ context.Context, data json.RawMessage) (*Identity, error) {
+	var req struct {
+		Nickname   string `json:"nickname"`
+		Credential string `json:"credential"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, ErrInvalidRequest
+	}
+
+	id := &Identity{Provider: ProviderGuest, Nickname: req.Nickname, Guest: true}
+	if req.Credential != "" && p.credentials != nil {
+		accountID, err := p.credentials.Verify(req.Credential)
+		if err == nil {
+			id.Subject = accountID
+			return id, nil
+		}
+		slog.Warn("rejected guest credential, starting a new guest", "error", err)
+	}
+	if req.Nickname == "" {
+		return nil, ErrNicknameRequired
+	}
+	return id, nil
+}
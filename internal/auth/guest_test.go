@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuestCredentials_RoundTrip(t *testing.T) {
+	g := NewGuestCredentials([]byte("key"))
+	id, err := g.Verify(g.Issue("acc-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "acc-1", id)
+}
+
+func TestGuestCredentials_Rejects(t *testing.T) {
+	g := NewGuestCredentials([]byte("key"))
+	other := NewGuestCredentials([]byte("other"))
+
+	for _, cred := range []string{"", "acc-1", "acc-1.", ".sig", "acc-2" + g.Issue("acc-1")[len("acc-1"):], other.Issue("acc-1")} {
+		_, err := g.Verify(cred)
+		assert.ErrorIs(t, err, ErrInvalidCredential, cred)
+	}
+}
+
+func TestGuestProvider(t *testing.T) {
+	g := NewGuestCredentials(nil)
+	p := NewGuestProvider(g)
+	auth := func(v map[string]string) (*Identity, error) {
+		data, _ := json.Marshal(v)
+		return p.Authenticate(context.Background(), data)
+	}
+
+	id, err := auth(map[string]string{"credential": g.Issue("acc-1")})
+	require.NoError(t, err)
+	assert.Equal(t, "acc-1", id.Subject)
+	assert.True(t, id.Guest)
+
+	id, err = auth(map[string]string{"credential": "acc-1.bad", "nickname": "손님"})
+	require.NoError(t, err)
+	assert.Empty(t, id.Subject)
+
+	_, err = auth(map[string]string{"credential": "acc-1.bad"})
+	assert.ErrorIs(t, err, ErrNicknameRequired)
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Provider names used as the authenticate message's method.
+const (
+	ProviderGameCenter = "game_center"
+	ProviderGuest      = "guest"
+	ProviderApple      = "apple"
+)
+
+var (
+	ErrUnknownProvider  = errors.New("unknown auth method")
+	ErrNicknameRequired = errors.New("nickname is required")
+	ErrInvalidRequest   = errors.New("invalid credential data")
+)
+
+// Identity is an external identity proven by an AuthProvider.
+type Identity struct {
+	Provider string
+	// Subject is the provider's stable user ID. Empty means the identity
+	// cannot be recognized again, so a fresh account is created each time.
+	Subject string
+	// Nickname is the client-requested nickname, used when creating an account.
+	Nickname string
+	// Guest marks identities that map to guest accounts.
+	Guest bool
+	// AccountID is set instead of Subject by providers that already know the
+	// internal account, such as session tokens.
+	AccountID string
+}
+
+// AuthProvider verifies one kind of credential.
+type AuthProvider interface {
+	// Name returns the method clients use to select this provider.
+	Name() string
+	// Authenticate verifies the credential in the authenticate message's
+	// data and returns the identity it proves.
+	Authenticate(ctx context.Context, data json.RawMessage) (*Identity, error)
+}
+
+// Registry holds the available auth providers by name.
+type Registry struct {
+	providers map[string]AuthProvider
+}
+
+// NewRegistry creates a registry with the given providers.
+func NewRegistry(providers ...AuthProvider) *Registry {
+	r := &Registry{providers: make(map[string]AuthProvider, len(providers))}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+// Register adds a provider, replacing any provider with the same name.
+func (r *Registry) Register(p AuthProvider) {
+	r.providers[p.Name()] = p
+}
+
+// Authenticate verifies a credential with the named provider.
+func (r *Registry) Authenticate(ctx context.Context, method string, data json.RawMessage) (*Identity, error) {
+	p, ok := r.providers[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, method)
+	}
+	return p.Authenticate(ctx, data)
+}
+
+// Names returns the registered provider names in sorted order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GameCenterProvider authenticates Game Center identity signatures.
+type GameCenterProvider struct {
+	verifier *GameCenterVerifier
+}
+
+// NewGameCenterProvider creates a Game Center provider using verifier.
+func NewGameCenterProvider(verifier *GameCenterVerifier) *GameCenterProvider {
+	return &GameCenterProvider{verifier: verifier}
+}
+
+// Name implements AuthProvider.
+func (p *GameCenterProvider) Name() string { return ProviderGameCenter }
+
+// Authenticate implements AuthProvider.
+func (p *GameCenterProvider) Authenticate(ctx context.Context, data json.RawMessage) (*Identity, error) {
+	var req struct {
+		GameCenterCredential
+		Nickname string `json:"nickname"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, ErrInvalidRequest
+	}
+	if err := p.verifier.Verify(ctx, &req.GameCenterCredential); err != nil {
+		return nil, err
+	}
+	return &Identity{Provider: ProviderGameCenter, Subject: req.PlayerID, Nickname: req.Nickname}, nil
+}
@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProviderToken is the auth method for reconnecting with a session token.
+const ProviderToken = "token"
+
+// DefaultSessionTTL is how long a session token stays valid.
+const DefaultSessionTTL = time.Hour
+
+var (
+	ErrInvalidToken = errors.New("invalid session token")
+	ErrTokenExpired = errors.New("session token expired")
+	ErrTokenRevoked = errors.New("session token revoked")
+)
+
+// SessionClaims is the payload of a session token.
+type SessionClaims struct {
+	ID        string `json:"jti"`
+	AccountID string `json:"sub"`
+	IssuedAt  int64  `json:"iat"` // Unix nanoseconds, to order issues against revocations
+	ExpiresAt int64  `json:"exp"` // Unix milliseconds
+}
+
+// Expiry returns when the token expires.
+func (c *SessionClaims) Expiry() time.Time {
+	return time.UnixMilli(c.ExpiresAt)
+}
+
+// Revocation is a persisted session token revocation. Either TokenID or
+// AccountID is set.
+type Revocation struct {
+	TokenID   string    // a single token
+	AccountID string    // every token issued to the account before At
+	At        time.Time // when the revocation was made
+	ExpiresAt time.Time // when every revoked token has expired on its own
+}
+
+// RevocationStore persists revocations so they survive a restart.
+type RevocationStore interface {
+	// SaveRevocation stores a revocation.
+	SaveRevocation(ctx context.Context, r Revocation) error
+	// LoadRevocations returns the revocations that have not expired at now.
+	LoadRevocations(ctx context.Context, now time.Time) ([]Revocation, error)
+}
+
+// SessionTokens issues short-lived HMAC-signed session tokens so clients can
+// reconnect, and call HTTP APIs, without repeating full sign-in. Revocations
+// are checked in memory until the revoked tokens would have expired anyway,
+// and written through to a RevocationStore when one is attached.
+type SessionTokens struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	store     RevocationStore      // nil keeps revocations in memory only
+	revoked   map[string]time.Time // token ID -> expiry
+	notBefore map[string]time.Time // account ID -> tokens issued before here are revoked
+}
+
+// NewSessionTokens creates a token signer with the given key and lifetime.
+// An empty key generates a random one, which invalidates tokens on restart.
+func NewSessionTokens(key []byte, ttl time.Duration) *SessionTokens {
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic("auth: failed to generate session token key: " + err.Error())
+		}
+	}
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+	return &SessionTokens{
+		key:       key,
+		ttl:       ttl,
+		now:       time.Now,
+		revoked:   make(map[string]time.Time),
+		notBefore: make(map[string]time.Time),
+	}
+}
+
+// Persist loads the revocations still in force from store and writes later
+// revocations through to it.
+func (s *SessionTokens) Persist(ctx context.Context, store RevocationStore) error {
+	revocations, err := store.LoadRevocations(ctx, s.now())
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store = store
+	for _, r := range revocations {
+		if r.TokenID != "" {
+			s.revoked[r.TokenID] = r.ExpiresAt
+			continue
+		}
+		if nb, ok := s.notBefore[r.AccountID]; !ok || r.At.After(nb) {
+			s.notBefore[r.AccountID] = r.At
+		}
+	}
+	return nil
+}
+
+// Issue creates a session token for an account.
+func (s *SessionTokens) Issue(accountID string) (string, *SessionClaims) {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	now := s.now()
+	claims := &SessionClaims{
+		ID:        base64.RawURLEncoding.EncodeToString(id),
+		AccountID: accountID,
+		IssuedAt:  now.UnixNano(),
+		ExpiresAt: now.Add(s.ttl).UnixMilli(),
+	}
+	payload, _ := json.Marshal(claims)
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + base64.RawURLEncoding.EncodeToString(s.sign(body)), claims
+}
+
+// Verify checks a token's signature, expiry and revocation.
+func (s *SessionTokens) Verify(token string) (*SessionClaims, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, s.sign(body)) {
+		return nil, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims SessionClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ID == "" || claims.AccountID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	if s.now().UnixMilli() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.revoked[claims.ID]; ok {
+		return nil, ErrTokenRevoked
+	}
+	if nb, ok := s.notBefore[claims.AccountID]; ok && claims.IssuedAt < nb.UnixNano() {
+		return nil, ErrTokenRevoked
+	}
+	return &claims, nil
+}
+
+// Revoke invalidates a single token. The revocation applies immediately;
+// an error means it could not be persisted.
+func (s *SessionTokens) Revoke(ctx context.Context, claims *SessionClaims) error {
+	s.mu.Lock()
+	s.prune()
+	s.revoked[claims.ID] = claims.Expiry()
+	store := s.store
+	s.mu.Unlock()
+
+	if store == nil {
+		return nil
+	}
+	return store.SaveRevocation(ctx, Revocation{TokenID: claims.ID, At: s.now(), ExpiresAt: claims.Expiry()})
+}
+
+// RevokeAccount invalidates every token issued to an account so far.
+// Tokens issued afterwards stay valid. The revocation applies immediately;
+// an error means it could not be persisted.
+func (s *SessionTokens) RevokeAccount(ctx context.Context, accountID string) error {
+	now := s.now()
+	s.mu.Lock()
+	s.prune()
+	s.notBefore[accountID] = now
+	store := s.store
+	s.mu.Unlock()
+
+	if store == nil {
+		return nil
+	}
+	return store.SaveRevocation(ctx, Revocation{AccountID: accountID, At: now, ExpiresAt: now.Add(s.ttl)})
+}
+
+// prune drops revocations for tokens that have expired on their own.
+// Caller must hold s.mu.
+func (s *SessionTokens) prune() {
+	now := s.now()
+	for id, exp := range s.revoked {
+		if now.After(exp) {
+			delete(s.revoked, id)
+		}
+	}
+	for id, nb := range s.notBefore {
+		if now.After(nb.Add(s.ttl)) {
+			delete(s.notBefore, id)
+		}
+	}
+}
+
+func (s *SessionTokens) sign(body string) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte("session:" + body))
+	return h.Sum(nil)
+}
+
+// TokenProvider signs clients back in with a session token.
+type TokenProvider struct {
+	tokens *SessionTokens
+}
+
+// NewTokenProvider creates a token provider.
+func NewTokenProvider(tokens *SessionTokens) *TokenProvider {
+	return &TokenProvider{tokens: tokens}
+}
+
+// Name implements AuthProvider.
+func (p *TokenProvider) Name() string { return ProviderToken }
+
+// Authenticate implements AuthProvider.
+func (p *TokenProvider) Authenticate(_ context.Context, data json.RawMessage) (*Identity, error) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil || req.Token == "" {
+		return nil, ErrInvalidRequest
+	}
+	claims, err := p.tokens.Verify(req.Token)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{Provider: ProviderToken, AccountID: claims.AccountID}, nil
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionTokens_IssueAndVerify(t *testing.T) {
+	s := NewSessionTokens([]byte("key"), time.Minute)
+	token, issued := s.Issue("acc-1")
+
+	claims, err := s.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "acc-1", claims.AccountID)
+	assert.Equal(t, issued.ID, claims.ID)
+
+	_, err = NewSessionTokens([]byte("other"), time.Minute).Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = s.Verify(token + "x")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = s.Verify("garbage")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestSessionTokens_Expiry(t *testing.T) {
+	s := NewSessionTokens([]byte("key"), time.Minute)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	token, _ := s.Issue("acc-1")
+
+	now = now.Add(time.Minute)
+	_, err := s.Verify(token)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestSessionTokens_Revoke(t *testing.T) {
+	s := NewSessionTokens([]byte("key"), time.Minute)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	a, claimsA := s.Issue("acc-1")
+	b, _ := s.Issue("acc-1")
+	require.NoError(t, s.Revoke(ctx, claimsA))
+	_, err := s.Verify(a)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+	_, err = s.Verify(b)
+	assert.NoError(t, err)
+
+	now = now.Add(time.Microsecond)
+	require.NoError(t, s.RevokeAccount(ctx, "acc-1"))
+	_, err = s.Verify(b)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+
+	// A re-login within the same millisecond as the revocation is not caught by it
+	now = now.Add(time.Microsecond)
+	c, _ := s.Issue("acc-1")
+	_, err = s.Verify(c)
+	assert.NoError(t, err, "tokens issued after RevokeAccount stay valid")
+}
+
+// memoryRevocations implements RevocationStore for testing.
+type memoryRevocations struct {
+	saved []Revocation
+}
+
+func (m *memoryRevocations) SaveRevocation(_ context.Context, r Revocation) error {
+	m.saved = append(m.saved, r)
+	return nil
+}
+
+func (m *memoryRevocations) LoadRevocations(_ context.Context, now time.Time) ([]Revocation, error) {
+	var live []Revocation
+	for _, r := range m.saved {
+		if now.Before(r.ExpiresAt) {
+			live = append(live, r)
+		}
+	}
+	return live, nil
+}
+
+func TestSessionTokens_PersistSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryRevocations{}
+	before := NewSessionTokens([]byte("key"), time.Minute)
+	require.NoError(t, before.Persist(ctx, store))
+
+	single, claims := before.Issue("acc-1")
+	banned, _ := before.Issue("acc-2")
+	require.NoError(t, before.Revoke(ctx, claims))
+	require.NoError(t, before.RevokeAccount(ctx, "acc-2"))
+	require.Len(t, store.saved, 2)
+
+	after := NewSessionTokens([]byte("key"), time.Minute)
+	require.NoError(t, after.Persist(ctx, store))
+	_, err := after.Verify(single)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+	_, err = after.Verify(banned)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+
+	fresh, _ := after.Issue("acc-2")
+	_, err = after.Verify(fresh)
+	assert.NoError(t, err)
+}
+
+func TestTokenProvider(t *testing.T) {
+	s := NewSessionTokens(nil, time.Minute)
+	p := NewTokenProvider(s)
+	token, _ := s.Issue("acc-1")
+
+	data, _ := json.Marshal(map[string]string{"token": token})
+	id, err := p.Authenticate(context.Background(), data)
+	require.NoError(t, err)
+	assert.Equal(t, "acc-1", id.AccountID)
+	assert.Empty(t, id.Subject)
+
+	_, err = p.Authenticate(context.Background(), json.RawMessage(`{}`))
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
@@ -93,9 +93,17 @@ func (bm *BoosterManager) Update(dt time.Duration) {
 	bm.RespawnQueue = remaining
 }
 
-// CheckPickup tests if any player picks up a booster. Returns picked up booster IDs.
-func (bm *BoosterManager) CheckPickup(players []*Player) []string {
-	var picked []string
+// Pickup records a player collecting an item.
+type Pickup struct {
+	ItemID   string
+	PlayerID string
+	X        float64
+	Y        float64
+}
+
+// CheckPickup tests if any player picks up a booster. Returns the pickups that occurred.
+func (bm *BoosterManager) CheckPickup(players []*Player) []Pickup {
+	var picked []Pickup
 	var remaining []*Booster
 
 	for _, b := range bm.Active {
@@ -108,7 +116,7 @@ func (bm *BoosterManager) CheckPickup(players []*Player) []string {
 				p.Boosted = true
 				p.BoostTimer = BoosterDuration
 				collected = true
-				picked = append(picked, b.ID)
+				picked = append(picked, Pickup{ItemID: b.ID, PlayerID: p.ID, X: b.X, Y: b.Y})
 				break
 			}
 		}
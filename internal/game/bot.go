@@ -0,0 +1,63 @@
+package game
+
+import (
+	"math"
+	"time"
+)
+
+// StepBot moves a server-controlled player for one tick of dt seconds ending at now.
+// Police chase the nearest free thief; free thieves run from the nearest police.
+// Arrested thieves wait for rescue. Like players, bots cannot walk through obstacles.
+func StepBot(bot *Player, players []*Player, objects []MapObject, dt float64, now time.Time) {
+	if bot.Role == RoleThief && bot.IsArrested() {
+		return
+	}
+
+	var target *Player
+	best := math.MaxFloat64
+	for _, p := range players {
+		if p.ID == bot.ID {
+			continue
+		}
+		switch {
+		case bot.Role == RolePolice && p.Role == RoleThief && p.IsFree():
+		case bot.Role == RoleThief && p.Role == RolePolice:
+		default:
+			continue
+		}
+		if d := Distance(bot.X, bot.Y, p.X, p.Y); d < best {
+			best = d
+			target = p
+		}
+	}
+	if target == nil || best == 0 {
+		return
+	}
+
+	speed := MoveSpeed
+	if bot.Boosted {
+		speed *= BoosterSpeedMult
+	}
+	if bot.Slowed {
+		speed *= StumbleSlowMult
+	}
+	step := speed * dt
+	if bot.Role == RolePolice {
+		// Stop on top of the target rather than overshooting it
+		step = math.Min(step, best)
+	} else {
+		step = -step
+	}
+
+	dx := (target.X - bot.X) * step / best
+	dy := (target.Y - bot.Y) * step / best
+
+	// Blocked straight on, sidestep around the obstacle or slide along an axis
+	for _, d := range [][2]float64{{dx, dy}, {-dy, dx}, {dy, -dx}, {dx, 0}, {0, dy}} {
+		x, y := ClampPosition(bot.X+d[0], bot.Y+d[1])
+		if (x != bot.X || y != bot.Y) && !CrossesObstacle(bot.X, bot.Y, x, y, objects) {
+			bot.Move(x, y, now)
+			return
+		}
+	}
+}
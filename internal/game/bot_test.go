@@ -0,0 +1,72 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepBot_PoliceChasesNearestFreeThief(t *testing.T) {
+	bot := &Player{ID: "bot", Role: RolePolice, X: 1000, Y: 1000}
+	near := &Player{ID: "near", Role: RoleThief, X: 1000, Y: 1500}
+	far := &Player{ID: "far", Role: RoleThief, X: 2000, Y: 1000}
+	arrested := &Player{ID: "arrested", Role: RoleThief, State: StateArrested, X: 1000, Y: 1100}
+
+	StepBot(bot, []*Player{bot, near, far, arrested}, nil, 0.5, time.Now())
+
+	assert.InDelta(t, 1000, bot.X, 0.001)
+	assert.InDelta(t, 1000+MoveSpeed*0.5, bot.Y, 0.001)
+}
+
+func TestStepBot_PoliceStopsOnTarget(t *testing.T) {
+	bot := &Player{ID: "bot", Role: RolePolice, X: 1000, Y: 1000}
+	thief := &Player{ID: "thief", Role: RoleThief, X: 1010, Y: 1000}
+
+	StepBot(bot, []*Player{bot, thief}, nil, 1, time.Now())
+
+	assert.InDelta(t, 1010, bot.X, 0.001)
+	assert.InDelta(t, 1000, bot.Y, 0.001)
+}
+
+func TestStepBot_ThiefFleesPolice(t *testing.T) {
+	bot := &Player{ID: "bot", Role: RoleThief, X: 1000, Y: 1000}
+	cop := &Player{ID: "cop", Role: RolePolice, X: 1200, Y: 1000}
+
+	StepBot(bot, []*Player{bot, cop}, nil, 0.5, time.Now())
+
+	assert.InDelta(t, 1000-MoveSpeed*0.5, bot.X, 0.001)
+	assert.InDelta(t, 1000, bot.Y, 0.001)
+}
+
+func TestStepBot_StaysInBounds(t *testing.T) {
+	bot := &Player{ID: "bot", Role: RoleThief, X: PlayerRadius, Y: 1000}
+	cop := &Player{ID: "cop", Role: RolePolice, X: 200, Y: 1000}
+
+	StepBot(bot, []*Player{bot, cop}, nil, 1, time.Now())
+
+	assert.InDelta(t, PlayerRadius, bot.X, 0.001)
+}
+
+func TestStepBot_ArrestedThiefWaits(t *testing.T) {
+	bot := &Player{ID: "bot", Role: RoleThief, State: StateArrested, X: 1000, Y: 1000}
+	cop := &Player{ID: "cop", Role: RolePolice, X: 1100, Y: 1000}
+
+	StepBot(bot, []*Player{bot, cop}, nil, 1, time.Now())
+
+	assert.Equal(t, 1000.0, bot.X)
+	assert.Equal(t, 1000.0, bot.Y)
+}
+
+func TestStepBot_WalksAroundObstacles(t *testing.T) {
+	tree := []MapObject{{Type: "tree", X: 1100, Y: 1000}}
+	bot := &Player{ID: "bot", Role: RolePolice, X: 1000, Y: 1000}
+	thief := &Player{ID: "thief", Role: RoleThief, X: 1400, Y: 1000}
+
+	for i := 0; i < 40; i++ {
+		prevX, prevY := bot.X, bot.Y
+		StepBot(bot, []*Player{bot, thief}, tree, TickInterval.Seconds(), time.Now())
+		assert.False(t, CrossesObstacle(prevX, prevY, bot.X, bot.Y, tree), "step %d", i)
+	}
+	assert.Less(t, Distance(bot.X, bot.Y, thief.X, thief.Y), ArrestRange, "went around the tree")
+}
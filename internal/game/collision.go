@@ -77,3 +77,58 @@ func FindJailRescueCandidates(players []*Player, jailX, jailY float64) []*Player
 	}
 	return candidates
 }
+
+// CrossesObstacle reports whether a straight move from (x1, y1) to (x2, y2)
+// enters the core of a tree or lake, which players must walk around.
+// The core is the central half of the object's size, leaving room for
+// client collision shapes that are smaller than the sprite.
+func CrossesObstacle(x1, y1, x2, y2 float64, objects []MapObject) bool {
+	for _, obj := range objects {
+		if obj.Type != "tree" && obj.Type != "lake" {
+			continue
+		}
+		size := objectSizes[obj.Type]
+		halfW, halfH := size[0]/4, size[1]/4
+		minX, maxX := obj.X-halfW, obj.X+halfW
+		minY, maxY := obj.Y-halfH, obj.Y+halfH
+
+		// A player already inside (e.g. a map it loaded differently) is left alone
+		if x1 > minX && x1 < maxX && y1 > minY && y1 < maxY {
+			continue
+		}
+		if segmentHitsRect(x1, y1, x2, y2, minX, minY, maxX, maxY) {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentHitsRect clips the segment against the rectangle (Liang-Barsky).
+func segmentHitsRect(x1, y1, x2, y2, minX, minY, maxX, maxY float64) bool {
+	dx, dy := x2-x1, y2-y1
+	t0, t1 := 0.0, 1.0
+	for _, edge := range [4][2]float64{
+		{-dx, x1 - minX},
+		{dx, maxX - x1},
+		{-dy, y1 - minY},
+		{dy, maxY - y1},
+	} {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			if q < 0 {
+				return false
+			}
+			continue
+		}
+		r := q / p
+		if p < 0 {
+			t0 = max(t0, r)
+		} else {
+			t1 = min(t1, r)
+		}
+		if t0 > t1 {
+			return false
+		}
+	}
+	return true
+}
@@ -181,3 +181,29 @@ func TestFindJailRescueCandidates(t *testing.T) {
 		assert.Len(t, candidates, 0)
 	})
 }
+
+func TestCrossesObstacle(t *testing.T) {
+	objects := []MapObject{
+		{Type: "tree", X: 1000, Y: 1000}, // core 40x60
+		{Type: "jail", X: 2000, Y: 2000},
+	}
+
+	tests := []struct {
+		name           string
+		x1, y1, x2, y2 float64
+		expected       bool
+	}{
+		{"through tree", 900, 1000, 1100, 1000, true},
+		{"into tree core", 900, 1000, 1000, 1000, true},
+		{"passes beside tree", 900, 1100, 1100, 1100, false},
+		{"short step near tree", 940, 1000, 970, 1000, false},
+		{"through jail", 1900, 2000, 2100, 2000, false},
+		{"starting inside", 1000, 1000, 1100, 1000, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CrossesObstacle(tt.x1, tt.y1, tt.x2, tt.y2, objects))
+		})
+	}
+}
@@ -40,6 +40,8 @@ const (
 	TickRate     = 20 // ticks per second
 	TickInterval = time.Second / TickRate
 	ResetDelay   = 5 * time.Second
+
+	CountdownSeconds = 3 // countdown broadcast before each match starts
 )
 
 // Spawn
@@ -73,3 +75,8 @@ const (
 	StumbleRespawnTime      = 10 * time.Second
 	StumbleSlowMult         = 0.7            // 30% speed decrease
 )
+
+// Match statistics
+const (
+	NearThiefRange = 300.0 // pixels, police counted as "near" a free thief within this range
+)
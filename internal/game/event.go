@@ -0,0 +1,83 @@
+package game
+
+import "sync"
+
+// EventType identifies a notable gameplay moment emitted by the game tick.
+type EventType string
+
+const (
+	EventThiefArrested      EventType = "thief_arrested"
+	EventThiefInfected      EventType = "thief_infected"
+	EventThievesRescued     EventType = "thieves_rescued"
+	EventItemPickedUp       EventType = "item_picked_up"
+	EventStoneTripped       EventType = "stone_tripped"
+	EventInvincibilityEnded EventType = "invincibility_ended"
+)
+
+// Item kinds carried by pickup events.
+const (
+	ItemBooster      = "booster"
+	ItemStumbleStone = "stumble_stone"
+)
+
+// Event describes a single gameplay moment.
+// ActorID is the player who caused the event; TargetIDs lists the players affected by it.
+type Event struct {
+	Type      EventType `json:"type"`
+	Room      string    `json:"room"`
+	ActorID   string    `json:"actor_id,omitempty"`
+	TargetIDs []string  `json:"target_ids,omitempty"`
+	Item      string    `json:"item,omitempty"`
+	ItemID    string    `json:"item_id,omitempty"`
+	X         float64   `json:"x"`
+	Y         float64   `json:"y"`
+	Elapsed   float64   `json:"elapsed"` // seconds since game start
+}
+
+// EventHandler receives published events.
+type EventHandler func(Event)
+
+// EventBus fans out events to in-process subscribers (stats, achievements, replays).
+// Handlers are invoked synchronously on the publishing goroutine and must not block.
+type EventBus struct {
+	handlers map[int]EventHandler
+	nextID   int
+	mu       sync.RWMutex
+}
+
+// NewEventBus creates an empty event bus.
+func NewEventBus() *EventBus {
+	return &EventBus{
+		handlers: make(map[int]EventHandler),
+	}
+}
+
+// Subscribe registers a handler and returns a function that removes it.
+func (b *EventBus) Subscribe(h EventHandler) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = h
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+// Publish delivers an event to all current subscribers.
+func (b *EventBus) Publish(e Event) {
+	b.mu.RLock()
+	handlers := make([]EventHandler, 0, len(b.handlers))
+	for _, h := range b.handlers {
+		handlers = append(handlers, h)
+	}
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(e)
+	}
+}
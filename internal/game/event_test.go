@@ -0,0 +1,45 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventBus_PublishToSubscribers(t *testing.T) {
+	bus := NewEventBus()
+
+	var got1, got2 []Event
+	bus.Subscribe(func(e Event) { got1 = append(got1, e) })
+	bus.Subscribe(func(e Event) { got2 = append(got2, e) })
+
+	bus.Publish(Event{Type: EventThiefArrested, ActorID: "p1", TargetIDs: []string{"t1"}})
+
+	assert.Len(t, got1, 1)
+	assert.Len(t, got2, 1)
+	assert.Equal(t, EventThiefArrested, got1[0].Type)
+	assert.Equal(t, "p1", got2[0].ActorID)
+}
+
+func TestEventBus_Unsubscribe(t *testing.T) {
+	bus := NewEventBus()
+
+	count := 0
+	unsubscribe := bus.Subscribe(func(Event) { count++ })
+
+	bus.Publish(Event{Type: EventItemPickedUp})
+	unsubscribe()
+	bus.Publish(Event{Type: EventItemPickedUp})
+
+	assert.Equal(t, 1, count)
+}
+
+func TestBoosterCheckPickup_ReportsPlayer(t *testing.T) {
+	bm := &BoosterManager{Active: []*Booster{{ID: "boost_1", X: 100, Y: 100}}}
+	players := []*Player{{ID: "p1", X: 110, Y: 100}}
+
+	picked := bm.CheckPickup(players)
+
+	assert.Equal(t, []Pickup{{ItemID: "boost_1", PlayerID: "p1", X: 100, Y: 100}}, picked)
+	assert.True(t, players[0].Boosted)
+}
@@ -15,6 +15,30 @@ func CheckPoliceWin(players []*Player) bool {
 	return thiefCount > 0 && thiefCount == arrestedCount
 }
 
+// CheckForfeit returns the winner when a team has no players left.
+// If both teams are empty there is no winner.
+func CheckForfeit(players []*Player) (WinResult, bool) {
+	police, thieves := 0, 0
+	for _, p := range players {
+		switch p.Role {
+		case RolePolice:
+			police++
+		case RoleThief:
+			thieves++
+		}
+	}
+	switch {
+	case police > 0 && thieves > 0:
+		return WinNone, false
+	case police > 0:
+		return WinPolice, true
+	case thieves > 0:
+		return WinThief, true
+	default:
+		return WinNone, true
+	}
+}
+
 // CheckThiefWin returns true if the timer expired and at least one thief is free.
 func CheckThiefWin(players []*Player, timerExpired bool) bool {
 	if !timerExpired {
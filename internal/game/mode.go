@@ -0,0 +1,84 @@
+package game
+
+// Mode names announced to clients.
+const (
+	ModeClassic   = "classic"
+	ModeInfection = "infection"
+)
+
+// Mode defines the rules that differ between game modes.
+type Mode interface {
+	// Name is the identifier announced in room_info and game_start.
+	Name() string
+	// OnArrest applies a completed arrest to the thief and returns the event to emit.
+	OnArrest(thief *Player) EventType
+	// AllowsRescue reports whether arrested thieves can be freed from jail.
+	AllowsRescue() bool
+	// CheckWin returns the winner, the reason and true if the match is decided.
+	CheckWin(players []*Player, timerExpired bool) (WinResult, EndReason, bool)
+}
+
+// ModeByName returns the mode with the given name.
+func ModeByName(name string) (Mode, bool) {
+	switch name {
+	case ModeClassic:
+		return ClassicMode{}, true
+	case ModeInfection:
+		return InfectionMode{}, true
+	default:
+		return nil, false
+	}
+}
+
+// ClassicMode jails arrested thieves, who can be rescued by free teammates.
+// Police win by arresting every thief; thieves win if the timer expires.
+type ClassicMode struct{}
+
+func (ClassicMode) Name() string { return ModeClassic }
+
+func (ClassicMode) OnArrest(thief *Player) EventType {
+	thief.Arrest()
+	return EventThiefArrested
+}
+
+func (ClassicMode) AllowsRescue() bool { return true }
+
+func (ClassicMode) CheckWin(players []*Player, timerExpired bool) (WinResult, EndReason, bool) {
+	if CheckPoliceWin(players) {
+		return WinPolice, ReasonAllArrested, true
+	}
+	if timerExpired {
+		return WinThief, ReasonTimeout, true
+	}
+	return WinNone, "", false
+}
+
+// InfectionMode converts arrested thieves to police instead of jailing them.
+// There is no rescue. Police win once no thieves remain; any thief still free
+// when the timer expires wins.
+type InfectionMode struct{}
+
+func (InfectionMode) Name() string { return ModeInfection }
+
+func (InfectionMode) OnArrest(thief *Player) EventType {
+	thief.Infect()
+	return EventThiefInfected
+}
+
+func (InfectionMode) AllowsRescue() bool { return false }
+
+func (InfectionMode) CheckWin(players []*Player, timerExpired bool) (WinResult, EndReason, bool) {
+	thieves := 0
+	for _, p := range players {
+		if p.Role == RoleThief {
+			thieves++
+		}
+	}
+	if thieves == 0 {
+		return WinPolice, ReasonAllArrested, true
+	}
+	if timerExpired {
+		return WinThief, ReasonTimeout, true
+	}
+	return WinNone, "", false
+}
@@ -0,0 +1,156 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModeByName(t *testing.T) {
+	m, ok := ModeByName(ModeClassic)
+	require.True(t, ok)
+	assert.Equal(t, ModeClassic, m.Name())
+
+	m, ok = ModeByName(ModeInfection)
+	require.True(t, ok)
+	assert.Equal(t, ModeInfection, m.Name())
+
+	_, ok = ModeByName("tag")
+	assert.False(t, ok)
+}
+
+func TestClassicMode_OnArrestJailsThief(t *testing.T) {
+	thief := &Player{ID: "t1", Role: RoleThief}
+
+	event := ClassicMode{}.OnArrest(thief)
+
+	assert.Equal(t, EventThiefArrested, event)
+	assert.Equal(t, RoleThief, thief.Role)
+	assert.True(t, thief.IsArrested())
+}
+
+func TestInfectionMode_OnArrestConvertsThief(t *testing.T) {
+	thief := &Player{ID: "t1", Role: RoleThief, ArrestGauge: ArrestDuration}
+
+	event := InfectionMode{}.OnArrest(thief)
+
+	assert.Equal(t, EventThiefInfected, event)
+	assert.Equal(t, RolePolice, thief.Role)
+	assert.False(t, thief.IsArrested())
+	assert.Zero(t, thief.ArrestGauge)
+	assert.False(t, InfectionMode{}.AllowsRescue())
+}
+
+func TestMode_CheckWin(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         Mode
+		players      []*Player
+		timerExpired bool
+		want         WinResult
+		reason       EndReason
+		decided      bool
+	}{
+		{
+			name: "classic in progress",
+			mode: ClassicMode{},
+			players: []*Player{
+				{ID: "p1", Role: RolePolice},
+				{ID: "t1", Role: RoleThief},
+			},
+			want: WinNone,
+		},
+		{
+			name: "classic all arrested",
+			mode: ClassicMode{},
+			players: []*Player{
+				{ID: "p1", Role: RolePolice},
+				{ID: "t1", Role: RoleThief, State: StateArrested},
+			},
+			want:    WinPolice,
+			reason:  ReasonAllArrested,
+			decided: true,
+		},
+		{
+			name: "classic timer expired",
+			mode: ClassicMode{},
+			players: []*Player{
+				{ID: "p1", Role: RolePolice},
+				{ID: "t1", Role: RoleThief},
+			},
+			timerExpired: true,
+			want:         WinThief,
+			reason:       ReasonTimeout,
+			decided:      true,
+		},
+		{
+			name: "infection thieves remain",
+			mode: InfectionMode{},
+			players: []*Player{
+				{ID: "p1", Role: RolePolice},
+				{ID: "t1", Role: RolePolice},
+				{ID: "t2", Role: RoleThief},
+			},
+			want: WinNone,
+		},
+		{
+			name: "infection everyone infected",
+			mode: InfectionMode{},
+			players: []*Player{
+				{ID: "p1", Role: RolePolice},
+				{ID: "t1", Role: RolePolice},
+			},
+			want:    WinPolice,
+			reason:  ReasonAllArrested,
+			decided: true,
+		},
+		{
+			name: "infection survivor at timer expiry",
+			mode: InfectionMode{},
+			players: []*Player{
+				{ID: "p1", Role: RolePolice},
+				{ID: "t1", Role: RoleThief},
+			},
+			timerExpired: true,
+			want:         WinThief,
+			reason:       ReasonTimeout,
+			decided:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason, decided := tt.mode.CheckWin(tt.players, tt.timerExpired)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.reason, reason)
+			assert.Equal(t, tt.decided, decided)
+		})
+	}
+}
+
+func TestCheckForfeit(t *testing.T) {
+	tests := []struct {
+		name    string
+		roles   []Role
+		want    WinResult
+		forfeit bool
+	}{
+		{"both teams present", []Role{RolePolice, RoleThief}, WinNone, false},
+		{"all police left", []Role{RoleThief, RoleThief}, WinThief, true},
+		{"all thieves left", []Role{RolePolice}, WinPolice, true},
+		{"everyone left", nil, WinNone, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var players []*Player
+			for _, role := range tt.roles {
+				players = append(players, &Player{Role: role})
+			}
+			got, forfeit := CheckForfeit(players)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.forfeit, forfeit)
+		})
+	}
+}
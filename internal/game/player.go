@@ -93,6 +93,8 @@ func (s *PlayerState) UnmarshalJSON(data []byte) error {
 
 type Player struct {
 	ID           string      `json:"id"`
+	AccountID    string      `json:"account_id,omitempty"`
+	IsGuest      bool        `json:"is_guest,omitempty"`
 	Nickname     string      `json:"nickname"`
 	Role         Role        `json:"role"`
 	State        PlayerState `json:"state"`
@@ -101,6 +103,14 @@ type Player struct {
 	Ready        bool        `json:"ready"`
 	LastMoveTime time.Time   `json:"-"`
 
+	// LastInputTime: when the player last sent any input, for AFK detection.
+	// Unlike LastMoveTime it also counts lobby actions and is not cleared by Reset.
+	LastInputTime time.Time `json:"-"`
+	// AFKWarned: whether the player was warned since their last input.
+	AFKWarned bool `json:"-"`
+	// Bot: whether the server controls the player after its owner went AFK.
+	Bot bool `json:"bot,omitempty"`
+
 	// Arrest gauge: cumulative time a police has been in range (seconds).
 	ArrestGauge float64 `json:"arrest_gauge"`
 	// Rescue gauge: continuous time a free thief has been near jail (seconds).
@@ -115,6 +125,19 @@ type Player struct {
 	Slowed bool `json:"-"`
 	// SlowTimer: remaining slow duration.
 	SlowTimer time.Duration `json:"-"`
+
+	// trail: recent positions, oldest first, for judging where the player was
+	// when another player last moved.
+	trail []positionSample
+}
+
+// positionTrailWindow is how far back a player's trail reaches.
+const positionTrailWindow = 2 * time.Second
+
+// positionSample is a position a player held from At onward.
+type positionSample struct {
+	X, Y float64
+	At   time.Time
 }
 
 func NewPlayer(nickname string) *Player {
@@ -126,13 +149,58 @@ func NewPlayer(nickname string) *Player {
 	}
 }
 
+// NewAccountPlayer creates a player bound to an authenticated account,
+// using the account's nickname.
+func NewAccountPlayer(accountID, nickname string, isGuest bool) *Player {
+	p := NewPlayer(nickname)
+	p.AccountID = accountID
+	p.IsGuest = isGuest
+	return p
+}
+
 func (p *Player) SetRole(role Role) {
 	p.Role = role
 }
 
+// SetPosition places the player, starting a new position trail.
 func (p *Player) SetPosition(x, y float64) {
 	p.X = x
 	p.Y = y
+	p.trail = append(p.trail[:0], positionSample{X: x, Y: y})
+}
+
+// Move moves the player at now, recording the step in the position trail.
+func (p *Player) Move(x, y float64, now time.Time) {
+	p.X = x
+	p.Y = y
+	p.trail = append(p.trail, positionSample{X: x, Y: y, At: now})
+
+	// Keep one sample older than the window as the position it started from
+	cut := 0
+	for cut+1 < len(p.trail) && now.Sub(p.trail[cut+1].At) > positionTrailWindow {
+		cut++
+	}
+	if cut > 0 {
+		p.trail = append(p.trail[:0], p.trail[cut:]...)
+	}
+}
+
+// PositionAt returns where the player was at t, as far back as the trail reaches.
+func (p *Player) PositionAt(t time.Time) (x, y float64) {
+	x, y = p.X, p.Y
+	for i := len(p.trail) - 1; i >= 0; i-- {
+		x, y = p.trail[i].X, p.trail[i].Y
+		if !p.trail[i].At.After(t) {
+			break
+		}
+	}
+	return x, y
+}
+
+// Touch records player input at now and clears any AFK warning.
+func (p *Player) Touch(now time.Time) {
+	p.LastInputTime = now
+	p.AFKWarned = false
 }
 
 func (p *Player) Arrest() {
@@ -140,6 +208,14 @@ func (p *Player) Arrest() {
 	p.ArrestGauge = 0
 }
 
+// Infect converts an arrested thief into police (infection mode).
+func (p *Player) Infect() {
+	p.Role = RolePolice
+	p.State = StateFree
+	p.ArrestGauge = 0
+	p.RescueGauge = 0
+}
+
 func (p *Player) Release() {
 	p.State = StateInvincible
 	p.InvincibleTimer = InvincibleTime
@@ -166,8 +242,7 @@ func (p *Player) IsInvincible() bool {
 func (p *Player) Reset() {
 	p.State = StateFree
 	p.Ready = false
-	p.X = 0
-	p.Y = 0
+	p.SetPosition(0, 0)
 	p.LastMoveTime = time.Time{}
 	p.ArrestGauge = 0
 	p.RescueGauge = 0
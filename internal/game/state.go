@@ -3,7 +3,8 @@ package game
 type RoomState int
 
 const (
-	StateWaiting RoomState = iota
+	StateWaiting  RoomState = iota
+	StateStarting           // countdown before play; clients load the map
 	StatePlaying
 	StateEnded
 )
@@ -12,6 +13,8 @@ func (s RoomState) String() string {
 	switch s {
 	case StateWaiting:
 		return "waiting"
+	case StateStarting:
+		return "starting"
 	case StatePlaying:
 		return "playing"
 	case StateEnded:
@@ -39,3 +42,12 @@ func (w WinResult) String() string {
 		return "none"
 	}
 }
+
+// EndReason explains why a match ended.
+type EndReason string
+
+const (
+	ReasonAllArrested EndReason = "all_arrested" // police caught (or infected) every thief
+	ReasonTimeout     EndReason = "timeout"      // timer expired with a thief still free
+	ReasonForfeit     EndReason = "forfeit"      // a team was emptied by players leaving
+)
@@ -0,0 +1,180 @@
+package game
+
+import "sort"
+
+// MVP scoring weights. See ScorePlayer for the rule.
+const (
+	scorePerArrest        = 100.0
+	scorePerSecondNear    = 2.0
+	scorePerRescue        = 100.0
+	scorePerSecondFree    = 1.0
+	scorePenaltyPerArrest = 50.0
+	scorePerItemCollected = 10.0
+)
+
+// PlayerStats holds per-player counters accumulated during a match.
+type PlayerStats struct {
+	PlayerID          string  `json:"player_id"`
+	Nickname          string  `json:"nickname"`
+	Role              Role    `json:"role"`
+	Arrests           int     `json:"arrests"`
+	TimeNearThieves   float64 `json:"time_near_thieves"` // seconds, police only
+	TimesArrested     int     `json:"times_arrested"`
+	Rescues           int     `json:"rescues"`
+	TimeFree          float64 `json:"time_free"` // seconds, thieves only
+	BoostersCollected int     `json:"boosters_collected"`
+	StonesHit         int     `json:"stones_hit"`
+	Distance          float64 `json:"distance"` // pixels travelled
+	Score             float64 `json:"score"`
+}
+
+// MVP names the most valuable player of each team.
+type MVP struct {
+	Police string `json:"police,omitempty"`
+	Thief  string `json:"thief,omitempty"`
+}
+
+// StatsTracker accumulates PlayerStats over a match from tick updates and events.
+type StatsTracker struct {
+	stats   map[string]*PlayerStats
+	lastPos map[string]Position
+}
+
+// NewStatsTracker starts tracking the given players from their current positions.
+func NewStatsTracker(players []*Player) *StatsTracker {
+	t := &StatsTracker{
+		stats:   make(map[string]*PlayerStats, len(players)),
+		lastPos: make(map[string]Position, len(players)),
+	}
+	for _, p := range players {
+		t.track(p)
+	}
+	return t
+}
+
+func (t *StatsTracker) track(p *Player) *PlayerStats {
+	if s, ok := t.stats[p.ID]; ok {
+		return s
+	}
+	s := &PlayerStats{PlayerID: p.ID, Nickname: p.Nickname, Role: p.Role}
+	t.stats[p.ID] = s
+	t.lastPos[p.ID] = Position{X: p.X, Y: p.Y}
+	return s
+}
+
+// Tick updates time- and distance-based counters. Call once per tick with dt in seconds.
+func (t *StatsTracker) Tick(players []*Player, dt float64) {
+	var freeThieves []*Player
+	for _, p := range players {
+		if p.Role == RoleThief && !p.IsArrested() {
+			freeThieves = append(freeThieves, p)
+		}
+	}
+
+	for _, p := range players {
+		s := t.track(p)
+
+		last := t.lastPos[p.ID]
+		s.Distance += Distance(last.X, last.Y, p.X, p.Y)
+		t.lastPos[p.ID] = Position{X: p.X, Y: p.Y}
+
+		switch p.Role {
+		case RolePolice:
+			for _, thief := range freeThieves {
+				if Distance(p.X, p.Y, thief.X, thief.Y) <= NearThiefRange {
+					s.TimeNearThieves += dt
+					break
+				}
+			}
+		case RoleThief:
+			if !p.IsArrested() {
+				s.TimeFree += dt
+			}
+		}
+	}
+}
+
+// HandleEvent updates event-based counters.
+func (t *StatsTracker) HandleEvent(e Event) {
+	actor := t.stats[e.ActorID]
+
+	switch e.Type {
+	case EventThiefArrested, EventThiefInfected:
+		if actor != nil {
+			actor.Arrests++
+		}
+		for _, id := range e.TargetIDs {
+			if s := t.stats[id]; s != nil {
+				s.TimesArrested++
+			}
+		}
+	case EventThievesRescued:
+		if actor != nil {
+			actor.Rescues++
+		}
+	case EventItemPickedUp:
+		if actor != nil {
+			actor.BoostersCollected++
+		}
+	case EventStoneTripped:
+		if actor != nil {
+			actor.StonesHit++
+		}
+	}
+}
+
+// Summary returns scored stats for every tracked player, highest score first.
+func (t *StatsTracker) Summary() []PlayerStats {
+	result := make([]PlayerStats, 0, len(t.stats))
+	for _, s := range t.stats {
+		entry := *s
+		entry.Score = ScorePlayer(entry)
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		if result[i].Distance != result[j].Distance {
+			return result[i].Distance > result[j].Distance
+		}
+		return result[i].PlayerID < result[j].PlayerID
+	})
+	return result
+}
+
+// ScorePlayer computes the MVP score for a player's match stats.
+//
+// Police: 100 per arrest + 2 per second spent near a free thief.
+// Thief:  100 per rescue + 1 per second free - 50 per time arrested.
+// Both:   10 per booster collected.
+func ScorePlayer(s PlayerStats) float64 {
+	score := float64(s.BoostersCollected) * scorePerItemCollected
+	switch s.Role {
+	case RolePolice:
+		score += float64(s.Arrests)*scorePerArrest + s.TimeNearThieves*scorePerSecondNear
+	case RoleThief:
+		score += float64(s.Rescues)*scorePerRescue + s.TimeFree*scorePerSecondFree
+		score -= float64(s.TimesArrested) * scorePenaltyPerArrest
+	}
+	return score
+}
+
+// SelectMVP picks the highest scoring player of each team from a Summary.
+// Ties are broken by distance travelled, then by player ID.
+func SelectMVP(summary []PlayerStats) MVP {
+	var mvp MVP
+	for _, s := range summary {
+		switch s.Role {
+		case RolePolice:
+			if mvp.Police == "" {
+				mvp.Police = s.PlayerID
+			}
+		case RoleThief:
+			if mvp.Thief == "" {
+				mvp.Thief = s.PlayerID
+			}
+		}
+	}
+	return mvp
+}
@@ -0,0 +1,83 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsTracker_TickAccumulates(t *testing.T) {
+	cop := &Player{ID: "p1", Role: RolePolice, X: 100, Y: 100}
+	thief := &Player{ID: "t1", Role: RoleThief, X: 200, Y: 100}
+	tracker := NewStatsTracker([]*Player{cop, thief})
+
+	cop.SetPosition(130, 140) // moved 50px
+	tracker.Tick([]*Player{cop, thief}, 0.5)
+
+	summary := tracker.Summary()
+	require.Len(t, summary, 2)
+
+	stats := make(map[string]PlayerStats)
+	for _, s := range summary {
+		stats[s.PlayerID] = s
+	}
+	assert.InDelta(t, 50.0, stats["p1"].Distance, 0.001)
+	assert.InDelta(t, 0.5, stats["p1"].TimeNearThieves, 0.001)
+	assert.InDelta(t, 0.5, stats["t1"].TimeFree, 0.001)
+}
+
+func TestStatsTracker_HandleEvent(t *testing.T) {
+	cop := &Player{ID: "p1", Role: RolePolice}
+	t1 := &Player{ID: "t1", Role: RoleThief}
+	t2 := &Player{ID: "t2", Role: RoleThief}
+	tracker := NewStatsTracker([]*Player{cop, t1, t2})
+
+	tracker.HandleEvent(Event{Type: EventThiefArrested, ActorID: "p1", TargetIDs: []string{"t1"}})
+	tracker.HandleEvent(Event{Type: EventThievesRescued, ActorID: "t2", TargetIDs: []string{"t1"}})
+	tracker.HandleEvent(Event{Type: EventItemPickedUp, ActorID: "t2"})
+	tracker.HandleEvent(Event{Type: EventStoneTripped, ActorID: "p1"})
+
+	stats := make(map[string]PlayerStats)
+	for _, s := range tracker.Summary() {
+		stats[s.PlayerID] = s
+	}
+	assert.Equal(t, 1, stats["p1"].Arrests)
+	assert.Equal(t, 1, stats["p1"].StonesHit)
+	assert.Equal(t, 1, stats["t1"].TimesArrested)
+	assert.Equal(t, 1, stats["t2"].Rescues)
+	assert.Equal(t, 1, stats["t2"].BoostersCollected)
+}
+
+func TestScorePlayer(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats PlayerStats
+		want  float64
+	}{
+		{"police arrests and pressure", PlayerStats{Role: RolePolice, Arrests: 2, TimeNearThieves: 10}, 220},
+		{"thief rescue and survival", PlayerStats{Role: RoleThief, Rescues: 1, TimeFree: 60, TimesArrested: 1}, 110},
+		{"booster bonus", PlayerStats{Role: RoleThief, BoostersCollected: 3}, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, ScorePlayer(tt.stats), 0.001)
+		})
+	}
+}
+
+func TestSelectMVP(t *testing.T) {
+	tracker := NewStatsTracker([]*Player{
+		{ID: "p1", Role: RolePolice},
+		{ID: "p2", Role: RolePolice},
+		{ID: "t1", Role: RoleThief},
+		{ID: "t2", Role: RoleThief},
+	})
+	tracker.HandleEvent(Event{Type: EventThiefArrested, ActorID: "p2", TargetIDs: []string{"t1"}})
+	tracker.HandleEvent(Event{Type: EventThievesRescued, ActorID: "t2", TargetIDs: []string{"t1"}})
+
+	mvp := SelectMVP(tracker.Summary())
+	assert.Equal(t, "p2", mvp.Police)
+	assert.Equal(t, "t2", mvp.Thief)
+}
@@ -92,9 +92,9 @@ func (sm *StumbleStoneManager) Update(dt time.Duration) {
 	sm.RespawnQueue = remaining
 }
 
-// CheckPickup tests if any player steps on a stumble stone. Returns picked up stone IDs.
-func (sm *StumbleStoneManager) CheckPickup(players []*Player) []string {
-	var picked []string
+// CheckPickup tests if any player steps on a stumble stone. Returns the pickups that occurred.
+func (sm *StumbleStoneManager) CheckPickup(players []*Player) []Pickup {
+	var picked []Pickup
 	var remaining []*StumbleStone
 
 	for _, s := range sm.Active {
@@ -107,7 +107,7 @@ func (sm *StumbleStoneManager) CheckPickup(players []*Player) []string {
 				p.Slowed = true
 				p.SlowTimer = StumbleSlowDuration
 				collected = true
-				picked = append(picked, s.ID)
+				picked = append(picked, Pickup{ItemID: s.ID, PlayerID: p.ID, X: s.X, Y: s.Y})
 				break
 			}
 		}
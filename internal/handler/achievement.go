@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/achievement"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/store"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// AchievementHandler evaluates achievements after each match and serves progress queries.
+type AchievementHandler struct {
+	defs  []achievement.Definition
+	store store.AchievementStore
+}
+
+// NewAchievementHandler creates a new achievement handler.
+func NewAchievementHandler(defs []achievement.Definition, store store.AchievementStore) *AchievementHandler {
+	return &AchievementHandler{
+		defs:  defs,
+		store: store,
+	}
+}
+
+type achievementUnlockedResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type achievementEntry struct {
+	achievement.Definition
+	Progress   float64    `json:"progress"`
+	UnlockedAt *time.Time `json:"unlocked_at,omitempty"`
+}
+
+type achievementsResponse struct {
+	Achievements []achievementEntry `json:"achievements"`
+}
+
+// HandleGameOver evaluates achievements for every authenticated participant
+// and pushes achievement_unlocked to players who unlocked something.
+func (h *AchievementHandler) HandleGameOver(r *room.Room, result room.MatchResult) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, pr := range result.Players {
+		// Leavers are penalized on the leaderboard and earn no achievements
+		if pr.AccountID == "" || pr.Left {
+			continue
+		}
+
+		outcome := achievement.Outcome{
+			Stats:         pr.Stats,
+			Won:           pr.Won,
+			MVP:           pr.PlayerID == result.MVP.Police || pr.PlayerID == result.MVP.Thief,
+			LastFreeThief: pr.LastFreeThief,
+		}
+
+		unlocked, err := h.apply(ctx, pr.AccountID, outcome)
+		if err != nil {
+			slog.Error("failed to evaluate achievements", "account_id", pr.AccountID, "room", result.RoomCode, "error", err)
+			continue
+		}
+
+		for _, d := range unlocked {
+			msg, _ := ws.NewMessage(ws.TypeAchievementUnlocked, achievementUnlockedResponse{
+				ID:          d.ID,
+				Name:        d.Name,
+				Description: d.Description,
+			})
+			r.SendToPlayer(pr.PlayerID, msg)
+			slog.Info("achievement unlocked", "account_id", pr.AccountID, "achievement", d.ID)
+		}
+	}
+}
+
+func (h *AchievementHandler) apply(ctx context.Context, accountID string, outcome achievement.Outcome) ([]achievement.Definition, error) {
+	existing, err := h.store.LoadAchievementProgress(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]achievement.Progress, len(existing))
+	for _, p := range existing {
+		current[p.AchievementID] = p
+	}
+
+	changed, unlocked := achievement.Evaluate(h.defs, current, outcome, time.Now())
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	if err := h.store.SaveAchievementProgress(ctx, accountID, changed); err != nil {
+		return nil, err
+	}
+	return unlocked, nil
+}
+
+// HandleGetAchievements returns every definition with the client's progress.
+func (h *AchievementHandler) HandleGetAchievements(client *ws.Client, _ ws.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	progress, err := h.store.LoadAchievementProgress(ctx, client.AccountID)
+	if err != nil {
+		slog.Error("failed to load achievements", "account_id", client.AccountID, "error", err)
+		client.SendMessage(ws.NewErrorMessage("서버 내부 오류입니다"))
+		return
+	}
+
+	byID := make(map[string]achievement.Progress, len(progress))
+	for _, p := range progress {
+		byID[p.AchievementID] = p
+	}
+
+	entries := make([]achievementEntry, 0, len(h.defs))
+	for _, d := range h.defs {
+		p := byID[d.ID]
+		entries = append(entries, achievementEntry{
+			Definition: d,
+			Progress:   p.Value,
+			UnlockedAt: p.UnlockedAt,
+		})
+	}
+
+	resp, _ := ws.NewMessage(ws.TypeGetAchievements, achievementsResponse{Achievements: entries})
+	client.SendMessage(resp)
+}
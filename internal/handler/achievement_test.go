@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/achievement"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// mockAchievementStore implements store.AchievementStore for testing.
+type mockAchievementStore struct {
+	progress map[string]map[string]achievement.Progress // account -> achievement -> progress
+}
+
+func newMockAchievementStore() *mockAchievementStore {
+	return &mockAchievementStore{progress: make(map[string]map[string]achievement.Progress)}
+}
+
+func (m *mockAchievementStore) LoadAchievementProgress(_ context.Context, accountID string) ([]achievement.Progress, error) {
+	var result []achievement.Progress
+	for _, p := range m.progress[accountID] {
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+func (m *mockAchievementStore) SaveAchievementProgress(_ context.Context, accountID string, progress []achievement.Progress) error {
+	if m.progress[accountID] == nil {
+		m.progress[accountID] = make(map[string]achievement.Progress)
+	}
+	for _, p := range progress {
+		m.progress[accountID][p.AchievementID] = p
+	}
+	return nil
+}
+
+func TestAchievementHandler_HandleGameOver(t *testing.T) {
+	st := newMockAchievementStore()
+	defs := []achievement.Definition{
+		{ID: "first_arrest", Name: "첫 체포", Metric: achievement.MetricArrests, Threshold: 1, Scope: achievement.ScopeLifetime},
+		{ID: "rescue_twice", Metric: achievement.MetricRescues, Threshold: 2, Scope: achievement.ScopeGame},
+	}
+	h := NewAchievementHandler(defs, st)
+
+	r := room.NewRoom("TEST")
+	client, ch := newTestClient("c1")
+	client.AccountID = "acc-1"
+	r.AddPlayer(&game.Player{ID: "p1", Role: game.RolePolice}, client)
+
+	h.HandleGameOver(r, room.MatchResult{
+		RoomCode: "TEST",
+		Winner:   game.WinPolice,
+		Players: []room.PlayerResult{
+			{PlayerID: "p1", AccountID: "acc-1", Won: true, Stats: game.PlayerStats{PlayerID: "p1", Role: game.RolePolice, Arrests: 1}},
+			{PlayerID: "p2", Stats: game.PlayerStats{PlayerID: "p2", Role: game.RoleThief, Rescues: 2}}, // no account
+		},
+	})
+
+	resp := readResponse(t, ch)
+	assert.Equal(t, ws.TypeAchievementUnlocked, resp.Type)
+	var unlocked achievementUnlockedResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &unlocked))
+	assert.Equal(t, "first_arrest", unlocked.ID)
+
+	assert.True(t, st.progress["acc-1"]["first_arrest"].Unlocked())
+	assert.Len(t, st.progress, 1, "players without an account should be skipped")
+}
+
+func TestAchievementHandler_HandleGetAchievements(t *testing.T) {
+	st := newMockAchievementStore()
+	st.SaveAchievementProgress(context.Background(), "acc-1", []achievement.Progress{{AchievementID: "marathon", Value: 500}})
+	defs := []achievement.Definition{
+		{ID: "marathon", Metric: achievement.MetricDistance, Threshold: 1000, Scope: achievement.ScopeLifetime},
+		{ID: "first_arrest", Metric: achievement.MetricArrests, Threshold: 1, Scope: achievement.ScopeLifetime},
+	}
+	h := NewAchievementHandler(defs, st)
+
+	client, ch := newTestClient("c1")
+	client.AccountID = "acc-1"
+	h.HandleGetAchievements(client, ws.Message{Type: ws.TypeGetAchievements})
+
+	resp := readResponse(t, ch)
+	var result achievementsResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &result))
+	require.Len(t, result.Achievements, 2)
+	assert.Equal(t, 500.0, result.Achievements[0].Progress)
+	assert.Nil(t, result.Achievements[1].UnlockedAt)
+}
+
+func TestAchievementHandler_SkipsLeavers(t *testing.T) {
+	st := newMockAchievementStore()
+	defs := []achievement.Definition{
+		{ID: "first_arrest", Metric: achievement.MetricArrests, Threshold: 1, Scope: achievement.ScopeLifetime},
+	}
+	h := NewAchievementHandler(defs, st)
+
+	h.HandleGameOver(room.NewRoom("TEST"), room.MatchResult{
+		RoomCode: "TEST",
+		Winner:   game.WinThief,
+		Reason:   game.ReasonForfeit,
+		Players: []room.PlayerResult{
+			{PlayerID: "p1", AccountID: "acc-1", Left: true, Stats: game.PlayerStats{PlayerID: "p1", Role: game.RolePolice, Arrests: 1}},
+		},
+	})
+
+	assert.Empty(t, st.progress["acc-1"])
+}
@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/store"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// AdminHandler serves moderation operations over HTTP. It must be mounted
+// behind RequireBearer; only allowlisted accounts may use it, and each
+// operation is recorded in the audit log under the caller's account ID.
+type AdminHandler struct {
+	store    store.ModerationStore
+	accounts store.AccountStore
+	router   *Router
+	admins   map[string]bool
+	mux      *http.ServeMux
+}
+
+// NewAdminHandler creates a new admin handler for the given admin account IDs.
+func NewAdminHandler(store store.ModerationStore, accounts store.AccountStore, adminIDs []string, router *Router) *AdminHandler {
+	h := &AdminHandler{
+		store:    store,
+		accounts: accounts,
+		router:   router,
+		admins:   make(map[string]bool, len(adminIDs)),
+		mux:      http.NewServeMux(),
+	}
+	for _, id := range adminIDs {
+		h.admins[id] = true
+	}
+	h.mux.HandleFunc("GET /admin/bans", h.handleListBans)
+	h.mux.HandleFunc("POST /admin/bans", h.handleIssueBan)
+	h.mux.HandleFunc("DELETE /admin/bans/{id}", h.handleLiftBan)
+	h.mux.HandleFunc("GET /admin/reports", h.handleListReports)
+	h.mux.HandleFunc("POST /admin/reports/{id}/review", h.handleReviewReport)
+	h.mux.HandleFunc("GET /admin/flags", h.handleListFlags)
+	h.mux.HandleFunc("POST /admin/flags/{id}/review", h.handleReviewFlag)
+	h.mux.HandleFunc("GET /admin/audit", h.handleListAudit)
+	return h
+}
+
+// ServeHTTP checks that the caller is an admin and dispatches the request.
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.admins[AccountIDFromContext(r.Context())] {
+		writeJSONError(w, http.StatusForbidden, "admin only")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+type issueBanRequest struct {
+	AccountID       string `json:"account_id"`
+	Reason          string `json:"reason"`
+	DurationSeconds int64  `json:"duration_seconds"` // 0 for a permanent ban
+}
+
+// handleIssueBan serves POST /admin/bans.
+func (h *AdminHandler) handleIssueBan(w http.ResponseWriter, r *http.Request) {
+	var req issueBanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.AccountID == "" || req.Reason == "" || req.DurationSeconds < 0 {
+		writeJSONError(w, http.StatusBadRequest, "account_id and reason are required, duration_seconds must not be negative")
+		return
+	}
+
+	acc, err := h.accounts.FindByID(r.Context(), req.AccountID)
+	if err != nil {
+		slog.Error("failed to look up account to ban", "account_id", req.AccountID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if acc == nil {
+		writeJSONError(w, http.StatusNotFound, "account not found")
+		return
+	}
+
+	actor := AccountIDFromContext(r.Context())
+	ban := moderation.NewBan(req.AccountID, req.Reason, actor, time.Duration(req.DurationSeconds)*time.Second)
+	if err := h.store.IssueBan(r.Context(), ban); err != nil {
+		slog.Error("failed to issue ban", "account_id", req.AccountID, "error", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	h.router.enforceBan(ban)
+
+	slog.Info("account banned", "account_id", ban.AccountID, "by", actor, "permanent", ban.Permanent, "reason", ban.Reason)
+	writeJSON(w, http.StatusCreated, ban)
+}
+
+// handleLiftBan serves DELETE /admin/bans/{id}.
+func (h *AdminHandler) handleLiftBan(w http.ResponseWriter, r *http.Request) {
+	actor := AccountIDFromContext(r.Context())
+	ban, err := h.store.LiftBan(r.Context(), r.PathValue("id"), actor)
+	switch {
+	case errors.Is(err, moderation.ErrBanNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, moderation.ErrAlreadyLifted):
+		writeJSONError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		slog.Error("failed to lift ban", "ban", r.PathValue("id"), "error", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	slog.Info("ban lifted", "account_id", ban.AccountID, "ban", ban.ID, "by", actor)
+	writeJSON(w, http.StatusOK, ban)
+}
+
+// handleListBans serves GET /admin/bans?account_id=&active=&limit=&offset=.
+func (h *AdminHandler) handleListBans(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	limit, offset := pageParams(params.Get("limit"), params.Get("offset"))
+	active, _ := strconv.ParseBool(params.Get("active"))
+
+	bans, err := h.store.ListBans(r.Context(), moderation.BanQuery{
+		AccountID:  params.Get("account_id"),
+		ActiveOnly: active,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		slog.Error("failed to list bans", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if bans == nil {
+		bans = []moderation.Ban{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"bans": bans})
+}
+
+// handleListReports serves GET /admin/reports?status=&target_id=&limit=&offset=,
+// oldest first. status=open gives the reviewer queue.
+func (h *AdminHandler) handleListReports(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	limit, offset := pageParams(params.Get("limit"), params.Get("offset"))
+
+	reports, err := h.store.ListReports(r.Context(), moderation.ReportQuery{
+		Status:   moderation.ReportStatus(params.Get("status")),
+		TargetID: params.Get("target_id"),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		slog.Error("failed to list reports", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if reports == nil {
+		reports = []moderation.Report{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"reports": reports})
+}
+
+// reviewRequest closes a report or flag.
+type reviewRequest struct {
+	Status moderation.ReportStatus `json:"status"` // actioned or dismissed
+	Note   string                  `json:"note"`
+}
+
+// handleReviewReport serves POST /admin/reports/{id}/review.
+func (h *AdminHandler) handleReviewReport(w http.ResponseWriter, r *http.Request) {
+	var req reviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !req.Status.Closed() {
+		writeJSONError(w, http.StatusBadRequest, "status must be actioned or dismissed")
+		return
+	}
+
+	actor := AccountIDFromContext(r.Context())
+	report, err := h.store.ReviewReport(r.Context(), r.PathValue("id"), actor, req.Status, req.Note)
+	switch {
+	case errors.Is(err, moderation.ErrReportNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, moderation.ErrReportClosed):
+		writeJSONError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		slog.Error("failed to review report", "report", r.PathValue("id"), "error", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	slog.Info("report reviewed", "report", report.ID, "target", report.TargetID, "status", report.Status, "by", actor)
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleListFlags serves GET /admin/flags?status=&account_id=&limit=&offset=,
+// oldest first. status=open gives the reviewer queue.
+func (h *AdminHandler) handleListFlags(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	limit, offset := pageParams(params.Get("limit"), params.Get("offset"))
+
+	flags, err := h.store.ListFlags(r.Context(), moderation.FlagQuery{
+		Status:    moderation.ReportStatus(params.Get("status")),
+		AccountID: params.Get("account_id"),
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		slog.Error("failed to list flags", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if flags == nil {
+		flags = []moderation.Flag{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"flags": flags})
+}
+
+// handleReviewFlag serves POST /admin/flags/{id}/review.
+func (h *AdminHandler) handleReviewFlag(w http.ResponseWriter, r *http.Request) {
+	var req reviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !req.Status.Closed() {
+		writeJSONError(w, http.StatusBadRequest, "status must be actioned or dismissed")
+		return
+	}
+
+	actor := AccountIDFromContext(r.Context())
+	flag, err := h.store.ReviewFlag(r.Context(), r.PathValue("id"), actor, req.Status, req.Note)
+	switch {
+	case errors.Is(err, moderation.ErrFlagNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, moderation.ErrFlagClosed):
+		writeJSONError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		slog.Error("failed to review flag", "flag", r.PathValue("id"), "error", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	slog.Info("flag reviewed", "flag", flag.ID, "account_id", flag.AccountID, "status", flag.Status, "by", actor)
+	writeJSON(w, http.StatusOK, flag)
+}
+
+// handleListAudit serves GET /admin/audit?actor=&target_id=&limit=&offset=.
+func (h *AdminHandler) handleListAudit(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	limit, offset := pageParams(params.Get("limit"), params.Get("offset"))
+
+	entries, err := h.store.ListAudit(r.Context(), moderation.AuditQuery{
+		Actor:    params.Get("actor"),
+		TargetID: params.Get("target_id"),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		slog.Error("failed to list audit log", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if entries == nil {
+		entries = []moderation.AuditEntry{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"entries": entries})
+}
+
+func pageParams(limit, offset string) (int, int) {
+	l, _ := strconv.Atoi(limit)
+	o, _ := strconv.Atoi(offset)
+	return l, o
+}
+
+// enforceBan revokes a newly banned account's session tokens and
+// disconnects its client, if online.
+func (r *Router) enforceBan(ban *moderation.Ban) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.authH.tokens.RevokeAccount(ctx, ban.AccountID); err != nil {
+		slog.Error("failed to persist session token revocation", "account_id", ban.AccountID, "error", err)
+	}
+
+	client := r.ClientByAccount(ban.AccountID)
+	if client == nil || !ban.Active(time.Now()) {
+		return
+	}
+	msg, _ := ws.NewMessage(ws.TypeBanned, newBanNotice(ban))
+	client.Kick(msg)
+}
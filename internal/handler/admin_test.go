@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// mockModerationStore is safe for concurrent use, as anti-cheat audits
+// are written in the background.
+type mockModerationStore struct {
+	mu      sync.Mutex
+	bans    []*moderation.Ban
+	audit   []moderation.AuditEntry
+	reports []*moderation.Report
+	flags   []*moderation.Flag
+}
+
+func (m *mockModerationStore) IssueBan(_ context.Context, ban *moderation.Ban) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bans = append(m.bans, ban)
+	m.audit = append(m.audit, moderation.NewAuditEntry(ban.IssuedBy, moderation.ActionBan, ban.AccountID, ban))
+	return nil
+}
+
+func (m *mockModerationStore) LiftBan(_ context.Context, banID, actor string) (*moderation.Ban, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, b := range m.bans {
+		if b.ID != banID {
+			continue
+		}
+		if b.LiftedAt != nil {
+			return nil, moderation.ErrAlreadyLifted
+		}
+		now := time.Now()
+		b.LiftedAt, b.LiftedBy = &now, actor
+		m.audit = append(m.audit, moderation.NewAuditEntry(actor, moderation.ActionUnban, b.AccountID, b))
+		return b, nil
+	}
+	return nil, moderation.ErrBanNotFound
+}
+
+func (m *mockModerationStore) ActiveBan(_ context.Context, accountID string) (*moderation.Ban, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, b := range m.bans {
+		if b.AccountID == accountID && b.Active(time.Now()) {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockModerationStore) ListBans(_ context.Context, q moderation.BanQuery) ([]moderation.Ban, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []moderation.Ban
+	for _, b := range m.bans {
+		if (q.AccountID == "" || b.AccountID == q.AccountID) && (!q.ActiveOnly || b.Active(time.Now())) {
+			out = append(out, *b)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockModerationStore) RecordAudit(_ context.Context, entry moderation.AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.audit = append(m.audit, entry)
+	return nil
+}
+
+func (m *mockModerationStore) RecordAudits(_ context.Context, entries []moderation.AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.audit = append(m.audit, entries...)
+	return nil
+}
+
+func (m *mockModerationStore) ListAudit(_ context.Context, q moderation.AuditQuery) ([]moderation.AuditEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []moderation.AuditEntry
+	for _, e := range m.audit {
+		if (q.Actor == "" || e.Actor == q.Actor) && (q.TargetID == "" || e.TargetID == q.TargetID) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockModerationStore) CreateReport(_ context.Context, r *moderation.Report) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.reports {
+		if existing.ReporterID == r.ReporterID && existing.TargetID == r.TargetID && existing.MatchID == r.MatchID {
+			return moderation.ErrDuplicateReport
+		}
+	}
+	m.reports = append(m.reports, r)
+	return nil
+}
+
+func (m *mockModerationStore) ListReports(_ context.Context, q moderation.ReportQuery) ([]moderation.Report, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []moderation.Report
+	for _, r := range m.reports {
+		if (q.Status == "" || r.Status == q.Status) && (q.TargetID == "" || r.TargetID == q.TargetID) {
+			out = append(out, *r)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockModerationStore) ReviewReport(_ context.Context, reportID, reviewer string, status moderation.ReportStatus, note string) (*moderation.Report, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.reports {
+		if r.ID != reportID {
+			continue
+		}
+		if r.Status.Closed() {
+			return nil, moderation.ErrReportClosed
+		}
+		now := time.Now()
+		r.Status, r.ReviewedBy, r.ReviewedAt, r.ReviewNote = status, reviewer, &now, note
+		m.audit = append(m.audit, moderation.NewAuditEntry(reviewer, moderation.ActionReviewReport, r.TargetID, r))
+		return r, nil
+	}
+	return nil, moderation.ErrReportNotFound
+}
+
+func (m *mockModerationStore) CreateFlag(_ context.Context, f *moderation.Flag) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.flags = append(m.flags, f)
+	return nil
+}
+
+func (m *mockModerationStore) ListFlags(_ context.Context, q moderation.FlagQuery) ([]moderation.Flag, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []moderation.Flag
+	for _, f := range m.flags {
+		if (q.Status == "" || f.Status == q.Status) && (q.AccountID == "" || f.AccountID == q.AccountID) {
+			out = append(out, *f)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockModerationStore) ReviewFlag(_ context.Context, flagID, reviewer string, status moderation.ReportStatus, note string) (*moderation.Flag, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range m.flags {
+		if f.ID != flagID {
+			continue
+		}
+		if f.Status.Closed() {
+			return nil, moderation.ErrFlagClosed
+		}
+		now := time.Now()
+		f.Status, f.ReviewedBy, f.ReviewedAt, f.ReviewNote = status, reviewer, &now, note
+		m.audit = append(m.audit, moderation.NewAuditEntry(reviewer, moderation.ActionReviewFlag, f.AccountID, f))
+		return f, nil
+	}
+	return nil, moderation.ErrFlagNotFound
+}
+
+type adminTest struct {
+	router *Router
+	rm     *room.Manager
+	store  *mockModerationStore
+	tokens *auth.SessionTokens
+	http   http.Handler
+}
+
+func setupAdminTest(t *testing.T, adminIDs ...string) *adminTest {
+	t.Helper()
+	mod := &mockModerationStore{}
+	tokens := auth.NewSessionTokens(nil, time.Minute)
+	rm := room.NewManager()
+	router := NewRouter(rm, auth.NewGameCenterVerifier(nil, 0), newMockAccountStore(), Options{
+		SessionTokens:   tokens,
+		ModerationStore: mod,
+		AdminAccountIDs: adminIDs,
+	})
+	require.NotNil(t, router.Admin())
+	return &adminTest{router: router, rm: rm, store: mod, tokens: tokens, http: RequireBearer(tokens, router.Admin())}
+}
+
+func (a *adminTest) do(t *testing.T, accountID, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	token, _ := a.tokens.Issue(accountID)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	a.http.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdmin_ForbiddenForNonAdmin(t *testing.T) {
+	a := setupAdminTest(t, "admin-1")
+	rec := a.do(t, "someone", http.MethodGet, "/admin/bans", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestAdmin_DisabledWithoutAllowlist(t *testing.T) {
+	router := NewRouter(room.NewManager(), auth.NewGameCenterVerifier(nil, 0), newMockAccountStore(), Options{
+		ModerationStore: &mockModerationStore{},
+	})
+	assert.Nil(t, router.Admin())
+}
+
+func TestAdmin_BanKicksOnlinePlayerAndBlocksSignIn(t *testing.T) {
+	a := setupAdminTest(t, "admin-1")
+	target, targetCh := loginGuest(t, a.router, "c1", "Target")
+
+	rec := a.do(t, "admin-1", http.MethodPost, "/admin/bans",
+		`{"account_id":"`+target.AccountID+`","reason":"cheating","duration_seconds":3600}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var ban moderation.Ban
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ban))
+	assert.Equal(t, "admin-1", ban.IssuedBy)
+	assert.False(t, ban.Permanent)
+	require.NotNil(t, ban.ExpiresAt)
+
+	msg := readResponse(t, targetCh)
+	assert.Equal(t, ws.TypeBanned, msg.Type)
+	assert.False(t, target.Authenticated())
+
+	// Signing back in with the guest credential is refused with the ban details
+	again, againCh := newTestClient("c2")
+	sendMessage(a.router, again, ws.TypeAuthenticate, map[string]string{
+		"method":     "guest",
+		"credential": a.router.authH.guests.Issue(target.AccountID),
+	})
+	resp := readResponse(t, againCh)
+	require.Equal(t, ws.TypeAuthResult, resp.Type)
+	var result struct {
+		Success bool       `json:"success"`
+		Ban     *banNotice `json:"ban"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Data, &result))
+	assert.False(t, result.Success)
+	require.NotNil(t, result.Ban)
+	assert.Equal(t, "cheating", result.Ban.Reason)
+	assert.False(t, again.Authenticated())
+}
+
+func TestAdmin_BanWhileHandlingMessages(t *testing.T) {
+	a := setupAdminTest(t, "admin-1")
+	target, targetCh := loginGuest(t, a.router, "c1", "Target")
+
+	done := make(chan int)
+	go func() {
+		rec := a.do(t, "admin-1", http.MethodPost, "/admin/bans",
+			`{"account_id":"`+target.AccountID+`","reason":"cheating","duration_seconds":0}`)
+		done <- rec.Code
+	}()
+	for target.Authenticated() {
+		sendMessage(a.router, target, ws.TypeListRooms, nil)
+		readResponse(t, targetCh)
+	}
+	require.Equal(t, http.StatusCreated, <-done)
+
+	// Messages handled after the ban are refused
+	sendMessage(a.router, target, ws.TypeListRooms, nil)
+	for {
+		resp := readResponse(t, targetCh)
+		if resp.Type == ws.TypeError {
+			var e ws.ErrorMessage
+			require.NoError(t, json.Unmarshal(resp.Data, &e))
+			assert.Equal(t, "인증이 필요합니다", e.Message)
+			break
+		}
+		assert.Contains(t, []string{ws.TypeListRooms, ws.TypeBanned}, resp.Type)
+	}
+}
+
+func TestAdmin_BanUnknownAccount(t *testing.T) {
+	a := setupAdminTest(t, "admin-1")
+	rec := a.do(t, "admin-1", http.MethodPost, "/admin/bans", `{"account_id":"nobody","reason":"x"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = a.do(t, "admin-1", http.MethodPost, "/admin/bans", `{"account_id":"nobody"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAdmin_LiftAndAudit(t *testing.T) {
+	a := setupAdminTest(t, "admin-1", "admin-2")
+	target, _ := loginGuest(t, a.router, "c1", "Target")
+
+	rec := a.do(t, "admin-1", http.MethodPost, "/admin/bans", `{"account_id":"`+target.AccountID+`","reason":"abuse"}`)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var ban moderation.Ban
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ban))
+	assert.True(t, ban.Permanent)
+
+	rec = a.do(t, "admin-2", http.MethodGet, "/admin/bans?active=true&account_id="+target.AccountID, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), ban.ID)
+
+	rec = a.do(t, "admin-2", http.MethodDelete, "/admin/bans/"+ban.ID, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = a.do(t, "admin-2", http.MethodDelete, "/admin/bans/"+ban.ID, "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = a.do(t, "admin-2", http.MethodDelete, "/admin/bans/missing", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = a.do(t, "admin-1", http.MethodGet, "/admin/audit?target_id="+target.AccountID, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var audit struct {
+		Entries []moderation.AuditEntry `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &audit))
+	require.Len(t, audit.Entries, 2)
+	assert.Equal(t, "admin-1", audit.Entries[0].Actor)
+	assert.Equal(t, moderation.ActionBan, audit.Entries[0].Action)
+	assert.Equal(t, "admin-2", audit.Entries[1].Actor)
+	assert.Equal(t, moderation.ActionUnban, audit.Entries[1].Action)
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/anticheat"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/store"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// Scored violations are written to the audit log in batches of up to
+// violationBatchSize, at most violationFlushInterval after the first one.
+const (
+	violationBatchSize     = 100
+	violationFlushInterval = 10 * time.Second
+)
+
+type kickedMessage struct {
+	Reason anticheat.Kind `json:"reason"`
+}
+
+// reportViolation scores an anti-cheat violation by the client and applies
+// the resulting action, which it returns.
+func (r *Router) reportViolation(client *ws.Client, v anticheat.Violation) anticheat.Action {
+	ev := r.anticheat.Record(v)
+	r.applyAntiCheat(client, ev)
+	return ev.Action
+}
+
+// HandleFlood scores a flood violation for a client that exceeded its
+// connection's message rate limit. The hub has already dropped the messages.
+func (r *Router) HandleFlood(client *ws.Client) {
+	if !client.Authenticated() || client.Closed() {
+		return
+	}
+	r.reportViolation(client, anticheat.Violation{
+		AccountID: client.AccountID,
+		PlayerID:  r.GetPlayerID(client.ID),
+		Kind:      anticheat.KindFlood,
+	})
+}
+
+// applyAntiCheat logs an anti-cheat event to the moderation audit log and
+// disconnects the client on a kick or flag, also auditing the action.
+// A flag puts the account in the admin review queue.
+func (r *Router) applyAntiCheat(client *ws.Client, ev anticheat.Event) {
+	slog.Warn("anti-cheat violation", "account_id", ev.AccountID, "player", ev.PlayerID, "room", ev.Room,
+		"kind", ev.Kind, "detail", ev.Detail, "score", ev.Score, "action", ev.Action)
+	if r.violations != nil {
+		r.violations.add(ev)
+	}
+	if ev.Action == anticheat.ActionRubberBand {
+		return
+	}
+
+	msg, _ := ws.NewMessage(ws.TypeKicked, kickedMessage{Reason: ev.Kind})
+	client.Kick(msg)
+	slog.Info("player kicked by anti-cheat", "account_id", ev.AccountID, "client", client.ID, "flagged", ev.Action == anticheat.ActionFlag)
+
+	if r.moderation != nil {
+		history := r.anticheat.History(ev.AccountID)
+		go func() {
+			// Write the violations leading up to the action first
+			if r.violations != nil {
+				r.violations.flush()
+			}
+			r.auditAntiCheat(ev, history)
+		}()
+	}
+}
+
+// FlushAntiCheat writes out scored violations not yet in the audit log.
+func (r *Router) FlushAntiCheat() {
+	if r.violations != nil {
+		r.violations.flush()
+	}
+}
+
+func (r *Router) auditAntiCheat(ev anticheat.Event, history []anticheat.Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	detail := map[string]any{
+		"score":  ev.Score,
+		"events": history,
+	}
+	action := moderation.ActionAntiCheatKick
+	if ev.Action == anticheat.ActionFlag {
+		action = moderation.ActionAntiCheatFlag
+		flag := moderation.NewFlag(ev.AccountID, anticheat.AuditActor, string(ev.Kind), detail)
+		if err := r.moderation.CreateFlag(ctx, flag); err != nil {
+			slog.Error("failed to flag account", "account_id", ev.AccountID, "error", err)
+		} else {
+			detail["flag_id"] = flag.ID
+		}
+	}
+	entry := moderation.NewAuditEntry(anticheat.AuditActor, action, ev.AccountID, detail)
+	if err := r.moderation.RecordAudit(ctx, entry); err != nil {
+		slog.Error("failed to record anti-cheat action", "account_id", ev.AccountID, "action", action, "error", err)
+	}
+}
+
+// violationLog batches scored violations into the moderation audit log.
+type violationLog struct {
+	store   store.ModerationStore
+	mu      sync.Mutex
+	pending []moderation.AuditEntry
+}
+
+func newViolationLog(store store.ModerationStore) *violationLog {
+	return &violationLog{store: store}
+}
+
+// add queues a violation, writing the batch once it is full or has waited
+// violationFlushInterval.
+func (l *violationLog) add(ev anticheat.Event) {
+	entry := moderation.NewAuditEntry(anticheat.AuditActor, moderation.ActionAntiCheatViolation, ev.AccountID, ev)
+
+	l.mu.Lock()
+	l.pending = append(l.pending, entry)
+	n := len(l.pending)
+	l.mu.Unlock()
+
+	switch {
+	case n >= violationBatchSize:
+		go l.flush()
+	case n == 1:
+		time.AfterFunc(violationFlushInterval, l.flush)
+	}
+}
+
+// flush writes all queued violations.
+func (l *violationLog) flush() {
+	l.mu.Lock()
+	batch := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := l.store.RecordAudits(ctx, batch); err != nil {
+		slog.Error("failed to record anti-cheat violations", "count", len(batch), "error", err)
+	}
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/anticheat"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// movePlayer sends a player_move one second after the player's last move,
+// so only the anti-cheat checks beyond speed can reject it.
+func movePlayer(router *Router, p *game.Player, client *ws.Client, x, y float64) {
+	p.LastMoveTime = time.Now().Add(-time.Second)
+	sendMessage(router, client, ws.TypePlayerMove, playerMoveRequest{X: x, Y: y})
+}
+
+func TestAntiCheat_TeleportThroughObstacle(t *testing.T) {
+	router, r, client, ch := setupGameplayTest()
+	r.PrepareGame()
+	r.MapObjects = []game.MapObject{{Type: "tree", X: 800, Y: 500}}
+	police := r.Players["player1"]
+	police.SetPosition(500, 500)
+	drainCh(ch)
+
+	movePlayer(router, police, client, 1000, 500)
+	resp := readResponse(t, ch)
+	require.Equal(t, ws.TypePositionCorrection, resp.Type)
+	assert.Equal(t, 500.0, police.X, "rejected moves are not applied")
+	assert.InDelta(t, 25.0, router.anticheat.Score(client.AccountID), 0.1)
+
+	// Walking around the tree is fine
+	movePlayer(router, police, client, 800, 700)
+	assert.Equal(t, ws.TypePlayerMove, readResponse(t, ch).Type)
+}
+
+func TestAntiCheat_SnapOntoThief(t *testing.T) {
+	router, r, client, ch := setupGameplayTest()
+	r.PrepareGame()
+	r.MapObjects = nil
+	police, thief := r.Players["player1"], r.Players["player2"]
+	police.SetPosition(500, 500)
+	thief.SetPosition(800, 500)
+	drainCh(ch)
+
+	movePlayer(router, police, client, 805, 500)
+	require.Equal(t, ws.TypePositionCorrection, readResponse(t, ch).Type)
+	history := router.anticheat.History(client.AccountID)
+	require.Len(t, history, 1)
+	assert.Equal(t, anticheat.KindArrestProximity, history[0].Kind)
+
+	// Closing in to arrest range is a normal chase
+	movePlayer(router, police, client, 710, 500)
+	assert.Equal(t, ws.TypePlayerMove, readResponse(t, ch).Type)
+}
+
+func TestAntiCheat_KickIsAudited(t *testing.T) {
+	router, r, client, ch := setupGameplayTest()
+	mod := &mockModerationStore{}
+	router.moderation = mod
+	router.violations = newViolationLog(mod)
+	client.AccountID = "acc-cheater"
+	r.PrepareGame()
+	police := r.Players["player1"]
+	drainCh(ch)
+
+	for i := 0; i < 4; i++ {
+		police.LastMoveTime = time.Now()
+		sendMessage(router, client, ws.TypePlayerMove, playerMoveRequest{X: 3000, Y: 3000})
+		require.Equal(t, ws.TypePositionCorrection, readResponse(t, ch).Type)
+	}
+	police.LastMoveTime = time.Now()
+	sendMessage(router, client, ws.TypePlayerMove, playerMoveRequest{X: 3000, Y: 3000})
+
+	resp := readResponse(t, ch)
+	require.Equal(t, ws.TypeKicked, resp.Type)
+	var kicked kickedMessage
+	require.NoError(t, json.Unmarshal(resp.Data, &kicked))
+	assert.Equal(t, anticheat.KindSpeed, kicked.Reason)
+	assert.False(t, client.Authenticated())
+
+	// The audit is written in the background, after the violations leading up to it
+	var entries []moderation.AuditEntry
+	require.Eventually(t, func() bool {
+		entries, _ = mod.ListAudit(t.Context(), moderation.AuditQuery{TargetID: "acc-cheater"})
+		return len(entries) == 6
+	}, time.Second, 10*time.Millisecond)
+	kick := entries[len(entries)-1]
+	assert.Equal(t, moderation.ActionAntiCheatKick, kick.Action)
+	assert.Equal(t, anticheat.AuditActor, kick.Actor)
+	assert.Contains(t, string(kick.Detail), `"kind":"speed"`)
+	for _, e := range entries[:len(entries)-1] {
+		assert.Equal(t, moderation.ActionAntiCheatViolation, e.Action)
+	}
+}
+
+func TestAntiCheat_RubberBandIsAudited(t *testing.T) {
+	router, r, client, ch := setupGameplayTest()
+	mod := &mockModerationStore{}
+	router.moderation = mod
+	router.violations = newViolationLog(mod)
+	client.AccountID = "acc-laggy"
+	r.PrepareGame()
+	police := r.Players["player1"]
+	drainCh(ch)
+
+	police.LastMoveTime = time.Now()
+	sendMessage(router, client, ws.TypePlayerMove, playerMoveRequest{X: 3000, Y: 3000})
+	require.Equal(t, ws.TypePositionCorrection, readResponse(t, ch).Type)
+
+	// Rubber-band violations wait for a batch
+	entries, _ := mod.ListAudit(t.Context(), moderation.AuditQuery{TargetID: "acc-laggy"})
+	assert.Empty(t, entries)
+
+	router.FlushAntiCheat()
+	entries, _ = mod.ListAudit(t.Context(), moderation.AuditQuery{TargetID: "acc-laggy"})
+	require.Len(t, entries, 1)
+	assert.Equal(t, moderation.ActionAntiCheatViolation, entries[0].Action)
+	assert.Contains(t, string(entries[0].Detail), `"action":"rubber_band"`)
+}
+
+func TestAntiCheat_Flood(t *testing.T) {
+	router, _ := setupLobbyTest(t)
+	client, ch := loginGuest(t, router, "c1", "Spammer")
+
+	router.HandleFlood(client)
+	assert.InDelta(t, 15.0, router.anticheat.Score(client.AccountID), 0.1)
+	assert.True(t, client.Authenticated(), "a single flood is only scored")
+
+	for i := 0; i < 3; i++ {
+		router.HandleFlood(client)
+	}
+	resp := readResponse(t, ch)
+	require.Equal(t, ws.TypeKicked, resp.Type)
+	var kicked kickedMessage
+	require.NoError(t, json.Unmarshal(resp.Data, &kicked))
+	assert.Equal(t, anticheat.KindFlood, kicked.Reason)
+	assert.True(t, client.Closed())
+}
+
+func TestAntiCheat_FloodBeforeAuthenticationIgnored(t *testing.T) {
+	router, _ := setupLobbyTest(t)
+	client := ws.NewClient("c1", nil, nil)
+
+	router.HandleFlood(client)
+	assert.False(t, client.Closed())
+	assert.Zero(t, router.anticheat.Score(client.AccountID))
+}
+
+func TestAntiCheat_FlagQueuedForReview(t *testing.T) {
+	a := setupAdminTest(t, "admin-1")
+	a.router.auditAntiCheat(anticheat.Event{
+		Violation: anticheat.Violation{AccountID: "acc-cheater", Kind: anticheat.KindSpeed},
+		Score:     95,
+		Action:    anticheat.ActionFlag,
+	}, nil)
+
+	rec := a.do(t, "admin-1", http.MethodGet, "/admin/flags?status=open", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var queue struct {
+		Flags []moderation.Flag `json:"flags"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &queue))
+	require.Len(t, queue.Flags, 1)
+	flag := queue.Flags[0]
+	assert.Equal(t, "acc-cheater", flag.AccountID)
+	assert.Equal(t, anticheat.AuditActor, flag.Source)
+	assert.Equal(t, string(anticheat.KindSpeed), flag.Reason)
+
+	entries, _ := a.store.ListAudit(t.Context(), moderation.AuditQuery{TargetID: "acc-cheater"})
+	require.Len(t, entries, 1)
+	assert.Contains(t, string(entries[0].Detail), `"flag_id":"`+flag.ID+`"`)
+
+	rec = a.do(t, "admin-1", http.MethodPost, "/admin/flags/"+flag.ID+"/review", `{"status":"open"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = a.do(t, "admin-1", http.MethodPost, "/admin/flags/"+flag.ID+"/review", `{"status":"actioned","note":"banned"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = a.do(t, "admin-1", http.MethodPost, "/admin/flags/"+flag.ID+"/review", `{"status":"dismissed"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = a.do(t, "admin-1", http.MethodPost, "/admin/flags/missing/review", `{"status":"dismissed"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = a.do(t, "admin-1", http.MethodGet, "/admin/flags?status=open", "")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &queue))
+	assert.Empty(t, queue.Flags)
+}
@@ -3,11 +3,14 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"time"
 
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/account"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/nickname"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/store"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
 )
@@ -16,30 +19,62 @@ const authTimeout = 10 * time.Second
 
 // AuthHandler handles authentication messages.
 type AuthHandler struct {
-	verifier *auth.GameCenterVerifier
-	store    store.AccountStore
+	providers *auth.Registry
+	guests    *auth.GuestCredentials
+	tokens    *auth.SessionTokens
+	nicknames *nickname.Policy
+	store     store.AccountStore
+	bans      store.ModerationStore // nil disables ban checks
+
+	// onAuthenticated is called after a client authenticates successfully.
+	onAuthenticated func(client *ws.Client)
 }
 
-// NewAuthHandler creates a new auth handler.
+// NewAuthHandler creates a new auth handler with the Game Center, guest and
+// session token providers. Guest credentials and session tokens are signed
+// with random keys until SetGuestCredentials and SetSessionTokens are called.
 func NewAuthHandler(verifier *auth.GameCenterVerifier, store store.AccountStore) *AuthHandler {
+	guests := auth.NewGuestCredentials(nil)
+	tokens := auth.NewSessionTokens(nil, auth.DefaultSessionTTL)
 	return &AuthHandler{
-		verifier: verifier,
-		store:    store,
+		providers: auth.NewRegistry(
+			auth.NewGameCenterProvider(verifier),
+			auth.NewGuestProvider(guests),
+			auth.NewTokenProvider(tokens),
+		),
+		guests:    guests,
+		tokens:    tokens,
+		nicknames: nickname.NewPolicy(nil),
+		store:     store,
 	}
 }
 
-type authenticateRequest struct {
-	Method string `json:"method"`
+// SetGuestCredentials replaces the signer used to issue and verify guest credentials.
+func (h *AuthHandler) SetGuestCredentials(guests *auth.GuestCredentials) {
+	h.guests = guests
+	h.providers.Register(auth.NewGuestProvider(guests))
+}
+
+// SetSessionTokens replaces the signer used to issue and verify session tokens.
+func (h *AuthHandler) SetSessionTokens(tokens *auth.SessionTokens) {
+	h.tokens = tokens
+	h.providers.Register(auth.NewTokenProvider(tokens))
+}
+
+// SetNicknamePolicy replaces the policy applied to nicknames of new accounts.
+func (h *AuthHandler) SetNicknamePolicy(p *nickname.Policy) {
+	h.nicknames = p
+}
 
-	// Game Center fields
-	PlayerID     string `json:"player_id,omitempty"`
-	BundleID     string `json:"bundle_id,omitempty"`
-	PublicKeyURL string `json:"public_key_url,omitempty"`
-	Signature    string `json:"signature,omitempty"`
-	Salt         string `json:"salt,omitempty"`
-	Timestamp    uint64 `json:"timestamp,omitempty"`
+// RegisterProvider adds an auth provider, replacing one with the same name.
+func (h *AuthHandler) RegisterProvider(p auth.AuthProvider) {
+	h.providers.Register(p)
+}
 
-	// Guest fields
+// authenticateRequest holds the fields common to every method.
+// Each provider reads its own credential fields from the same data.
+type authenticateRequest struct {
+	Method   string `json:"method"`
 	Nickname string `json:"nickname,omitempty"`
 }
 
@@ -47,16 +82,46 @@ type authSuccessResponse struct {
 	Success   bool   `json:"success"`
 	AccountID string `json:"account_id"`
 	Nickname  string `json:"nickname"`
+
+	// SessionToken signs the client back in with method "token" and
+	// authorizes HTTP API calls as a bearer token until it expires.
+	SessionToken     string    `json:"session_token"`
+	SessionExpiresAt time.Time `json:"session_expires_at"`
+
+	// GuestCredential lets a guest sign back in to the same account.
+	GuestCredential string `json:"guest_credential,omitempty"`
+}
+
+type sessionTokenRequest struct {
+	Token string `json:"token"`
+	All   bool   `json:"all,omitempty"` // revoke_token only: revoke every token of the account
+}
+
+type sessionTokenResponse struct {
+	SessionToken     string    `json:"session_token"`
+	SessionExpiresAt time.Time `json:"session_expires_at"`
 }
 
 type authFailureResponse struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error"`
+	Success bool       `json:"success"`
+	Error   string     `json:"error"`
+	Ban     *banNotice `json:"ban,omitempty"`
+}
+
+// banNotice tells a banned player why and until when.
+type banNotice struct {
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Permanent bool       `json:"permanent"`
+}
+
+func newBanNotice(ban *moderation.Ban) *banNotice {
+	return &banNotice{Reason: ban.Reason, ExpiresAt: ban.ExpiresAt, Permanent: ban.Permanent}
 }
 
 // HandleAuthenticate processes an authentication request.
 func (h *AuthHandler) HandleAuthenticate(client *ws.Client, msg ws.Message) {
-	if client.Authenticated {
+	if client.Authenticated() {
 		client.SendMessage(ws.NewErrorMessage("이미 인증되었습니다"))
 		return
 	}
@@ -67,90 +132,235 @@ func (h *AuthHandler) HandleAuthenticate(client *ws.Client, msg ws.Message) {
 		return
 	}
 
-	switch req.Method {
-	case "game_center":
-		h.handleGameCenter(client, req)
-	case "guest":
-		h.handleGuest(client, req)
-	default:
-		h.sendFailure(client, "알 수 없는 인증 방식: "+req.Method)
-	}
-}
-
-func (h *AuthHandler) handleGameCenter(client *ws.Client, req authenticateRequest) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cred := &auth.GameCenterCredential{
-		PlayerID:     req.PlayerID,
-		BundleID:     req.BundleID,
-		PublicKeyURL: req.PublicKeyURL,
-		Signature:    req.Signature,
-		Salt:         req.Salt,
-		Timestamp:    req.Timestamp,
+	id, err := h.providers.Authenticate(ctx, req.Method, msg.Data)
+	if err != nil {
+		slog.Warn("authentication failed", "method", req.Method, "error", err, "client", client.ID)
+		h.sendFailure(client, authErrorMessage(req.Method, err))
+		return
 	}
 
-	if err := h.verifier.Verify(ctx, cred); err != nil {
-		slog.Warn("game center verification failed", "error", err, "client", client.ID)
-		h.sendFailure(client, "인증 검증에 실패했습니다")
+	acc, err := h.resolveAccount(ctx, id)
+	if isAuthRequestError(err) {
+		h.sendFailure(client, authErrorMessage(req.Method, err))
 		return
 	}
-
-	// Find or create account
-	acc, err := h.store.FindByGameCenterID(ctx, req.PlayerID)
 	if err != nil {
-		slog.Error("failed to find account", "error", err)
+		slog.Error("failed to resolve account", "method", req.Method, "error", err)
 		h.sendFailure(client, "서버 내부 오류입니다")
 		return
 	}
 
-	if acc == nil {
-		acc = account.NewGameCenterAccount(req.PlayerID, req.Nickname)
-		if err := h.store.Create(ctx, acc); err != nil {
-			slog.Error("failed to create account", "error", err)
-			h.sendFailure(client, "서버 내부 오류입니다")
-			return
+	h.authenticateClient(ctx, client, acc)
+}
+
+// resolveAccount finds the account linked to an identity, creating it on
+// first sign-in. Guests are identified by their own account ID; a guest
+// without a subject, or whose account no longer exists, gets a new one.
+func (h *AuthHandler) resolveAccount(ctx context.Context, id *auth.Identity) (*account.Account, error) {
+	if id.AccountID != "" {
+		acc, err := h.store.FindByID(ctx, id.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		if acc == nil {
+			return nil, auth.ErrInvalidToken
 		}
-		slog.Info("new game center account created", "account_id", acc.ID, "gc_id", req.PlayerID)
-	} else {
 		_ = h.store.UpdateLastLogin(ctx, acc.ID)
+		return acc, nil
+	}
+	if id.Subject == "" {
+		return h.createGuest(ctx, id)
 	}
 
-	h.authenticateClient(client, acc)
+	acc, err := h.store.FindByIdentity(ctx, id.Provider, id.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if acc != nil {
+		_ = h.store.UpdateLastLogin(ctx, acc.ID)
+		return acc, nil
+	}
+	if id.Provider == auth.ProviderGuest {
+		slog.Warn("guest credential for unknown account, starting a new guest", "account_id", id.Subject)
+		return h.createGuest(ctx, id)
+	}
+
+	name := nickname.Generate()
+	if id.Nickname != "" {
+		if name, err = h.nicknames.Validate(id.Nickname); err != nil {
+			return nil, err
+		}
+	}
+	if id.Provider == auth.ProviderGameCenter {
+		acc = account.NewGameCenterAccount(id.Subject, name)
+	} else {
+		acc = account.NewAccount(name)
+	}
+	acc.IsGuest = id.Guest
+	if err := h.store.CreateWithIdentity(ctx, acc, id.Provider, id.Subject); err != nil {
+		return nil, err
+	}
+	slog.Info("new account created", "account_id", acc.ID, "provider", id.Provider)
+	return acc, nil
 }
 
-func (h *AuthHandler) handleGuest(client *ws.Client, req authenticateRequest) {
-	if req.Nickname == "" {
-		h.sendFailure(client, "게스트 로그인에는 닉네임이 필요합니다")
-		return
+// createGuest creates a guest account linked to a guest identity under its own ID.
+func (h *AuthHandler) createGuest(ctx context.Context, id *auth.Identity) (*account.Account, error) {
+	if id.Nickname == "" {
+		return nil, auth.ErrNicknameRequired
+	}
+	name, err := h.nicknames.Validate(id.Nickname)
+	if err != nil {
+		return nil, err
+	}
+	acc := account.NewGuestAccount(name)
+	if err := h.store.CreateWithIdentity(ctx, acc, auth.ProviderGuest, acc.ID); err != nil {
+		return nil, err
 	}
+	slog.Info("new guest account created", "account_id", acc.ID, "nickname", acc.Nickname)
+	return acc, nil
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// isAuthRequestError reports whether an account resolution error is the
+// client's to fix rather than a server failure.
+func isAuthRequestError(err error) bool {
+	return errors.Is(err, auth.ErrNicknameRequired) || errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, nickname.ErrInvalid)
+}
+
+// authErrorMessage maps a provider error to a user-facing message.
+func authErrorMessage(method string, err error) string {
+	switch {
+	case errors.Is(err, auth.ErrUnknownProvider):
+		return "알 수 없는 인증 방식: " + method
+	case errors.Is(err, auth.ErrNicknameRequired):
+		return "게스트 로그인에는 닉네임이 필요합니다"
+	case errors.Is(err, auth.ErrInvalidRequest):
+		return "잘못된 인증 데이터입니다"
+	case errors.Is(err, nickname.ErrInvalid):
+		return nicknameErrorMessage(err)
+	case errors.Is(err, auth.ErrTokenExpired), errors.Is(err, auth.ErrTokenRevoked), errors.Is(err, auth.ErrInvalidToken):
+		return "세션이 만료되었습니다. 다시 로그인해주세요"
+	default:
+		return "인증 검증에 실패했습니다"
+	}
+}
+
+// activeBan returns the ban in force on an account, or nil. Every path that
+// signs a client in to an account must check it. A nil store disables bans.
+func activeBan(ctx context.Context, bans store.ModerationStore, accountID string) (*moderation.Ban, error) {
+	if bans == nil {
+		return nil, nil
+	}
+	return bans.ActiveBan(ctx, accountID)
+}
 
-	acc := account.NewGuestAccount(req.Nickname)
-	if err := h.store.Create(ctx, acc); err != nil {
-		slog.Error("failed to create guest account", "error", err)
+// authenticateClient signs the client in to acc unless the account is banned.
+func (h *AuthHandler) authenticateClient(ctx context.Context, client *ws.Client, acc *account.Account) {
+	ban, err := activeBan(ctx, h.bans, acc.ID)
+	if err != nil {
+		slog.Error("failed to check bans", "account_id", acc.ID, "error", err)
 		h.sendFailure(client, "서버 내부 오류입니다")
 		return
 	}
+	if ban != nil {
+		slog.Info("banned account rejected", "account_id", acc.ID, "ban", ban.ID, "client", client.ID)
+		resp, _ := ws.NewMessage(ws.TypeAuthResult, authFailureResponse{
+			Success: false,
+			Error:   "이용이 제한된 계정입니다",
+			Ban:     newBanNotice(ban),
+		})
+		client.SendMessage(resp)
+		return
+	}
+
+	client.AccountID = acc.ID
+	client.Nickname = acc.Nickname
+	client.IsGuest = acc.IsGuest
+	client.MarkAuthenticated()
+	if h.onAuthenticated != nil {
+		h.onAuthenticated(client)
+	}
+
+	token, claims := h.tokens.Issue(acc.ID)
+	res := authSuccessResponse{
+		Success:          true,
+		AccountID:        acc.ID,
+		Nickname:         acc.Nickname,
+		SessionToken:     token,
+		SessionExpiresAt: claims.Expiry(),
+	}
+	if acc.IsGuest {
+		res.GuestCredential = h.guests.Issue(acc.ID)
+	}
+	resp, _ := ws.NewMessage(ws.TypeAuthResult, res)
+	client.SendMessage(resp)
 
-	slog.Info("new guest account created", "account_id", acc.ID, "nickname", req.Nickname)
-	h.authenticateClient(client, acc)
+	slog.Info("client authenticated", "client", client.ID, "account_id", acc.ID)
 }
 
-func (h *AuthHandler) authenticateClient(client *ws.Client, acc *account.Account) {
-	client.AccountID = acc.ID
-	client.Authenticated = true
+// HandleRefreshToken exchanges one of the client's session tokens for a new one.
+func (h *AuthHandler) HandleRefreshToken(client *ws.Client, msg ws.Message) {
+	var req sessionTokenRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil || req.Token == "" {
+		client.SendMessage(ws.NewErrorMessage("세션 토큰을 입력해주세요"))
+		return
+	}
+	old, err := h.tokens.Verify(req.Token)
+	if err != nil || old.AccountID != client.AccountID {
+		client.SendMessage(ws.NewErrorMessage("세션이 만료되었습니다. 다시 로그인해주세요"))
+		return
+	}
 
-	resp, _ := ws.NewMessage(ws.TypeAuthResult, authSuccessResponse{
-		Success:   true,
-		AccountID: acc.ID,
-		Nickname:  acc.Nickname,
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.tokens.Revoke(ctx, old); err != nil {
+		slog.Error("failed to persist session token revocation", "account_id", client.AccountID, "error", err)
+	}
+	token, claims := h.tokens.Issue(client.AccountID)
+	resp, _ := ws.NewMessage(ws.TypeRefreshToken, sessionTokenResponse{
+		SessionToken:     token,
+		SessionExpiresAt: claims.Expiry(),
 	})
 	client.SendMessage(resp)
+}
 
-	slog.Info("client authenticated", "client", client.ID, "account_id", acc.ID)
+// HandleRevokeToken revokes one of the client's session tokens, or all of
+// them, so they can no longer sign in or call HTTP APIs.
+func (h *AuthHandler) HandleRevokeToken(client *ws.Client, msg ws.Message) {
+	var req sessionTokenRequest
+	if len(msg.Data) > 0 {
+		if err := json.Unmarshal(msg.Data, &req); err != nil {
+			client.SendMessage(ws.NewErrorMessage("잘못된 요청입니다"))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var err error
+	switch {
+	case req.All:
+		err = h.tokens.RevokeAccount(ctx, client.AccountID)
+	case req.Token != "":
+		claims, verr := h.tokens.Verify(req.Token)
+		if verr == nil && claims.AccountID == client.AccountID {
+			err = h.tokens.Revoke(ctx, claims)
+		}
+	default:
+		client.SendMessage(ws.NewErrorMessage("폐기할 세션 토큰을 지정해주세요"))
+		return
+	}
+	if err != nil {
+		slog.Error("failed to persist session token revocation", "account_id", client.AccountID, "error", err)
+	}
+
+	resp, _ := ws.NewMessage(ws.TypeRevokeToken, nil)
+	client.SendMessage(resp)
+	slog.Info("session tokens revoked", "account_id", client.AccountID, "all", req.All)
 }
 
 func (h *AuthHandler) sendFailure(client *ws.Client, errMsg string) {
@@ -164,7 +374,7 @@ func (h *AuthHandler) sendFailure(client *ws.Client, errMsg string) {
 // StartAuthTimeout closes the connection if the client doesn't authenticate in time.
 func (h *AuthHandler) StartAuthTimeout(client *ws.Client) {
 	time.AfterFunc(authTimeout, func() {
-		if !client.Authenticated {
+		if !client.Authenticated() {
 			slog.Info("auth timeout, closing connection", "client", client.ID)
 			client.SendMessage(ws.NewErrorMessage("인증 시간이 초과되었습니다"))
 			client.Conn.Close()
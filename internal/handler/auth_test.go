@@ -16,14 +16,16 @@ import (
 
 // mockAccountStore implements store.AccountStore for testing.
 type mockAccountStore struct {
-	accounts map[string]*account.Account // id -> account
-	byGCID   map[string]*account.Account // game_center_id -> account
+	accounts   map[string]*account.Account    // id -> account
+	byGCID     map[string]*account.Account    // game_center_id -> account
+	identities map[[2]string]*account.Account // provider, subject -> account
 }
 
 func newMockAccountStore() *mockAccountStore {
 	return &mockAccountStore{
-		accounts: make(map[string]*account.Account),
-		byGCID:   make(map[string]*account.Account),
+		accounts:   make(map[string]*account.Account),
+		byGCID:     make(map[string]*account.Account),
+		identities: make(map[[2]string]*account.Account),
 	}
 }
 
@@ -43,8 +45,42 @@ func (m *mockAccountStore) Create(_ context.Context, acc *account.Account) error
 	return nil
 }
 
+func (m *mockAccountStore) FindByIdentity(_ context.Context, provider, subject string) (*account.Account, error) {
+	return m.identities[[2]string{provider, subject}], nil
+}
+
+func (m *mockAccountStore) CreateWithIdentity(ctx context.Context, acc *account.Account, provider, subject string) error {
+	m.identities[[2]string{provider, subject}] = acc
+	return m.Create(ctx, acc)
+}
+
+func (m *mockAccountStore) LinkIdentity(_ context.Context, accountID, provider, subject string) error {
+	acc := m.accounts[accountID]
+	m.identities[[2]string{provider, subject}] = acc
+	delete(m.identities, [2]string{auth.ProviderGuest, accountID})
+	acc.IsGuest = false
+	if provider == auth.ProviderGameCenter {
+		acc.GameCenterID = &subject
+		m.byGCID[subject] = acc
+	}
+	return nil
+}
+
+func (m *mockAccountStore) MergeAccounts(_ context.Context, fromID, _ string) error {
+	delete(m.accounts, fromID)
+	delete(m.identities, [2]string{auth.ProviderGuest, fromID})
+	return nil
+}
+
 func (m *mockAccountStore) UpdateLastLogin(_ context.Context, _ string) error { return nil }
-func (m *mockAccountStore) UpdateNickname(_ context.Context, _, _ string) error { return nil }
+func (m *mockAccountStore) UpdateNickname(_ context.Context, id, nickname string) error {
+	if acc := m.accounts[id]; acc != nil {
+		now := time.Now()
+		acc.Nickname = nickname
+		acc.NicknameChangedAt = &now
+	}
+	return nil
+}
 func (m *mockAccountStore) Close() error { return nil }
 
 // mockClient creates a test client that captures sent messages.
@@ -107,7 +143,7 @@ func TestHandleAuthenticate_Guest(t *testing.T) {
 	assert.NotEmpty(t, result.AccountID)
 	assert.Equal(t, "테스트유저", result.Nickname)
 
-	assert.True(t, client.Authenticated)
+	assert.True(t, client.Authenticated())
 	assert.Equal(t, result.AccountID, client.AccountID)
 
 	// Verify account was created in store
@@ -133,7 +169,7 @@ func TestHandleAuthenticate_GuestNoNickname(t *testing.T) {
 	require.NoError(t, json.Unmarshal(resp.Data, &result))
 	assert.False(t, result.Success)
 	assert.Contains(t, result.Error, "nickname is required")
-	assert.False(t, client.Authenticated)
+	assert.False(t, client.Authenticated())
 }
 
 func TestHandleAuthenticate_AlreadyAuthenticated(t *testing.T) {
@@ -142,7 +178,7 @@ func TestHandleAuthenticate_AlreadyAuthenticated(t *testing.T) {
 	handler := NewAuthHandler(verifier, store)
 
 	client, ch := newTestClient("test-client-3")
-	client.Authenticated = true
+	client.MarkAuthenticated()
 
 	data, _ := json.Marshal(authenticateRequest{Method: "guest", Nickname: "test"})
 	msg := ws.Message{Type: ws.TypeAuthenticate, Data: data}
@@ -177,7 +213,7 @@ func TestAuthGuard_UnauthenticatedBlocked(t *testing.T) {
 	verifier := auth.NewGameCenterVerifier(nil, 0)
 
 	rm := room.NewManager()
-	router := NewRouter(rm, verifier, store)
+	router := NewRouter(rm, verifier, store, Options{})
 
 	client, ch := newTestClient("test-client-5")
 
@@ -200,7 +236,7 @@ func TestAuthGuard_AuthenticatedAllowed(t *testing.T) {
 	verifier := auth.NewGameCenterVerifier(nil, 0)
 
 	rm := room.NewManager()
-	router := NewRouter(rm, verifier, store)
+	router := NewRouter(rm, verifier, store, Options{})
 
 	client, ch := newTestClient("test-client-6")
 
@@ -212,7 +248,7 @@ func TestAuthGuard_AuthenticatedAllowed(t *testing.T) {
 	// Read auth response
 	authResp := readResponse(t, ch)
 	assert.Equal(t, ws.TypeAuthResult, authResp.Type)
-	assert.True(t, client.Authenticated)
+	assert.True(t, client.Authenticated())
 
 	// Now create room should work
 	roomData, _ := json.Marshal(map[string]string{"nickname": "플레이어"})
@@ -222,3 +258,136 @@ func TestAuthGuard_AuthenticatedAllowed(t *testing.T) {
 	roomResp := readResponse(t, ch)
 	assert.Equal(t, ws.TypeCreateRoom, roomResp.Type)
 }
+
+func TestSession_NewestReplacesOld(t *testing.T) {
+	router, rm := setupLobbyTest(t)
+	old, oldCh := loginGuest(t, router, "c1", "Alice")
+	sendMessage(router, old, ws.TypeCreateRoom, map[string]string{"nickname": "Alice"})
+	require.Equal(t, ws.TypeCreateRoom, readResponse(t, oldCh).Type)
+
+	// A second connection authenticates as the same account
+	newer, newerCh := newTestClient("c2")
+	newer.AccountID = old.AccountID
+	newer.MarkAuthenticated()
+	router.registerAccount(newer)
+
+	assert.Equal(t, ws.TypeSessionReplaced, readResponse(t, oldCh).Type)
+	assert.True(t, old.Closed())
+	assert.False(t, old.Authenticated())
+	assert.Same(t, newer, router.ClientByAccount(old.AccountID))
+
+	// The old connection's disconnect leaves the new session in place
+	router.HandleDisconnect(old)
+	assert.Same(t, newer, router.ClientByAccount(old.AccountID))
+	assert.Equal(t, 0, rm.RoomCount())
+
+	sendMessage(router, newer, ws.TypeCreateRoom, map[string]string{"nickname": "Alice"})
+	assert.Equal(t, ws.TypeCreateRoom, readResponse(t, newerCh).Type)
+}
+
+// fakeProvider proves whatever subject the client sends.
+type fakeProvider struct{}
+
+func (fakeProvider) Name() string { return "fake" }
+
+func (fakeProvider) Authenticate(_ context.Context, data json.RawMessage) (*auth.Identity, error) {
+	var req struct {
+		Subject  string `json:"subject"`
+		Nickname string `json:"nickname"`
+	}
+	json.Unmarshal(data, &req)
+	return &auth.Identity{Provider: "fake", Subject: req.Subject, Nickname: req.Nickname}, nil
+}
+
+func TestHandleAuthenticate_ProviderIdentityReused(t *testing.T) {
+	store := newMockAccountStore()
+	router := NewRouter(room.NewManager(), auth.NewGameCenterVerifier(nil, 0), store, Options{
+		AuthProviders: []auth.AuthProvider{fakeProvider{}},
+	})
+
+	login := func(clientID string) authSuccessResponse {
+		client, ch := newTestClient(clientID)
+		sendMessage(router, client, ws.TypeAuthenticate, map[string]string{"method": "fake", "subject": "sub-1", "nickname": "첫이름"})
+		resp := readResponse(t, ch)
+		require.Equal(t, ws.TypeAuthResult, resp.Type)
+		var result authSuccessResponse
+		require.NoError(t, json.Unmarshal(resp.Data, &result))
+		require.True(t, result.Success)
+		return result
+	}
+
+	first := login("c1")
+	second := login("c2")
+	assert.Equal(t, first.AccountID, second.AccountID)
+	assert.Equal(t, "첫이름", second.Nickname)
+	assert.Len(t, store.accounts, 1)
+	assert.False(t, store.accounts[first.AccountID].IsGuest)
+}
+
+func TestHandleAuthenticate_GuestCredentialRestoresAccount(t *testing.T) {
+	store := newMockAccountStore()
+	handler := NewAuthHandler(auth.NewGameCenterVerifier(nil, 0), store)
+
+	login := func(clientID string, data map[string]string) authSuccessResponse {
+		client, ch := newTestClient(clientID)
+		raw, _ := json.Marshal(data)
+		handler.HandleAuthenticate(client, ws.Message{Type: ws.TypeAuthenticate, Data: raw})
+		var result authSuccessResponse
+		require.NoError(t, json.Unmarshal(readResponse(t, ch).Data, &result))
+		require.True(t, result.Success)
+		return result
+	}
+
+	first := login("c1", map[string]string{"method": "guest", "nickname": "손님"})
+	require.NotEmpty(t, first.GuestCredential)
+
+	again := login("c2", map[string]string{"method": "guest", "credential": first.GuestCredential})
+	assert.Equal(t, first.AccountID, again.AccountID)
+	assert.Equal(t, "손님", again.Nickname)
+
+	forged := login("c3", map[string]string{"method": "guest", "nickname": "새손님", "credential": first.AccountID + ".forged"})
+	assert.NotEqual(t, first.AccountID, forged.AccountID)
+	assert.Len(t, store.accounts, 2)
+}
+
+func TestSessionToken_ReconnectRefreshRevoke(t *testing.T) {
+	router, _ := setupLobbyTest(t)
+
+	client, ch := newTestClient("c1")
+	sendMessage(router, client, ws.TypeAuthenticate, map[string]string{"method": "guest", "nickname": "손님"})
+	var first authSuccessResponse
+	require.NoError(t, json.Unmarshal(readResponse(t, ch).Data, &first))
+	require.NotEmpty(t, first.SessionToken)
+	assert.True(t, first.SessionExpiresAt.After(time.Now()))
+
+	// Each successful reconnect becomes the account's active session
+	reconnect := func(clientID, token string) (*ws.Client, chan sentMessage, authFailureResponse) {
+		c, cch := newTestClient(clientID)
+		sendMessage(router, c, ws.TypeAuthenticate, map[string]string{"method": "token", "token": token})
+		var res authFailureResponse
+		require.NoError(t, json.Unmarshal(readResponse(t, cch).Data, &res))
+		if res.Success {
+			assert.Equal(t, first.AccountID, c.AccountID)
+		}
+		return c, cch, res
+	}
+	client, ch, res := reconnect("c2", first.SessionToken)
+	require.True(t, res.Success)
+
+	// Refreshing revokes the old token
+	sendMessage(router, client, ws.TypeRefreshToken, map[string]string{"token": first.SessionToken})
+	resp := readResponse(t, ch)
+	require.Equal(t, ws.TypeRefreshToken, resp.Type)
+	var refreshed sessionTokenResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &refreshed))
+	_, _, res = reconnect("c3", first.SessionToken)
+	assert.False(t, res.Success)
+	client, ch, res = reconnect("c4", refreshed.SessionToken)
+	require.True(t, res.Success)
+
+	sendMessage(router, client, ws.TypeRevokeToken, map[string]bool{"all": true})
+	require.Equal(t, ws.TypeRevokeToken, readResponse(t, ch).Type)
+	_, _, res = reconnect("c5", refreshed.SessionToken)
+	assert.False(t, res.Success)
+	assert.Equal(t, "세션이 만료되었습니다. 다시 로그인해주세요", res.Error)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+)
+
+type accountIDKey struct{}
+
+// RequireBearer rejects HTTP requests without a valid session token in the
+// Authorization header and passes the token's account to next.
+func RequireBearer(tokens *auth.SessionTokens, next http.Handler) http.Handler {
+	return bearer(tokens, next, true)
+}
+
+// OptionalBearer passes requests without an Authorization header to next
+// anonymously. A request that sends a token must still send a valid one.
+func OptionalBearer(tokens *auth.SessionTokens, next http.Handler) http.Handler {
+	return bearer(tokens, next, false)
+}
+
+func bearer(tokens *auth.SessionTokens, next http.Handler, required bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" && !required {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSONError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+		claims, err := tokens.Verify(token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeJSONError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), accountIDKey{}, claims.AccountID)))
+	})
+}
+
+// AccountIDFromContext returns the account authenticated by RequireBearer
+// or OptionalBearer, or empty string if the request was not authenticated.
+func AccountIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(accountIDKey{}).(string)
+	return id
+}
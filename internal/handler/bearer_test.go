@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+)
+
+func TestRequireBearer(t *testing.T) {
+	tokens := auth.NewSessionTokens(nil, time.Minute)
+	var seen string
+	h := RequireBearer(tokens, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = AccountIDFromContext(r.Context())
+	}))
+
+	serve := func(header string) int {
+		req := httptest.NewRequest(http.MethodGet, "/leaderboard", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve(""))
+	assert.Equal(t, http.StatusUnauthorized, serve("Bearer nope"))
+
+	token, claims := tokens.Issue("acc-1")
+	assert.Equal(t, http.StatusOK, serve("Bearer "+token))
+	assert.Equal(t, "acc-1", seen)
+
+	assert.NoError(t, tokens.Revoke(context.Background(), claims))
+	assert.Equal(t, http.StatusUnauthorized, serve("Bearer "+token))
+}
+
+func TestOptionalBearer(t *testing.T) {
+	tokens := auth.NewSessionTokens(nil, time.Minute)
+	seen := "unset"
+	h := OptionalBearer(tokens, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = AccountIDFromContext(r.Context())
+	}))
+
+	serve := func(header string) int {
+		req := httptest.NewRequest(http.MethodGet, "/leaderboard", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(""))
+	assert.Empty(t, seen, "anonymous requests have no account")
+	assert.Equal(t, http.StatusUnauthorized, serve("Bearer nope"))
+
+	token, _ := tokens.Issue("acc-1")
+	assert.Equal(t, http.StatusOK, serve("Bearer "+token))
+	assert.Equal(t, "acc-1", seen)
+}
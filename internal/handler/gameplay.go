@@ -2,9 +2,11 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/anticheat"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
@@ -57,12 +59,19 @@ func (h *GameplayHandler) HandlePlayerMove(client *ws.Client, msg ws.Message) {
 		return
 	}
 
+	r.TouchPlayer(playerID)
+
 	// Only allow movement during playing state
-	if r.State != game.StatePlaying {
+	if r.CurrentState() != game.StatePlaying {
 		client.SendMessage(ws.NewErrorMessage("게임이 진행 중이 아닙니다"))
 		return
 	}
 
+	if r.AwaitingLoad(playerID) {
+		client.SendMessage(ws.NewErrorMessage("맵을 불러오는 중입니다"))
+		return
+	}
+
 	player := r.Players[playerID]
 	if player == nil {
 		return
@@ -85,12 +94,22 @@ func (h *GameplayHandler) HandlePlayerMove(client *ws.Client, msg ws.Message) {
 	}
 	maxDist := speed * elapsed * 1.5 // 50% tolerance for network jitter
 	if dist > maxDist {
-		slog.Warn("speed violation", "player", playerID, "dist", dist, "maxDist", maxDist)
-		client.SendMessage(ws.NewErrorMessage("이동 속도가 너무 빠릅니다"))
+		h.rejectMove(client, r, player, anticheat.KindSpeed, fmt.Sprintf("moved %.0fpx, max %.0fpx", dist, maxDist))
+		return
+	}
+	if r.CrossesObstacle(player.X, player.Y, req.X, req.Y) {
+		h.rejectMove(client, r, player, anticheat.KindTeleport,
+			fmt.Sprintf("(%.0f, %.0f) -> (%.0f, %.0f)", player.X, player.Y, req.X, req.Y))
 		return
 	}
+	if player.Role == game.RolePolice {
+		if thief := r.SnappedOntoThief(player, req.X, req.Y); thief != nil {
+			h.rejectMove(client, r, player, anticheat.KindArrestProximity, "onto thief "+thief.ID)
+			return
+		}
+	}
 
-	player.SetPosition(req.X, req.Y)
+	player.Move(req.X, req.Y, now)
 	player.LastMoveTime = now
 
 	// Broadcast movement to other players in the room
@@ -103,3 +122,42 @@ func (h *GameplayHandler) HandlePlayerMove(client *ws.Client, msg ws.Message) {
 
 	slog.Debug("player moved", "player", playerID, "x", req.X, "y", req.Y)
 }
+
+// rejectMove scores a suspicious move and, unless the player was kicked,
+// sends them back to their last valid position.
+func (h *GameplayHandler) rejectMove(client *ws.Client, r *room.Room, player *game.Player, kind anticheat.Kind, detail string) {
+	action := h.router.reportViolation(client, anticheat.Violation{
+		AccountID: client.AccountID,
+		PlayerID:  player.ID,
+		Room:      r.Code,
+		Kind:      kind,
+		Detail:    detail,
+	})
+	if action != anticheat.ActionRubberBand {
+		return
+	}
+	msg, _ := ws.NewMessage(ws.TypePositionCorrection, playerMoveResponse{
+		PlayerID: player.ID,
+		X:        player.X,
+		Y:        player.Y,
+	})
+	client.SendMessage(msg)
+}
+
+// HandleClientLoaded marks the player as having loaded the map.
+// Movement is accepted only after this message.
+func (h *GameplayHandler) HandleClientLoaded(client *ws.Client, _ ws.Message) {
+	playerID := h.router.GetPlayerID(client.ID)
+	r := h.rm.FindRoomByPlayerID(playerID)
+	if r == nil {
+		client.SendMessage(ws.NewErrorMessage("방에 참가하고 있지 않습니다"))
+		return
+	}
+
+	r.TouchPlayer(playerID)
+	if !r.MarkLoaded(playerID) {
+		slog.Debug("unexpected client_loaded", "player", playerID, "room", r.Code)
+		return
+	}
+	slog.Info("client loaded", "player", playerID, "room", r.Code)
+}
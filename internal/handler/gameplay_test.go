@@ -18,14 +18,14 @@ func setupGameplayTest() (*Router, *room.Room, *ws.Client, chan sentMessage) {
 	store := newMockAccountStore()
 	verifier := auth.NewGameCenterVerifier(nil, 0)
 	rm := room.NewManager()
-	router := NewRouter(rm, verifier, store)
+	router := NewRouter(rm, verifier, store, Options{})
 
 	r := rm.CreateRoom()
 	client := &ws.Client{
-		ID:            "test-client",
-		Send:          make(chan []byte, 256),
-		Authenticated: true,
+		ID:   "test-client",
+		Send: make(chan []byte, 256),
 	}
+	client.MarkAuthenticated()
 
 	player := &game.Player{
 		ID:       "player1",
@@ -42,10 +42,10 @@ func setupGameplayTest() (*Router, *room.Room, *ws.Client, chan sentMessage) {
 
 	// Add a second player so the room can enter playing state
 	client2 := &ws.Client{
-		ID:            "test-client-2",
-		Send:          make(chan []byte, 256),
-		Authenticated: true,
+		ID:   "test-client-2",
+		Send: make(chan []byte, 256),
 	}
+	client2.MarkAuthenticated()
 	player2 := &game.Player{
 		ID:       "player2",
 		Nickname: "Test2",
@@ -164,11 +164,13 @@ func TestHandlePlayerMove_SpeedViolation(t *testing.T) {
 	for resp.Type == ws.TypeGameState {
 		resp = readResponseWithTimeout(t, ch, 500*time.Millisecond)
 	}
-	assert.Equal(t, ws.TypeError, resp.Type)
+	assert.Equal(t, ws.TypePositionCorrection, resp.Type)
 
-	var errMsg ws.ErrorMessage
-	json.Unmarshal(resp.Data, &errMsg)
-	assert.Equal(t, "movement too fast", errMsg.Message)
+	// The player is sent back to the last valid position
+	var correction playerMoveResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &correction))
+	assert.Equal(t, 100.0, correction.X)
+	assert.Equal(t, 100.0, correction.Y)
 }
 
 func TestHandlePlayerMove_NotPlaying(t *testing.T) {
@@ -209,3 +211,32 @@ func readResponseWithTimeout(t *testing.T, ch chan sentMessage, timeout time.Dur
 		return sentMessage{}
 	}
 }
+
+func TestHandleClientLoaded(t *testing.T) {
+	router, r, client, ch := setupGameplayTest()
+	r.StartRound()
+	defer r.SetPlayerReady("player1", false) // cancel the countdown
+
+	// Movement is rejected during the countdown
+	data, _ := json.Marshal(playerMoveRequest{X: 510, Y: 510})
+	rawMsg, _ := json.Marshal(ws.Message{Type: ws.TypePlayerMove, Data: data})
+	router.HandleMessage(&ws.ClientMessage{Client: client, Data: rawMsg})
+
+	require.True(t, r.AwaitingLoad("player1"))
+	loadedMsg, _ := json.Marshal(ws.Message{Type: ws.TypeClientLoaded})
+	router.HandleMessage(&ws.ClientMessage{Client: client, Data: loadedMsg})
+	assert.False(t, r.AwaitingLoad("player1"))
+	assert.True(t, r.AwaitingLoad("player2"))
+
+	var types []string
+	for i := 0; i < 10; i++ {
+		resp := readResponseWithTimeout(t, ch, 500*time.Millisecond)
+		types = append(types, resp.Type)
+		if resp.Type == ws.TypeClientLoaded {
+			break
+		}
+	}
+	assert.Equal(t, ws.TypeGameStart, types[0])
+	assert.Contains(t, types, ws.TypeError, "move during countdown should be rejected")
+	assert.Contains(t, types, ws.TypeClientLoaded)
+}
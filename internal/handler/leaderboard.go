@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/leaderboard"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/store"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// seasonCurrent selects the active season in leaderboard requests.
+const seasonCurrent = "current"
+
+// LeaderboardHandler records match results into standings and serves rankings
+// over WebSocket and read-only HTTP.
+type LeaderboardHandler struct {
+	store  store.LeaderboardStore
+	season string // active season ID, empty when seasons are disabled
+}
+
+// NewLeaderboardHandler creates a new leaderboard handler.
+func NewLeaderboardHandler(store store.LeaderboardStore, season string) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		store:  store,
+		season: season,
+	}
+}
+
+// HandleGameOver applies a finished match to all-time and current season standings.
+// Matches without a winner are not recorded.
+func (h *LeaderboardHandler) HandleGameOver(result room.MatchResult) {
+	if result.Winner == game.WinNone {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var accountIDs []string
+	for _, pr := range result.Players {
+		if pr.AccountID != "" {
+			accountIDs = append(accountIDs, pr.AccountID)
+		}
+	}
+	if len(accountIDs) == 0 {
+		return
+	}
+
+	// Each board keeps its own rating history, so deltas are computed per board
+	deltas, err := h.ratingDeltas(ctx, leaderboard.SeasonAllTime, result, accountIDs)
+	if err != nil {
+		slog.Error("failed to rate match", "room", result.RoomCode, "season", leaderboard.SeasonAllTime, "error", err)
+		return
+	}
+	var seasonDeltas map[string]float64
+	if h.season != "" {
+		seasonDeltas, err = h.ratingDeltas(ctx, h.season, result, accountIDs)
+		if err != nil {
+			slog.Error("failed to rate match", "room", result.RoomCode, "season", h.season, "error", err)
+			return
+		}
+	}
+
+	results := make([]leaderboard.Result, 0, len(accountIDs))
+	for _, pr := range result.Players {
+		if pr.AccountID == "" {
+			continue
+		}
+		results = append(results, leaderboard.Result{
+			AccountID:         pr.AccountID,
+			Won:               pr.Won,
+			Arrests:           pr.Stats.Arrests,
+			Rescues:           pr.Stats.Rescues,
+			RatingDelta:       deltas[pr.AccountID],
+			SeasonRatingDelta: seasonDeltas[pr.AccountID],
+		})
+	}
+	if err := h.store.RecordResults(ctx, h.season, results); err != nil {
+		slog.Error("failed to record leaderboard results", "room", result.RoomCode, "error", err)
+		return
+	}
+	slog.Info("leaderboard updated", "room", result.RoomCode, "accounts", len(accountIDs))
+}
+
+// ratingDeltas rates a match against one season's ratings.
+func (h *LeaderboardHandler) ratingDeltas(ctx context.Context, season string, result room.MatchResult, accountIDs []string) (map[string]float64, error) {
+	ratings, err := h.store.LoadRatings(ctx, season, accountIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	participants := make([]leaderboard.Participant, 0, len(accountIDs))
+	for _, pr := range result.Players {
+		if pr.AccountID == "" {
+			continue
+		}
+		rating, ok := ratings[pr.AccountID]
+		if !ok {
+			rating = leaderboard.InitialRating
+		}
+		participants = append(participants, leaderboard.Participant{
+			AccountID: pr.AccountID,
+			Team:      int(pr.Role),
+			Rating:    rating,
+			Won:       pr.Won,
+		})
+	}
+	return leaderboard.RatingDeltas(participants), nil
+}
+
+// Query returns a page of standings and, if accountID is set, that account's own standing.
+func (h *LeaderboardHandler) Query(ctx context.Context, q leaderboard.Query, accountID string) (*leaderboard.Page, error) {
+	if q.Season == seasonCurrent {
+		if h.season == "" {
+			return nil, leaderboard.ErrNoSeason
+		}
+		q.Season = h.season
+	}
+
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, total, err := h.store.TopStandings(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []leaderboard.Entry{}
+	}
+
+	page := &leaderboard.Page{
+		Season:  q.Season,
+		Metric:  q.Metric,
+		Total:   total,
+		Offset:  q.Offset,
+		Entries: entries,
+	}
+
+	if accountID != "" {
+		self, err := h.store.StandingOf(ctx, q, accountID)
+		if err != nil {
+			return nil, err
+		}
+		page.Self = self
+	}
+	return page, nil
+}
+
+type getLeaderboardRequest struct {
+	Metric string `json:"metric"`
+	Season string `json:"season"` // "all" (default), "current" or a season ID
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+// HandleGetLeaderboard serves a leaderboard page including the client's own rank.
+func (h *LeaderboardHandler) HandleGetLeaderboard(client *ws.Client, msg ws.Message) {
+	var req getLeaderboardRequest
+	if len(msg.Data) > 0 {
+		if err := json.Unmarshal(msg.Data, &req); err != nil {
+			client.SendMessage(ws.NewErrorMessage("잘못된 리더보드 요청입니다"))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	page, err := h.Query(ctx, leaderboard.Query{
+		Season: req.Season,
+		Metric: leaderboard.Metric(req.Metric),
+		Offset: req.Offset,
+		Limit:  req.Limit,
+	}, client.AccountID)
+	if err != nil {
+		client.SendMessage(ws.NewErrorMessage(leaderboardErrorMessage(err)))
+		if !isLeaderboardRequestError(err) {
+			slog.Error("failed to query leaderboard", "client", client.ID, "error", err)
+		}
+		return
+	}
+
+	resp, _ := ws.NewMessage(ws.TypeGetLeaderboard, page)
+	client.SendMessage(resp)
+}
+
+// ServeHTTP serves GET /leaderboard?metric=&season=&offset=&limit=.
+// Anyone may read it; behind OptionalBearer a request with a session token
+// also gets the caller's own standing.
+func (h *LeaderboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	params := r.URL.Query()
+	offset, _ := strconv.Atoi(params.Get("offset"))
+	limit, _ := strconv.Atoi(params.Get("limit"))
+
+	page, err := h.Query(r.Context(), leaderboard.Query{
+		Season: params.Get("season"),
+		Metric: leaderboard.Metric(params.Get("metric")),
+		Offset: offset,
+		Limit:  limit,
+	}, AccountIDFromContext(r.Context()))
+	if err != nil {
+		if isLeaderboardRequestError(err) {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.Error("failed to query leaderboard", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+func isLeaderboardRequestError(err error) bool {
+	return errors.Is(err, leaderboard.ErrUnknownMetric) || errors.Is(err, leaderboard.ErrNoSeason)
+}
+
+func leaderboardErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, leaderboard.ErrUnknownMetric):
+		return "알 수 없는 리더보드 기준입니다"
+	case errors.Is(err, leaderboard.ErrNoSeason):
+		return "진행 중인 시즌이 없습니다"
+	default:
+		return "서버 내부 오류입니다"
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to write JSON response", "error", err)
+	}
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
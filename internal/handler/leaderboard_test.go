@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/leaderboard"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// mockLeaderboardStore implements store.LeaderboardStore for testing.
+type mockLeaderboardStore struct {
+	ratings   map[string]map[string]float64   // season -> account -> rating
+	recorded  map[string][]leaderboard.Result // season -> results
+	lastQuery leaderboard.Query
+	entries   []leaderboard.Entry
+}
+
+func newMockLeaderboardStore() *mockLeaderboardStore {
+	return &mockLeaderboardStore{
+		ratings:  make(map[string]map[string]float64),
+		recorded: make(map[string][]leaderboard.Result),
+	}
+}
+
+func (m *mockLeaderboardStore) CurrentSeason(_ context.Context) (string, error) { return "", nil }
+func (m *mockLeaderboardStore) StartSeason(_ context.Context, _ string) error   { return nil }
+
+func (m *mockLeaderboardStore) LoadRatings(_ context.Context, season string, _ []string) (map[string]float64, error) {
+	ratings := make(map[string]float64)
+	for id, r := range m.ratings[season] {
+		ratings[id] = r
+	}
+	return ratings, nil
+}
+
+// RecordResults records each board's results with that board's rating change in RatingDelta.
+func (m *mockLeaderboardStore) RecordResults(_ context.Context, season string, results []leaderboard.Result) error {
+	m.recorded[leaderboard.SeasonAllTime] = append(m.recorded[leaderboard.SeasonAllTime], results...)
+	if season != "" {
+		for _, r := range results {
+			r.RatingDelta = r.SeasonRatingDelta
+			m.recorded[season] = append(m.recorded[season], r)
+		}
+	}
+	return nil
+}
+
+func (m *mockLeaderboardStore) TopStandings(_ context.Context, q leaderboard.Query) ([]leaderboard.Entry, int, error) {
+	m.lastQuery = q
+	return m.entries, len(m.entries), nil
+}
+
+func (m *mockLeaderboardStore) StandingOf(_ context.Context, _ leaderboard.Query, accountID string) (*leaderboard.Entry, error) {
+	for _, e := range m.entries {
+		if e.AccountID == accountID {
+			return &e, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestLeaderboardHandler_HandleGameOver(t *testing.T) {
+	st := newMockLeaderboardStore()
+	h := NewLeaderboardHandler(st, "2026-s1")
+
+	h.HandleGameOver(room.MatchResult{
+		Winner: game.WinPolice,
+		Players: []room.PlayerResult{
+			{AccountID: "acc-p", Role: game.RolePolice, Won: true, Stats: game.PlayerStats{Role: game.RolePolice, Arrests: 1}},
+			{AccountID: "acc-t", Role: game.RoleThief, Won: false, Stats: game.PlayerStats{Role: game.RoleThief, Rescues: 2}},
+			{PlayerID: "no-account", Role: game.RoleThief, Stats: game.PlayerStats{Role: game.RoleThief}},
+		},
+	})
+
+	require.Len(t, st.recorded[leaderboard.SeasonAllTime], 2)
+	require.Len(t, st.recorded["2026-s1"], 2)
+
+	byID := make(map[string]leaderboard.Result)
+	for _, r := range st.recorded[leaderboard.SeasonAllTime] {
+		byID[r.AccountID] = r
+	}
+	assert.Equal(t, 16.0, byID["acc-p"].RatingDelta)
+	assert.Equal(t, -16.0, byID["acc-t"].RatingDelta)
+	assert.Equal(t, 2, byID["acc-t"].Rescues)
+}
+
+func TestLeaderboardHandler_RatesEachSeasonSeparately(t *testing.T) {
+	st := newMockLeaderboardStore()
+	st.ratings[leaderboard.SeasonAllTime] = map[string]float64{"acc-p": 1400, "acc-t": 1000}
+	h := NewLeaderboardHandler(st, "2026-s1")
+
+	h.HandleGameOver(room.MatchResult{
+		Winner: game.WinPolice,
+		Players: []room.PlayerResult{
+			{AccountID: "acc-p", Role: game.RolePolice, Won: true, Stats: game.PlayerStats{Role: game.RolePolice}},
+			{AccountID: "acc-t", Role: game.RoleThief, Stats: game.PlayerStats{Role: game.RoleThief}},
+		},
+	})
+
+	deltas := func(season string) map[string]float64 {
+		out := make(map[string]float64)
+		for _, r := range st.recorded[season] {
+			out[r.AccountID] = r.RatingDelta
+		}
+		return out
+	}
+	// The favourite gains little all-time, but the fresh season is an even match
+	assert.Less(t, deltas(leaderboard.SeasonAllTime)["acc-p"], 16.0)
+	assert.Equal(t, 16.0, deltas("2026-s1")["acc-p"])
+	assert.Equal(t, -16.0, deltas("2026-s1")["acc-t"])
+}
+
+func TestLeaderboardHandler_InfectedThiefRatedWithPolice(t *testing.T) {
+	st := newMockLeaderboardStore()
+	h := NewLeaderboardHandler(st, "")
+
+	h.HandleGameOver(room.MatchResult{
+		Winner: game.WinPolice,
+		Players: []room.PlayerResult{
+			{AccountID: "acc-p", Role: game.RolePolice, Won: true, Stats: game.PlayerStats{Role: game.RolePolice}},
+			{AccountID: "acc-converted", Role: game.RolePolice, Won: true, Stats: game.PlayerStats{Role: game.RoleThief}},
+			{AccountID: "acc-t", Role: game.RoleThief, Stats: game.PlayerStats{Role: game.RoleThief}},
+		},
+	})
+
+	byID := make(map[string]leaderboard.Result)
+	for _, r := range st.recorded[leaderboard.SeasonAllTime] {
+		byID[r.AccountID] = r
+	}
+	assert.Equal(t, 16.0, byID["acc-converted"].RatingDelta)
+	assert.Equal(t, byID["acc-p"].RatingDelta, byID["acc-converted"].RatingDelta)
+	assert.Equal(t, -16.0, byID["acc-t"].RatingDelta)
+}
+
+func TestLeaderboardHandler_SkipsNoWinner(t *testing.T) {
+	st := newMockLeaderboardStore()
+	h := NewLeaderboardHandler(st, "")
+
+	h.HandleGameOver(room.MatchResult{
+		Winner:  game.WinNone,
+		Players: []room.PlayerResult{{AccountID: "acc-p", Stats: game.PlayerStats{Role: game.RolePolice}}},
+	})
+
+	assert.Empty(t, st.recorded)
+}
+
+func TestLeaderboardHandler_HandleGetLeaderboard(t *testing.T) {
+	st := newMockLeaderboardStore()
+	st.entries = []leaderboard.Entry{
+		{Rank: 1, AccountID: "acc-1", Wins: 5},
+		{Rank: 2, AccountID: "acc-2", Wins: 3},
+	}
+	h := NewLeaderboardHandler(st, "2026-s1")
+
+	client, ch := newTestClient("c1")
+	client.AccountID = "acc-2"
+	data, _ := json.Marshal(getLeaderboardRequest{Metric: "wins", Season: "current"})
+	h.HandleGetLeaderboard(client, ws.Message{Type: ws.TypeGetLeaderboard, Data: data})
+
+	resp := readResponse(t, ch)
+	assert.Equal(t, ws.TypeGetLeaderboard, resp.Type)
+
+	var page leaderboard.Page
+	require.NoError(t, json.Unmarshal(resp.Data, &page))
+	assert.Equal(t, "2026-s1", page.Season)
+	assert.Equal(t, leaderboard.MetricWins, page.Metric)
+	assert.Len(t, page.Entries, 2)
+	require.NotNil(t, page.Self)
+	assert.Equal(t, 2, page.Self.Rank)
+}
+
+func TestLeaderboardHandler_CurrentSeasonDisabled(t *testing.T) {
+	h := NewLeaderboardHandler(newMockLeaderboardStore(), "")
+
+	client, ch := newTestClient("c1")
+	data, _ := json.Marshal(getLeaderboardRequest{Season: "current"})
+	h.HandleGetLeaderboard(client, ws.Message{Type: ws.TypeGetLeaderboard, Data: data})
+
+	resp := readResponse(t, ch)
+	assert.Equal(t, ws.TypeError, resp.Type)
+}
+
+func TestLeaderboardHandler_ServeHTTP(t *testing.T) {
+	st := newMockLeaderboardStore()
+	st.entries = []leaderboard.Entry{{Rank: 1, AccountID: "acc-1", Arrests: 9}}
+	h := NewLeaderboardHandler(st, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/leaderboard?metric=arrests&offset=10&limit=5", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, leaderboard.Query{Season: leaderboard.SeasonAllTime, Metric: leaderboard.MetricArrests, Offset: 10, Limit: 5}, st.lastQuery)
+
+	var page leaderboard.Page
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Len(t, page.Entries, 1)
+	assert.Nil(t, page.Self)
+}
+
+func TestLeaderboardHandler_ServeHTTP_Errors(t *testing.T) {
+	h := NewLeaderboardHandler(newMockLeaderboardStore(), "")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/leaderboard?metric=kills", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/leaderboard", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
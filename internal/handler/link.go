@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/account"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/party"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/store"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// Conflict resolutions for linking a Game Center ID that already has an account.
+const (
+	linkResolveMerge  = "merge"  // move the guest's progress into the existing account
+	linkResolveSwitch = "switch" // sign in to the existing account, leaving the guest as is
+)
+
+// Link results reported to the client.
+const (
+	linkStatusLinked   = "linked"
+	linkStatusConflict = "conflict"
+	linkStatusMerged   = "merged"
+	linkStatusSwitched = "switched"
+	linkStatusBanned   = "banned" // the existing account is banned and cannot be switched to
+)
+
+// LinkHandler upgrades guest accounts by linking a Game Center identity.
+type LinkHandler struct {
+	verify  func(ctx context.Context, cred *auth.GameCenterCredential) error
+	store   store.AccountStore
+	tokens  *auth.SessionTokens
+	parties *party.Manager
+	bans    store.ModerationStore // nil disables ban checks
+	router  *Router
+}
+
+// NewLinkHandler creates a new account link handler. Session tokens are
+// issued for the account a guest switches or merges into, unless it is banned.
+func NewLinkHandler(verifier *auth.GameCenterVerifier, accounts store.AccountStore, tokens *auth.SessionTokens, parties *party.Manager, bans store.ModerationStore, router *Router) *LinkHandler {
+	return &LinkHandler{
+		verify:  verifier.Verify,
+		store:   accounts,
+		tokens:  tokens,
+		parties: parties,
+		bans:    bans,
+		router:  router,
+	}
+}
+
+type linkAccountRequest struct {
+	auth.GameCenterCredential
+	Resolve string `json:"resolve,omitempty"` // "merge" or "switch" after a conflict
+}
+
+type linkAccountResponse struct {
+	Status    string `json:"status"`
+	AccountID string `json:"account_id"`
+	Nickname  string `json:"nickname"`
+
+	// Set on conflict: the account already linked to the Game Center ID.
+	ExistingAccountID string `json:"existing_account_id,omitempty"`
+	ExistingNickname  string `json:"existing_nickname,omitempty"`
+
+	// Set when banned: why and until when the existing account is banned.
+	Ban *banNotice `json:"ban,omitempty"`
+
+	// Set on merge or switch: a session token for the account now in use.
+	SessionToken     string     `json:"session_token,omitempty"`
+	SessionExpiresAt *time.Time `json:"session_expires_at,omitempty"`
+}
+
+// HandleLinkAccount links a verified Game Center identity to the client's
+// guest account. When the Game Center ID already has an account, the client
+// is told about the conflict and may retry with resolve set to merge or switch.
+func (h *LinkHandler) HandleLinkAccount(client *ws.Client, msg ws.Message) {
+	if !client.IsGuest {
+		client.SendMessage(ws.NewErrorMessage("게스트 계정만 연결할 수 있습니다"))
+		return
+	}
+
+	var req linkAccountRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil || req.PlayerID == "" {
+		client.SendMessage(ws.NewErrorMessage("잘못된 계정 연결 요청입니다"))
+		return
+	}
+	if req.Resolve != "" && req.Resolve != linkResolveMerge && req.Resolve != linkResolveSwitch {
+		client.SendMessage(ws.NewErrorMessage("알 수 없는 연결 방식입니다: " + req.Resolve))
+		return
+	}
+	// A seated player's guest status is shown to the room, and merging or
+	// switching would leave the seat under the guest account.
+	if h.router.GetPlayerID(client.ID) != "" {
+		client.SendMessage(ws.NewErrorMessage("방을 나간 뒤 계정을 연결해주세요"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.verify(ctx, &req.GameCenterCredential); err != nil {
+		slog.Warn("account link verification failed", "account_id", client.AccountID, "error", err)
+		client.SendMessage(ws.NewErrorMessage("Game Center 인증에 실패했습니다"))
+		return
+	}
+
+	existing, err := h.store.FindByIdentity(ctx, auth.ProviderGameCenter, req.PlayerID)
+	if err != nil {
+		slog.Error("failed to look up game center identity", "error", err)
+		client.SendMessage(ws.NewErrorMessage("서버 내부 오류입니다"))
+		return
+	}
+
+	if existing == nil {
+		if err := h.store.LinkIdentity(ctx, client.AccountID, auth.ProviderGameCenter, req.PlayerID); err != nil {
+			slog.Error("failed to link game center identity", "account_id", client.AccountID, "error", err)
+			client.SendMessage(ws.NewErrorMessage("서버 내부 오류입니다"))
+			return
+		}
+		client.IsGuest = false
+		h.respond(client, linkAccountResponse{Status: linkStatusLinked, AccountID: client.AccountID, Nickname: client.Nickname})
+		slog.Info("guest account linked to game center", "account_id", client.AccountID)
+		return
+	}
+
+	// Switching or merging signs the client in to the existing account
+	ban, err := activeBan(ctx, h.bans, existing.ID)
+	if err != nil {
+		slog.Error("failed to check bans", "account_id", existing.ID, "error", err)
+		client.SendMessage(ws.NewErrorMessage("서버 내부 오류입니다"))
+		return
+	}
+	if ban != nil {
+		slog.Info("banned account rejected on link", "account_id", existing.ID, "ban", ban.ID, "guest", client.AccountID)
+		h.respond(client, linkAccountResponse{
+			Status:    linkStatusBanned,
+			AccountID: client.AccountID,
+			Nickname:  client.Nickname,
+			Ban:       newBanNotice(ban),
+		})
+		return
+	}
+
+	if req.Resolve == "" {
+		h.respond(client, linkAccountResponse{
+			Status:            linkStatusConflict,
+			AccountID:         client.AccountID,
+			Nickname:          client.Nickname,
+			ExistingAccountID: existing.ID,
+			ExistingNickname:  existing.Nickname,
+		})
+		return
+	}
+
+	// Merging or switching changes the client's account, so it must not be
+	// holding a party slot under the guest account.
+	if _, ok := h.parties.Get(client.AccountID); ok {
+		client.SendMessage(ws.NewErrorMessage("파티를 나간 뒤 계정을 전환해주세요"))
+		return
+	}
+
+	guestID := client.AccountID
+	status := linkStatusSwitched
+	if req.Resolve == linkResolveMerge {
+		if err := h.store.MergeAccounts(ctx, guestID, existing.ID); err != nil {
+			slog.Error("failed to merge accounts", "from", guestID, "into", existing.ID, "error", err)
+			client.SendMessage(ws.NewErrorMessage("서버 내부 오류입니다"))
+			return
+		}
+		status = linkStatusMerged
+	}
+	_ = h.store.UpdateLastLogin(ctx, existing.ID)
+	h.switchAccount(client, existing)
+	token, claims := h.tokens.Issue(existing.ID)
+	expiresAt := claims.Expiry()
+	h.respond(client, linkAccountResponse{
+		Status:           status,
+		AccountID:        existing.ID,
+		Nickname:         existing.Nickname,
+		SessionToken:     token,
+		SessionExpiresAt: &expiresAt,
+	})
+
+	slog.Info("guest resolved game center conflict", "guest", guestID, "account_id", existing.ID, "resolve", req.Resolve)
+}
+
+// switchAccount moves the client's session from its guest account to acc.
+func (h *LinkHandler) switchAccount(client *ws.Client, acc *account.Account) {
+	h.router.unregisterAccount(client)
+	client.AccountID = acc.ID
+	client.Nickname = acc.Nickname
+	client.IsGuest = acc.IsGuest
+	h.router.registerAccount(client)
+}
+
+func (h *LinkHandler) respond(client *ws.Client, res linkAccountResponse) {
+	resp, _ := ws.NewMessage(ws.TypeLinkAccount, res)
+	client.SendMessage(resp)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/account"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+func setupLinkTest(t *testing.T) (*Router, *mockAccountStore) {
+	t.Helper()
+	store := newMockAccountStore()
+	router := NewRouter(room.NewManager(), auth.NewGameCenterVerifier(nil, 0), store, Options{})
+	router.link.verify = func(context.Context, *auth.GameCenterCredential) error { return nil }
+	return router, store
+}
+
+func readLinkResponse(t *testing.T, ch chan sentMessage) linkAccountResponse {
+	t.Helper()
+	resp := readResponse(t, ch)
+	require.Equal(t, ws.TypeLinkAccount, resp.Type, string(resp.Data))
+	var res linkAccountResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &res))
+	return res
+}
+
+func TestLinkAccount_UpgradesGuest(t *testing.T) {
+	router, store := setupLinkTest(t)
+	client, ch := loginGuest(t, router, "c1", "손님")
+	guestID := client.AccountID
+
+	sendMessage(router, client, ws.TypeLinkAccount, map[string]string{"player_id": "G:1"})
+	res := readLinkResponse(t, ch)
+	assert.Equal(t, linkStatusLinked, res.Status)
+	assert.Equal(t, guestID, res.AccountID)
+	assert.False(t, client.IsGuest)
+
+	acc := store.byGCID["G:1"]
+	require.NotNil(t, acc)
+	assert.Equal(t, guestID, acc.ID)
+	assert.False(t, acc.IsGuest)
+
+	// Only guests can link
+	sendMessage(router, client, ws.TypeLinkAccount, map[string]string{"player_id": "G:2"})
+	assert.Equal(t, ws.TypeError, readResponse(t, ch).Type)
+}
+
+func TestLinkAccount_ConflictThenResolve(t *testing.T) {
+	for _, resolve := range []string{linkResolveMerge, linkResolveSwitch} {
+		t.Run(resolve, func(t *testing.T) {
+			router, store := setupLinkTest(t)
+			existing := account.NewGameCenterAccount("G:1", "본계정")
+			require.NoError(t, store.CreateWithIdentity(context.Background(), existing, auth.ProviderGameCenter, "G:1"))
+
+			client, ch := loginGuest(t, router, "c1", "손님")
+			guestID := client.AccountID
+
+			sendMessage(router, client, ws.TypeLinkAccount, map[string]string{"player_id": "G:1"})
+			res := readLinkResponse(t, ch)
+			assert.Equal(t, linkStatusConflict, res.Status)
+			assert.Equal(t, existing.ID, res.ExistingAccountID)
+			assert.Equal(t, "본계정", res.ExistingNickname)
+			assert.Equal(t, guestID, client.AccountID, "nothing changes until the client resolves")
+
+			sendMessage(router, client, ws.TypeLinkAccount, map[string]string{"player_id": "G:1", "resolve": resolve})
+			res = readLinkResponse(t, ch)
+			assert.Equal(t, existing.ID, res.AccountID)
+			assert.NotEmpty(t, res.SessionToken)
+			assert.Equal(t, existing.ID, client.AccountID)
+			assert.Equal(t, "본계정", client.Nickname)
+			assert.False(t, client.IsGuest)
+			assert.Same(t, client, router.ClientByAccount(existing.ID))
+			assert.Nil(t, router.ClientByAccount(guestID))
+
+			_, guestKept := store.accounts[guestID]
+			if resolve == linkResolveMerge {
+				assert.Equal(t, linkStatusMerged, res.Status)
+				assert.False(t, guestKept)
+			} else {
+				assert.Equal(t, linkStatusSwitched, res.Status)
+				assert.True(t, guestKept)
+			}
+		})
+	}
+}
+
+func TestLinkAccount_BlockedInRoom(t *testing.T) {
+	router, store := setupLinkTest(t)
+	require.NoError(t, store.CreateWithIdentity(context.Background(), account.NewGameCenterAccount("G:1", "본계정"), auth.ProviderGameCenter, "G:1"))
+
+	client, ch := loginGuest(t, router, "c1", "손님")
+	sendMessage(router, client, ws.TypeCreateRoom, nil)
+	require.Equal(t, ws.TypeCreateRoom, readResponse(t, ch).Type)
+	drainCh(ch)
+
+	sendMessage(router, client, ws.TypeLinkAccount, map[string]string{"player_id": "G:1", "resolve": linkResolveSwitch})
+	assert.Equal(t, ws.TypeError, readResponse(t, ch).Type)
+
+	// A plain link would leave the room showing a stale guest badge
+	sendMessage(router, client, ws.TypeLinkAccount, map[string]string{"player_id": "G:2"})
+	assert.Equal(t, ws.TypeError, readResponse(t, ch).Type)
+	assert.True(t, client.IsGuest)
+	assert.Nil(t, store.byGCID["G:2"])
+}
+
+func TestLinkAccount_ResolveBlockedInParty(t *testing.T) {
+	router, store := setupLinkTest(t)
+	require.NoError(t, store.CreateWithIdentity(context.Background(), account.NewGameCenterAccount("G:1", "본계정"), auth.ProviderGameCenter, "G:1"))
+
+	client, ch := loginGuest(t, router, "c1", "손님")
+	guestID := client.AccountID
+	sendMessage(router, client, ws.TypePartyCreate, nil)
+	readResponse(t, ch)
+	drainCh(ch)
+
+	sendMessage(router, client, ws.TypeLinkAccount, map[string]string{"player_id": "G:1", "resolve": linkResolveMerge})
+	assert.Equal(t, ws.TypeError, readResponse(t, ch).Type)
+	assert.Equal(t, guestID, client.AccountID)
+}
+
+func TestLinkAccount_BannedAccountRefused(t *testing.T) {
+	store := newMockAccountStore()
+	mod := &mockModerationStore{}
+	router := NewRouter(room.NewManager(), auth.NewGameCenterVerifier(nil, 0), store, Options{ModerationStore: mod})
+	router.link.verify = func(context.Context, *auth.GameCenterCredential) error { return nil }
+
+	existing := account.NewGameCenterAccount("G:1", "본계정")
+	require.NoError(t, store.CreateWithIdentity(context.Background(), existing, auth.ProviderGameCenter, "G:1"))
+	require.NoError(t, mod.IssueBan(context.Background(), moderation.NewBan(existing.ID, "speed hack", "admin-1", 0)))
+
+	client, ch := loginGuest(t, router, "c1", "손님")
+	guestID := client.AccountID
+
+	for _, resolve := range []string{"", linkResolveSwitch, linkResolveMerge} {
+		sendMessage(router, client, ws.TypeLinkAccount, map[string]string{"player_id": "G:1", "resolve": resolve})
+		res := readLinkResponse(t, ch)
+		assert.Equal(t, linkStatusBanned, res.Status)
+		require.NotNil(t, res.Ban)
+		assert.True(t, res.Ban.Permanent)
+		assert.Empty(t, res.SessionToken)
+	}
+	assert.Equal(t, guestID, client.AccountID)
+	assert.True(t, client.IsGuest)
+	_, guestKept := store.accounts[guestID]
+	assert.True(t, guestKept, "a banned account must not absorb the guest")
+}
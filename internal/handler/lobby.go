@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
@@ -24,7 +25,9 @@ func NewLobbyHandler(rm *room.Manager, router *Router) *LobbyHandler {
 }
 
 type createRoomRequest struct {
-	Nickname string `json:"nickname"`
+	Mode       string `json:"mode,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+	Password   string `json:"password,omitempty"`
 }
 
 type createRoomResponse struct {
@@ -35,13 +38,27 @@ type createRoomResponse struct {
 // HandleCreateRoom handles room creation.
 func (h *LobbyHandler) HandleCreateRoom(client *ws.Client, msg ws.Message) {
 	var req createRoomRequest
-	if err := json.Unmarshal(msg.Data, &req); err != nil || req.Nickname == "" {
-		client.SendMessage(ws.NewErrorMessage("닉네임을 입력해주세요"))
+	if len(msg.Data) > 0 {
+		if err := json.Unmarshal(msg.Data, &req); err != nil {
+			client.SendMessage(ws.NewErrorMessage("잘못된 방 생성 요청입니다"))
+			return
+		}
+	}
+	if h.router.GetPlayerID(client.ID) != "" {
+		client.SendMessage(ws.NewErrorMessage("이미 방에 참가하고 있습니다"))
+		return
+	}
+
+	mode, visibility, errMsg := parseRoomOptions(req)
+	if errMsg != "" {
+		client.SendMessage(ws.NewErrorMessage(errMsg))
 		return
 	}
 
 	r := h.rm.CreateRoom()
-	player := game.NewPlayer(req.Nickname)
+	r.SetMode(mode)
+	r.SetVisibility(visibility, req.Password)
+	player := newAccountPlayer(client)
 	r.AddPlayer(player, client)
 	h.router.RegisterPlayer(client.ID, player.ID)
 
@@ -51,19 +68,55 @@ func (h *LobbyHandler) HandleCreateRoom(client *ws.Client, msg ws.Message) {
 	})
 	client.SendMessage(resp)
 
-	slog.Info("player created room", "player", player.Nickname, "room", r.Code)
+	slog.Info("player created room", "player", player.Nickname, "room", r.Code, "mode", mode.Name(), "visibility", visibility)
+}
+
+// parseRoomOptions resolves the mode and visibility of a new room.
+// Returns a user-facing error message on failure.
+func parseRoomOptions(req createRoomRequest) (game.Mode, room.Visibility, string) {
+	var mode game.Mode = game.ClassicMode{}
+	if req.Mode != "" {
+		m, ok := game.ModeByName(req.Mode)
+		if !ok {
+			return nil, "", "알 수 없는 게임 모드입니다"
+		}
+		mode = m
+	}
+
+	visibility := room.VisibilityPublic
+	if req.Visibility != "" {
+		v, errMsg := parseVisibility(req.Visibility, req.Password)
+		if errMsg != "" {
+			return nil, "", errMsg
+		}
+		visibility = v
+	}
+	return mode, visibility, ""
+}
+
+// parseVisibility validates a requested visibility and its password.
+// Returns a user-facing error message on failure.
+func parseVisibility(name, password string) (room.Visibility, string) {
+	v, ok := room.VisibilityByName(name)
+	if !ok {
+		return "", "알 수 없는 공개 설정입니다"
+	}
+	if v == room.VisibilityPassword && (password == "" || len(password) > room.MaxPasswordLength) {
+		return "", fmt.Sprintf("비밀번호는 1~%d자로 입력해주세요", room.MaxPasswordLength)
+	}
+	return v, ""
 }
 
 type joinRoomRequest struct {
 	Code     string `json:"code"`
-	Nickname string `json:"nickname"`
+	Password string `json:"password,omitempty"`
 }
 
 // HandleJoinRoom handles joining an existing room.
 func (h *LobbyHandler) HandleJoinRoom(client *ws.Client, msg ws.Message) {
 	var req joinRoomRequest
-	if err := json.Unmarshal(msg.Data, &req); err != nil || req.Code == "" || req.Nickname == "" {
-		client.SendMessage(ws.NewErrorMessage("방 코드와 닉네임을 입력해주세요"))
+	if err := json.Unmarshal(msg.Data, &req); err != nil || req.Code == "" {
+		client.SendMessage(ws.NewErrorMessage("방 코드를 입력해주세요"))
 		return
 	}
 
@@ -72,11 +125,27 @@ func (h *LobbyHandler) HandleJoinRoom(client *ws.Client, msg ws.Message) {
 		client.SendMessage(ws.NewErrorMessage("방을 찾을 수 없습니다"))
 		return
 	}
+	if !r.CheckPassword(req.Password) {
+		client.SendMessage(ws.NewErrorMessage("비밀번호가 올바르지 않습니다"))
+		return
+	}
+
+	if h.joinRoom(client, r) {
+		slog.Info("player joined room", "account_id", client.AccountID, "room", r.Code)
+	}
+}
 
-	player := game.NewPlayer(req.Nickname)
+// joinRoom adds the client to a room as a new player and announces it.
+// Returns false if the client is already in a room or the room is full.
+func (h *LobbyHandler) joinRoom(client *ws.Client, r *room.Room) bool {
+	if h.router.GetPlayerID(client.ID) != "" || r.HasAccount(client.AccountID) {
+		client.SendMessage(ws.NewErrorMessage("이미 방에 참가하고 있습니다"))
+		return false
+	}
+	player := newAccountPlayer(client)
 	if !r.AddPlayer(player, client) {
 		client.SendMessage(ws.NewErrorMessage("방이 가득 찼습니다"))
-		return
+		return false
 	}
 	h.router.RegisterPlayer(client.ID, player.ID)
 
@@ -87,21 +156,26 @@ func (h *LobbyHandler) HandleJoinRoom(client *ws.Client, msg ws.Message) {
 	client.SendMessage(resp)
 
 	h.broadcastRoomInfo(r)
+	return true
+}
 
-	slog.Info("player joined room", "player", player.Nickname, "room", r.Code)
+// newAccountPlayer creates a player bound to an authenticated client's account.
+func newAccountPlayer(client *ws.Client) *game.Player {
+	return game.NewAccountPlayer(client.AccountID, client.Nickname, client.IsGuest)
 }
 
 type randomJoinRequest struct {
-	Nickname      string `json:"nickname"`
 	PreferredRole string `json:"preferred_role,omitempty"`
 }
 
 // HandleRandomJoin handles joining a random available room.
 func (h *LobbyHandler) HandleRandomJoin(client *ws.Client, msg ws.Message) {
 	var req randomJoinRequest
-	if err := json.Unmarshal(msg.Data, &req); err != nil || req.Nickname == "" {
-		client.SendMessage(ws.NewErrorMessage("닉네임을 입력해주세요"))
-		return
+	if len(msg.Data) > 0 {
+		if err := json.Unmarshal(msg.Data, &req); err != nil {
+			client.SendMessage(ws.NewErrorMessage("잘못된 입장 요청입니다"))
+			return
+		}
 	}
 
 	var preferredRole game.Role
@@ -116,27 +190,33 @@ func (h *LobbyHandler) HandleRandomJoin(client *ws.Client, msg ws.Message) {
 
 	r := h.rm.FindAvailableRoom(preferredRole)
 	if r == nil {
-		slog.Warn("no available room for random join", "player", req.Nickname, "preferred_role", req.PreferredRole, "total_rooms", h.rm.RoomCount())
+		slog.Warn("no available room for random join", "account_id", client.AccountID, "preferred_role", req.PreferredRole, "total_rooms", h.rm.RoomCount())
 		client.SendMessage(ws.NewErrorMessage("입장 가능한 방이 없습니다"))
 		return
 	}
 
-	player := game.NewPlayer(req.Nickname)
-	if !r.AddPlayer(player, client) {
-		client.SendMessage(ws.NewErrorMessage("방이 가득 찼습니다"))
-		return
+	if h.joinRoom(client, r) {
+		slog.Info("player random joined room", "account_id", client.AccountID, "room", r.Code)
 	}
-	h.router.RegisterPlayer(client.ID, player.ID)
+}
 
-	resp, _ := ws.NewMessage(ws.TypeJoinRoom, createRoomResponse{
-		Code:     r.Code,
-		PlayerID: player.ID,
-	})
-	client.SendMessage(resp)
+type listRoomsRequest struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
 
-	h.broadcastRoomInfo(r)
+// HandleListRooms returns a page of public and password-protected waiting rooms.
+func (h *LobbyHandler) HandleListRooms(client *ws.Client, msg ws.Message) {
+	var req listRoomsRequest
+	if len(msg.Data) > 0 {
+		if err := json.Unmarshal(msg.Data, &req); err != nil {
+			client.SendMessage(ws.NewErrorMessage("잘못된 방 목록 요청입니다"))
+			return
+		}
+	}
 
-	slog.Info("player random joined room", "player", player.Nickname, "room", r.Code)
+	resp, _ := ws.NewMessage(ws.TypeListRooms, h.rm.ListRooms(req.Offset, req.Limit))
+	client.SendMessage(resp)
 }
 
 type selectTeamRequest struct {
@@ -157,6 +237,7 @@ func (h *LobbyHandler) HandleSelectTeam(client *ws.Client, msg ws.Message) {
 		client.SendMessage(ws.NewErrorMessage("방에 참가하고 있지 않습니다"))
 		return
 	}
+	r.TouchPlayer(playerID)
 
 	var role game.Role
 	switch req.Role {
@@ -180,6 +261,84 @@ func (h *LobbyHandler) HandleSelectTeam(client *ws.Client, msg ws.Message) {
 	slog.Info("player selected team", "player", playerID, "role", role.String())
 }
 
+type roomSettingsRequest struct {
+	Mode         *string `json:"mode,omitempty"`
+	SeriesLength *int    `json:"series_length,omitempty"`
+	AFKAction    *string `json:"afk_action,omitempty"`
+	Visibility   *string `json:"visibility,omitempty"`
+	Password     string  `json:"password,omitempty"` // required with visibility "password"
+}
+
+// HandleRoomSettings lets the host change room settings while waiting.
+func (h *LobbyHandler) HandleRoomSettings(client *ws.Client, msg ws.Message) {
+	var req roomSettingsRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		client.SendMessage(ws.NewErrorMessage("잘못된 방 설정입니다"))
+		return
+	}
+
+	playerID := h.router.GetPlayerID(client.ID)
+	r := h.rm.FindRoomByPlayerID(playerID)
+	if r == nil {
+		client.SendMessage(ws.NewErrorMessage("방에 참가하고 있지 않습니다"))
+		return
+	}
+	r.TouchPlayer(playerID)
+	if r.HostID != playerID {
+		client.SendMessage(ws.NewErrorMessage("방장만 설정을 변경할 수 있습니다"))
+		return
+	}
+
+	if r.CurrentState() != game.StateWaiting {
+		client.SendMessage(ws.NewErrorMessage("대기 중에만 설정을 변경할 수 있습니다"))
+		return
+	}
+
+	var mode game.Mode
+	if req.Mode != nil {
+		m, ok := game.ModeByName(*req.Mode)
+		if !ok {
+			client.SendMessage(ws.NewErrorMessage("알 수 없는 게임 모드입니다"))
+			return
+		}
+		mode = m
+	}
+	if req.SeriesLength != nil && !room.ValidSeriesLength(*req.SeriesLength) {
+		client.SendMessage(ws.NewErrorMessage(fmt.Sprintf("시리즈 길이는 1~%d 사이의 홀수여야 합니다", room.MaxSeriesLength)))
+		return
+	}
+	var afkAction room.AFKAction
+	if req.AFKAction != nil {
+		a, ok := room.AFKActionByName(*req.AFKAction)
+		if !ok {
+			client.SendMessage(ws.NewErrorMessage("알 수 없는 자리 비움 처리 방식입니다"))
+			return
+		}
+		afkAction = a
+	}
+	var visibility room.Visibility
+	if req.Visibility != nil {
+		v, errMsg := parseVisibility(*req.Visibility, req.Password)
+		if errMsg != "" {
+			client.SendMessage(ws.NewErrorMessage(errMsg))
+			return
+		}
+		visibility = v
+	}
+
+	if (mode != nil && !r.SetMode(mode)) ||
+		(req.SeriesLength != nil && !r.SetSeriesLength(*req.SeriesLength)) ||
+		(afkAction != "" && !r.SetAFKAction(afkAction)) ||
+		(visibility != "" && !r.SetVisibility(visibility, req.Password)) {
+		client.SendMessage(ws.NewErrorMessage("대기 중에만 설정을 변경할 수 있습니다"))
+		return
+	}
+
+	h.broadcastRoomInfo(r)
+
+	slog.Info("room settings changed", "room", r.Code, "by", playerID, "mode", r.ModeName(), "series_length", r.GetSeriesLength(), "afk_action", r.GetAFKAction(), "visibility", r.GetVisibility())
+}
+
 // HandlePlayerReady handles player ready status toggle.
 func (h *LobbyHandler) HandlePlayerReady(client *ws.Client, msg ws.Message) {
 	playerID := h.router.GetPlayerID(client.ID)
@@ -189,6 +348,7 @@ func (h *LobbyHandler) HandlePlayerReady(client *ws.Client, msg ws.Message) {
 		return
 	}
 
+	r.TouchPlayer(playerID)
 	allReady := r.TogglePlayerReady(playerID)
 	h.broadcastRoomInfo(r)
 
@@ -196,18 +356,8 @@ func (h *LobbyHandler) HandlePlayerReady(client *ws.Client, msg ws.Message) {
 
 	// Check if all players are ready to start
 	if allReady {
-		// 1. Assign spawn positions first
-		r.PrepareGame()
-
-		// 2. Broadcast game_start with correct spawn positions and map objects
-		startMsg, _ := ws.NewMessage(ws.TypeGameStart, gameStartResponse{
-			Players:    r.GetPlayerList(),
-			MapObjects: r.MapObjects,
-		})
-		r.BroadcastMessage(startMsg)
-
-		// 3. Start the game loop
-		r.StartGameLoop()
+		// Assigns spawn positions, broadcasts game_start and starts the game loop
+		r.StartRound()
 		slog.Info("all players ready, game starting", "room", r.Code)
 	}
 }
@@ -221,13 +371,18 @@ func (h *LobbyHandler) HandleReturnToLobby(client *ws.Client, _ ws.Message) {
 		return
 	}
 
-	if r.State == game.StatePlaying {
+	r.TouchPlayer(playerID)
+	if state := r.CurrentState(); state == game.StatePlaying || state == game.StateStarting {
 		client.SendMessage(ws.NewErrorMessage("게임이 아직 끝나지 않았습니다"))
 		return
 	}
+	if r.InSeries() {
+		client.SendMessage(ws.NewErrorMessage("시리즈가 아직 끝나지 않았습니다"))
+		return
+	}
 
 	// Idempotent: only reset if still in ended state
-	if r.State == game.StateEnded {
+	if r.CurrentState() == game.StateEnded {
 		r.Reset()
 	}
 	h.broadcastRoomInfo(r)
@@ -254,35 +409,51 @@ func (h *LobbyHandler) removePlayer(client *ws.Client) {
 	r := h.rm.FindRoomByPlayerID(playerID)
 	if r != nil {
 		r.RemovePlayer(playerID)
-		if r.IsEmpty() {
-			h.rm.RemoveRoom(r.Code)
-		} else {
-			h.broadcastRoomInfo(r)
-		}
+		h.afterRemoval(r)
 	}
 
 	h.router.UnregisterPlayer(client.ID)
 	slog.Info("player left", "player", playerID)
 }
 
-type gameStartResponse struct {
-	Players    []*game.Player   `json:"players"`
-	MapObjects []game.MapObject `json:"map_objects"`
+// HandleAFKRemoved releases the client of a player the room removed for inactivity.
+func (h *LobbyHandler) HandleAFKRemoved(r *room.Room, removal room.AFKRemoval) {
+	if removal.Client != nil {
+		h.router.UnregisterPlayer(removal.Client.ID)
+	}
+	h.afterRemoval(r)
+}
+
+// afterRemoval drops an empty room or tells the remaining players who is left.
+func (h *LobbyHandler) afterRemoval(r *room.Room) {
+	if r.IsEmpty() {
+		h.rm.RemoveRoom(r.Code)
+	} else {
+		h.broadcastRoomInfo(r)
+	}
 }
 
 type roomInfoResponse struct {
-	Code    string         `json:"code"`
-	State   string         `json:"state"`
-	Players []*game.Player `json:"players"`
-	HostID  string         `json:"host_id"`
+	Code         string         `json:"code"`
+	State        string         `json:"state"`
+	Mode         string         `json:"mode"`
+	SeriesLength int            `json:"series_length"`
+	AFKAction    string         `json:"afk_action"`
+	Visibility   string         `json:"visibility"`
+	Players      []*game.Player `json:"players"`
+	HostID       string         `json:"host_id"`
 }
 
 func (h *LobbyHandler) broadcastRoomInfo(r *room.Room) {
 	resp, _ := ws.NewMessage(ws.TypeRoomInfo, roomInfoResponse{
-		Code:    r.Code,
-		State:   r.State.String(),
-		Players: r.GetPlayerList(),
-		HostID:  r.HostID,
+		Code:         r.Code,
+		State:        r.CurrentState().String(),
+		Mode:         r.ModeName(),
+		SeriesLength: r.GetSeriesLength(),
+		AFKAction:    string(r.GetAFKAction()),
+		Visibility:   string(r.GetVisibility()),
+		Players:      r.GetPlayerList(),
+		HostID:       r.HostID,
 	})
 	r.BroadcastMessage(resp)
 }
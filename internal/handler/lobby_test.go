@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+func sendMessage(router *Router, client *ws.Client, msgType string, data any) {
+	msg, _ := ws.NewMessage(msgType, data)
+	raw, _ := json.Marshal(msg)
+	router.HandleMessage(&ws.ClientMessage{Client: client, Data: raw})
+}
+
+func setupLobbyTest(t *testing.T) (*Router, *room.Manager) {
+	t.Helper()
+	rm := room.NewManager()
+	router := NewRouter(rm, auth.NewGameCenterVerifier(nil, 0), newMockAccountStore(), Options{})
+	return router, rm
+}
+
+func TestHandleCreateRoom_WithMode(t *testing.T) {
+	router, rm := setupLobbyTest(t)
+	client, ch := newTestClient("c1")
+	client.MarkAuthenticated()
+
+	sendMessage(router, client, ws.TypeCreateRoom, map[string]string{"nickname": "Host", "mode": game.ModeInfection})
+
+	resp := readResponse(t, ch)
+	require.Equal(t, ws.TypeCreateRoom, resp.Type)
+	r := rm.FindRoomByPlayerID(router.GetPlayerID(client.ID))
+	require.NotNil(t, r)
+	assert.Equal(t, game.ModeInfection, r.ModeName())
+}
+
+func TestHandleCreateRoom_UnknownMode(t *testing.T) {
+	router, _ := setupLobbyTest(t)
+	client, ch := newTestClient("c1")
+	client.MarkAuthenticated()
+
+	sendMessage(router, client, ws.TypeCreateRoom, map[string]string{"nickname": "Host", "mode": "tag"})
+
+	resp := readResponse(t, ch)
+	assert.Equal(t, ws.TypeError, resp.Type)
+}
+
+func TestHandleRoomSettings_HostChangesMode(t *testing.T) {
+	router, rm := setupLobbyTest(t)
+	host, hostCh := newTestClient("c1")
+	host.MarkAuthenticated()
+	guest, guestCh := newTestClient("c2")
+	guest.MarkAuthenticated()
+
+	sendMessage(router, host, ws.TypeCreateRoom, map[string]string{"nickname": "Host"})
+	created := readResponse(t, hostCh)
+	var createResp createRoomResponse
+	require.NoError(t, json.Unmarshal(created.Data, &createResp))
+
+	sendMessage(router, guest, ws.TypeJoinRoom, map[string]string{"code": createResp.Code, "nickname": "Guest"})
+	require.Equal(t, ws.TypeRoomInfo, readResponse(t, hostCh).Type)
+	require.Equal(t, ws.TypeJoinRoom, readResponse(t, guestCh).Type)
+	require.Equal(t, ws.TypeRoomInfo, readResponse(t, guestCh).Type)
+
+	// Only the host may change settings
+	sendMessage(router, guest, ws.TypeRoomSettings, map[string]string{"mode": game.ModeInfection})
+	resp := readResponse(t, guestCh)
+	assert.Equal(t, ws.TypeError, resp.Type)
+
+	sendMessage(router, host, ws.TypeRoomSettings, map[string]string{"mode": game.ModeInfection})
+	resp = readResponse(t, guestCh)
+	require.Equal(t, ws.TypeRoomInfo, resp.Type)
+	var info roomInfoResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &info))
+	assert.Equal(t, game.ModeInfection, info.Mode)
+
+	r := rm.FindRoomByPlayerID(router.GetPlayerID(host.ID))
+	require.NotNil(t, r)
+	assert.Equal(t, game.ModeInfection, r.ModeName())
+}
+
+func TestHandleRoomSettings_SeriesLength(t *testing.T) {
+	router, rm := setupLobbyTest(t)
+	host, ch := newTestClient("c1")
+	host.MarkAuthenticated()
+
+	sendMessage(router, host, ws.TypeCreateRoom, map[string]string{"nickname": "Host"})
+	require.Equal(t, ws.TypeCreateRoom, readResponse(t, ch).Type)
+
+	sendMessage(router, host, ws.TypeRoomSettings, map[string]int{"series_length": 4})
+	assert.Equal(t, ws.TypeError, readResponse(t, ch).Type)
+
+	sendMessage(router, host, ws.TypeRoomSettings, map[string]int{"series_length": 3})
+	resp := readResponse(t, ch)
+	require.Equal(t, ws.TypeRoomInfo, resp.Type)
+	var info roomInfoResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &info))
+	assert.Equal(t, 3, info.SeriesLength)
+
+	r := rm.FindRoomByPlayerID(router.GetPlayerID(host.ID))
+	require.NotNil(t, r)
+	assert.Equal(t, 3, r.GetSeriesLength())
+}
+
+func TestHandleRoomSettings_AFKAction(t *testing.T) {
+	router, rm := setupLobbyTest(t)
+	host, ch := newTestClient("c1")
+	host.MarkAuthenticated()
+
+	sendMessage(router, host, ws.TypeCreateRoom, map[string]string{"nickname": "Host"})
+	require.Equal(t, ws.TypeCreateRoom, readResponse(t, ch).Type)
+
+	sendMessage(router, host, ws.TypeRoomSettings, map[string]string{"afk_action": "ban"})
+	assert.Equal(t, ws.TypeError, readResponse(t, ch).Type)
+
+	sendMessage(router, host, ws.TypeRoomSettings, map[string]string{"afk_action": "bot"})
+	resp := readResponse(t, ch)
+	require.Equal(t, ws.TypeRoomInfo, resp.Type)
+	var info roomInfoResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &info))
+	assert.Equal(t, "bot", info.AFKAction)
+
+	r := rm.FindRoomByPlayerID(router.GetPlayerID(host.ID))
+	require.NotNil(t, r)
+	assert.Equal(t, room.AFKBot, r.GetAFKAction())
+}
+
+func TestHandleAFKRemoved_ReleasesClient(t *testing.T) {
+	router, rm := setupLobbyTest(t)
+	host, ch := newTestClient("c1")
+	host.MarkAuthenticated()
+
+	sendMessage(router, host, ws.TypeCreateRoom, map[string]string{"nickname": "Host"})
+	require.Equal(t, ws.TypeCreateRoom, readResponse(t, ch).Type)
+	r := rm.FindRoomByPlayerID(router.GetPlayerID(host.ID))
+	require.NotNil(t, r)
+
+	rm.CheckAFK(time.Now().Add(time.Hour), room.AFKPolicy{LobbyWarn: time.Minute, LobbyTimeout: time.Minute})
+
+	assert.Equal(t, ws.TypeAFKRemoved, readResponse(t, ch).Type)
+	assert.Empty(t, router.GetPlayerID(host.ID))
+	assert.Nil(t, rm.GetRoom(r.Code))
+}
+
+func TestHandleJoinRoom_Password(t *testing.T) {
+	router, _ := setupLobbyTest(t)
+	host, hostCh := newTestClient("c1")
+	guest, guestCh := newTestClient("c2")
+	host.MarkAuthenticated()
+	guest.MarkAuthenticated()
+
+	sendMessage(router, host, ws.TypeCreateRoom, map[string]string{"nickname": "Host", "visibility": "password"})
+	assert.Equal(t, ws.TypeError, readResponse(t, hostCh).Type, "password required")
+
+	sendMessage(router, host, ws.TypeCreateRoom, map[string]string{"nickname": "Host", "visibility": "password", "password": "1234"})
+	created := readResponse(t, hostCh)
+	require.Equal(t, ws.TypeCreateRoom, created.Type)
+	var createResp createRoomResponse
+	require.NoError(t, json.Unmarshal(created.Data, &createResp))
+
+	sendMessage(router, guest, ws.TypeJoinRoom, map[string]string{"code": createResp.Code, "nickname": "Guest", "password": "0000"})
+	assert.Equal(t, ws.TypeError, readResponse(t, guestCh).Type)
+	assert.Empty(t, router.GetPlayerID(guest.ID))
+
+	sendMessage(router, guest, ws.TypeJoinRoom, map[string]string{"code": createResp.Code, "nickname": "Guest", "password": "1234"})
+	assert.Equal(t, ws.TypeJoinRoom, readResponse(t, guestCh).Type)
+}
+
+func TestHandleListRooms(t *testing.T) {
+	router, _ := setupLobbyTest(t)
+	host, hostCh := loginGuest(t, router, "c1", "Host")
+	browser, browserCh := loginGuest(t, router, "c2", "Browser")
+
+	sendMessage(router, host, ws.TypeCreateRoom, map[string]string{"nickname": "Host"})
+	created := readResponse(t, hostCh)
+	var createResp createRoomResponse
+	require.NoError(t, json.Unmarshal(created.Data, &createResp))
+
+	sendMessage(router, browser, ws.TypeListRooms, nil)
+	resp := readResponse(t, browserCh)
+	require.Equal(t, ws.TypeListRooms, resp.Type)
+	var list room.RoomList
+	require.NoError(t, json.Unmarshal(resp.Data, &list))
+	require.Equal(t, 1, list.Total)
+	assert.Equal(t, createResp.Code, list.Rooms[0].Code)
+	assert.Equal(t, "Host", list.Rooms[0].HostNickname)
+
+	sendMessage(router, host, ws.TypeRoomSettings, map[string]string{"visibility": "private"})
+	require.Equal(t, ws.TypeRoomInfo, readResponse(t, hostCh).Type)
+
+	sendMessage(router, browser, ws.TypeListRooms, map[string]int{"offset": 0, "limit": 10})
+	resp = readResponse(t, browserCh)
+	require.NoError(t, json.Unmarshal(resp.Data, &list))
+	assert.Zero(t, list.Total)
+	assert.Empty(t, list.Rooms)
+}
+
+func TestHandleJoinRoom_BindsAccountIdentity(t *testing.T) {
+	router, rm := setupLobbyTest(t)
+	host, hostCh := loginGuest(t, router, "c1", "Host")
+
+	// The nickname in the request is ignored in favor of the account's
+	sendMessage(router, host, ws.TypeCreateRoom, map[string]string{"nickname": "Impostor"})
+	created := readResponse(t, hostCh)
+	require.Equal(t, ws.TypeCreateRoom, created.Type)
+	var createResp createRoomResponse
+	require.NoError(t, json.Unmarshal(created.Data, &createResp))
+
+	r := rm.GetRoom(createResp.Code)
+	require.NotNil(t, r)
+	p := r.GetPlayer(createResp.PlayerID)
+	require.NotNil(t, p)
+	assert.Equal(t, "Host", p.Nickname)
+	assert.Equal(t, host.AccountID, p.AccountID)
+	assert.True(t, p.IsGuest)
+
+	// The same client cannot join again
+	sendMessage(router, host, ws.TypeJoinRoom, map[string]string{"code": createResp.Code})
+	assert.Equal(t, ws.TypeError, readResponse(t, hostCh).Type)
+
+	// Nor can another connection of the same account
+	other, otherCh := newTestClient("c2")
+	other.AccountID, other.Nickname = host.AccountID, host.Nickname
+	other.MarkAuthenticated()
+	sendMessage(router, other, ws.TypeJoinRoom, map[string]string{"code": createResp.Code})
+	assert.Equal(t, ws.TypeError, readResponse(t, otherCh).Type)
+	assert.Equal(t, 1, r.PlayerCount())
+}
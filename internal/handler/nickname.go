@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/nickname"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/store"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// NicknameHandler handles nickname changes.
+type NicknameHandler struct {
+	policy   *nickname.Policy
+	store    store.AccountStore
+	cooldown time.Duration
+	router   *Router
+}
+
+// NewNicknameHandler creates a new nickname handler. A zero cooldown
+// allows changes at any time.
+func NewNicknameHandler(policy *nickname.Policy, store store.AccountStore, cooldown time.Duration, router *Router) *NicknameHandler {
+	return &NicknameHandler{
+		policy:   policy,
+		store:    store,
+		cooldown: cooldown,
+		router:   router,
+	}
+}
+
+type changeNicknameRequest struct {
+	Nickname string `json:"nickname"`
+}
+
+type changeNicknameResponse struct {
+	Nickname     string     `json:"nickname"`
+	NextChangeAt *time.Time `json:"next_change_at,omitempty"`
+}
+
+// HandleChangeNickname renames the client's account, at most once per cooldown.
+func (h *NicknameHandler) HandleChangeNickname(client *ws.Client, msg ws.Message) {
+	var req changeNicknameRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		client.SendMessage(ws.NewErrorMessage("잘못된 닉네임 변경 요청입니다"))
+		return
+	}
+	if h.router.GetPlayerID(client.ID) != "" {
+		client.SendMessage(ws.NewErrorMessage("방에 있는 동안에는 닉네임을 바꿀 수 없습니다"))
+		return
+	}
+
+	name, err := h.policy.Validate(req.Nickname)
+	if err != nil {
+		client.SendMessage(ws.NewErrorMessage(nicknameErrorMessage(err)))
+		return
+	}
+	if name == client.Nickname {
+		client.SendMessage(ws.NewErrorMessage("지금 사용 중인 닉네임입니다"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	acc, err := h.store.FindByID(ctx, client.AccountID)
+	if err != nil || acc == nil {
+		slog.Error("failed to load account for nickname change", "account_id", client.AccountID, "error", err)
+		client.SendMessage(ws.NewErrorMessage("서버 내부 오류입니다"))
+		return
+	}
+	now := time.Now()
+	if acc.NicknameChangedAt != nil {
+		if wait := acc.NicknameChangedAt.Add(h.cooldown).Sub(now); wait > 0 {
+			client.SendMessage(ws.NewErrorMessage(fmt.Sprintf("닉네임은 %s 후에 다시 바꿀 수 있습니다", formatWait(wait))))
+			return
+		}
+	}
+
+	if err := h.store.UpdateNickname(ctx, client.AccountID, name); err != nil {
+		slog.Error("failed to update nickname", "account_id", client.AccountID, "error", err)
+		client.SendMessage(ws.NewErrorMessage("서버 내부 오류입니다"))
+		return
+	}
+	old := client.Nickname
+	client.Nickname = name
+	res := changeNicknameResponse{Nickname: name}
+	if h.cooldown > 0 {
+		next := now.Add(h.cooldown)
+		res.NextChangeAt = &next
+	}
+	resp, _ := ws.NewMessage(ws.TypeChangeNickname, res)
+	client.SendMessage(resp)
+
+	if p, ok := h.router.party.parties.Rename(client.AccountID, name); ok {
+		h.router.party.broadcast(p)
+	}
+
+	slog.Info("nickname changed", "account_id", client.AccountID, "from", old, "to", name)
+}
+
+// formatWait renders a remaining cooldown in whole hours, or minutes under an hour.
+func formatWait(d time.Duration) string {
+	minutes := int((d + time.Minute - 1) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%d분", minutes)
+	}
+	return fmt.Sprintf("%d시간", (minutes+59)/60)
+}
+
+func nicknameErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, nickname.ErrEmpty):
+		return "닉네임을 입력해주세요"
+	case errors.Is(err, nickname.ErrLength):
+		return fmt.Sprintf("닉네임은 %d~%d자여야 합니다", nickname.MinLength, nickname.MaxLength)
+	case errors.Is(err, nickname.ErrCharacters):
+		return "닉네임에는 한글, 영문, 숫자, _만 사용할 수 있습니다"
+	case errors.Is(err, nickname.ErrReserved):
+		return "사용할 수 없는 닉네임입니다"
+	case errors.Is(err, nickname.ErrBanned):
+		return "부적절한 단어가 포함된 닉네임입니다"
+	default:
+		return "잘못된 닉네임입니다"
+	}
+}
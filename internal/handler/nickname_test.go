@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+func setupNicknameTest(t *testing.T, cooldown time.Duration) (*Router, *mockAccountStore) {
+	t.Helper()
+	store := newMockAccountStore()
+	router := NewRouter(room.NewManager(), auth.NewGameCenterVerifier(nil, 0), store, Options{NicknameCooldown: cooldown})
+	return router, store
+}
+
+func readErrorText(t *testing.T, ch chan sentMessage) string {
+	t.Helper()
+	resp := readResponse(t, ch)
+	require.Equal(t, ws.TypeError, resp.Type)
+	var e ws.ErrorMessage
+	require.NoError(t, json.Unmarshal(resp.Data, &e))
+	return e.Message
+}
+
+func TestHandleAuthenticate_RejectsBannedNickname(t *testing.T) {
+	router, store := setupNicknameTest(t, 0)
+	client, ch := newTestClient("c1")
+	sendMessage(router, client, ws.TypeAuthenticate, map[string]string{"method": "guest", "nickname": "씨1발"})
+
+	var res authFailureResponse
+	require.NoError(t, json.Unmarshal(readResponse(t, ch).Data, &res))
+	assert.False(t, res.Success)
+	assert.Equal(t, "부적절한 단어가 포함된 닉네임입니다", res.Error)
+	assert.Empty(t, store.accounts)
+}
+
+func TestChangeNickname(t *testing.T) {
+	router, store := setupNicknameTest(t, time.Hour)
+	client, ch := loginGuest(t, router, "c1", "도둑")
+	sendMessage(router, client, ws.TypePartyCreate, nil)
+	require.Equal(t, ws.TypePartyUpdate, readResponse(t, ch).Type)
+
+	sendMessage(router, client, ws.TypeChangeNickname, map[string]string{"nickname": "운영자"})
+	assert.Equal(t, "사용할 수 없는 닉네임입니다", readErrorText(t, ch))
+
+	sendMessage(router, client, ws.TypeChangeNickname, map[string]string{"nickname": "경찰"})
+	resp := readResponse(t, ch)
+	require.Equal(t, ws.TypeChangeNickname, resp.Type)
+	var res changeNicknameResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &res))
+	assert.Equal(t, "경찰", res.Nickname)
+	require.NotNil(t, res.NextChangeAt)
+	assert.Equal(t, "경찰", client.Nickname)
+	assert.Equal(t, "경찰", store.accounts[client.AccountID].Nickname)
+
+	// Party members see the new nickname
+	update := readResponse(t, ch)
+	require.Equal(t, ws.TypePartyUpdate, update.Type)
+	assert.Contains(t, string(update.Data), "경찰")
+
+	sendMessage(router, client, ws.TypeChangeNickname, map[string]string{"nickname": "경찰왕"})
+	assert.Equal(t, "닉네임은 1시간 후에 다시 바꿀 수 있습니다", readErrorText(t, ch))
+}
+
+func TestChangeNickname_BlockedInRoom(t *testing.T) {
+	router, _ := setupNicknameTest(t, 0)
+	client, ch := loginGuest(t, router, "c1", "도둑")
+	sendMessage(router, client, ws.TypeCreateRoom, nil)
+	require.Equal(t, ws.TypeCreateRoom, readResponse(t, ch).Type)
+	drainCh(ch)
+
+	sendMessage(router, client, ws.TypeChangeNickname, map[string]string{"nickname": "경찰"})
+	assert.Equal(t, "방에 있는 동안에는 닉네임을 바꿀 수 없습니다", readErrorText(t, ch))
+}
@@ -0,0 +1,349 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/party"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// PartyHandler handles parties and the rooms they enter together.
+type PartyHandler struct {
+	parties *party.Manager
+	rm      *room.Manager
+	router  *Router
+}
+
+// NewPartyHandler creates a new party handler.
+func NewPartyHandler(rm *room.Manager, router *Router) *PartyHandler {
+	return &PartyHandler{
+		parties: party.NewManager(),
+		rm:      rm,
+		router:  router,
+	}
+}
+
+type partyInviteRequest struct {
+	AccountID string `json:"account_id"`
+}
+
+type partyJoinRequest struct {
+	PartyID string `json:"party_id"`
+}
+
+type partyInfoResponse struct {
+	PartyID  string         `json:"party_id"`
+	LeaderID string         `json:"leader_id"`
+	Members  []party.Member `json:"members"`
+}
+
+type partyInviteNotice struct {
+	PartyID       string `json:"party_id"`
+	FromAccountID string `json:"from_account_id"`
+	FromNickname  string `json:"from_nickname"`
+}
+
+type partyLeaveResponse struct {
+	PartyID string `json:"party_id"`
+	Kicked  bool   `json:"kicked,omitempty"`
+}
+
+type partyCreateRoomRequest struct {
+	Mode       string `json:"mode,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+	Password   string `json:"password,omitempty"`
+	Team       string `json:"team,omitempty"` // "police" or "thief" to keep the party together
+}
+
+type partyRandomJoinRequest struct {
+	Team string `json:"team,omitempty"`
+}
+
+// HandlePartyCreate starts a party led by the client.
+func (h *PartyHandler) HandlePartyCreate(client *ws.Client, _ ws.Message) {
+	p, err := h.parties.Create(client.AccountID, client.Nickname)
+	if err != nil {
+		client.SendMessage(ws.NewErrorMessage(partyErrorMessage(err)))
+		return
+	}
+	h.broadcast(p)
+
+	slog.Info("party created", "party", p.ID, "leader", client.AccountID)
+}
+
+// HandlePartyInvite invites an online account to the leader's party.
+func (h *PartyHandler) HandlePartyInvite(client *ws.Client, msg ws.Message) {
+	var req partyInviteRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil || req.AccountID == "" {
+		client.SendMessage(ws.NewErrorMessage("초대할 계정을 지정해주세요"))
+		return
+	}
+
+	target := h.router.ClientByAccount(req.AccountID)
+	if target == nil {
+		client.SendMessage(ws.NewErrorMessage("상대가 접속 중이 아닙니다"))
+		return
+	}
+
+	p, err := h.parties.Invite(client.AccountID, req.AccountID)
+	if err != nil {
+		client.SendMessage(ws.NewErrorMessage(partyErrorMessage(err)))
+		return
+	}
+
+	push, _ := ws.NewMessage(ws.TypePartyInviteReceived, partyInviteNotice{
+		PartyID:       p.ID,
+		FromAccountID: client.AccountID,
+		FromNickname:  p.Members[0].Nickname,
+	})
+	target.SendMessage(push)
+
+	resp, _ := ws.NewMessage(ws.TypePartyInvite, partyInviteRequest{AccountID: req.AccountID})
+	client.SendMessage(resp)
+
+	slog.Info("party invite sent", "party", p.ID, "to", req.AccountID)
+}
+
+// HandlePartyJoin joins a party the client was invited to.
+func (h *PartyHandler) HandlePartyJoin(client *ws.Client, msg ws.Message) {
+	var req partyJoinRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil || req.PartyID == "" {
+		client.SendMessage(ws.NewErrorMessage("파티 ID를 입력해주세요"))
+		return
+	}
+
+	p, err := h.parties.Join(req.PartyID, client.AccountID, client.Nickname)
+	if err != nil {
+		client.SendMessage(ws.NewErrorMessage(partyErrorMessage(err)))
+		return
+	}
+	h.broadcast(p)
+
+	slog.Info("party joined", "party", p.ID, "account_id", client.AccountID)
+}
+
+// HandlePartyLeave removes the client from its party.
+func (h *PartyHandler) HandlePartyLeave(client *ws.Client, _ ws.Message) {
+	p, err := h.parties.Leave(client.AccountID)
+	if err != nil {
+		client.SendMessage(ws.NewErrorMessage(partyErrorMessage(err)))
+		return
+	}
+
+	resp, _ := ws.NewMessage(ws.TypePartyLeave, partyLeaveResponse{PartyID: p.ID})
+	client.SendMessage(resp)
+	h.broadcast(p)
+
+	slog.Info("party left", "party", p.ID, "account_id", client.AccountID)
+}
+
+// HandlePartyKick lets the leader remove a member.
+func (h *PartyHandler) HandlePartyKick(client *ws.Client, msg ws.Message) {
+	var req partyInviteRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil || req.AccountID == "" {
+		client.SendMessage(ws.NewErrorMessage("내보낼 계정을 지정해주세요"))
+		return
+	}
+
+	p, err := h.parties.Kick(client.AccountID, req.AccountID)
+	if err != nil {
+		client.SendMessage(ws.NewErrorMessage(partyErrorMessage(err)))
+		return
+	}
+
+	if target := h.router.ClientByAccount(req.AccountID); target != nil {
+		notice, _ := ws.NewMessage(ws.TypePartyLeave, partyLeaveResponse{PartyID: p.ID, Kicked: true})
+		target.SendMessage(notice)
+	}
+	h.broadcast(p)
+
+	slog.Info("party member kicked", "party", p.ID, "account_id", req.AccountID)
+}
+
+// HandlePartyCreateRoom creates a room holding the whole party, led by the leader.
+func (h *PartyHandler) HandlePartyCreateRoom(client *ws.Client, msg ws.Message) {
+	var req partyCreateRoomRequest
+	if len(msg.Data) > 0 {
+		if err := json.Unmarshal(msg.Data, &req); err != nil {
+			client.SendMessage(ws.NewErrorMessage("잘못된 방 생성 요청입니다"))
+			return
+		}
+	}
+
+	mode, visibility, errMsg := parseRoomOptions(createRoomRequest{
+		Mode:       req.Mode,
+		Visibility: req.Visibility,
+		Password:   req.Password,
+	})
+	if errMsg != "" {
+		client.SendMessage(ws.NewErrorMessage(errMsg))
+		return
+	}
+	role, errMsg := parseTeam(req.Team)
+	if errMsg != "" {
+		client.SendMessage(ws.NewErrorMessage(errMsg))
+		return
+	}
+
+	p, players, clients, errMsg := h.prepare(client)
+	if errMsg != "" {
+		client.SendMessage(ws.NewErrorMessage(errMsg))
+		return
+	}
+
+	r := h.rm.CreateRoom()
+	r.SetMode(mode)
+	r.SetVisibility(visibility, req.Password)
+	if !r.AddGroup(players, clients, role) {
+		h.rm.RemoveRoom(r.Code)
+		client.SendMessage(ws.NewErrorMessage("파티원이 모두 들어갈 자리가 없습니다"))
+		return
+	}
+	h.announce(r, players, clients, ws.TypePartyCreateRoom)
+
+	slog.Info("party created room", "party", p.ID, "room", r.Code, "members", len(players), "mode", mode.Name())
+}
+
+// HandlePartyRandomJoin places the whole party into one public waiting room.
+func (h *PartyHandler) HandlePartyRandomJoin(client *ws.Client, msg ws.Message) {
+	var req partyRandomJoinRequest
+	if len(msg.Data) > 0 {
+		if err := json.Unmarshal(msg.Data, &req); err != nil {
+			client.SendMessage(ws.NewErrorMessage("잘못된 입장 요청입니다"))
+			return
+		}
+	}
+
+	role, errMsg := parseTeam(req.Team)
+	if errMsg != "" {
+		client.SendMessage(ws.NewErrorMessage(errMsg))
+		return
+	}
+
+	p, players, clients, errMsg := h.prepare(client)
+	if errMsg != "" {
+		client.SendMessage(ws.NewErrorMessage(errMsg))
+		return
+	}
+
+	r := h.rm.PlaceGroup(players, clients, role)
+	if r == nil {
+		slog.Warn("no available room for party", "party", p.ID, "members", len(players), "team", req.Team, "total_rooms", h.rm.RoomCount())
+		client.SendMessage(ws.NewErrorMessage("파티가 함께 입장할 수 있는 방이 없습니다"))
+		return
+	}
+	h.announce(r, players, clients, ws.TypePartyRandomJoin)
+
+	slog.Info("party random joined room", "party", p.ID, "room", r.Code, "members", len(players))
+}
+
+// HandleDisconnect drops a disconnected client from its party.
+func (h *PartyHandler) HandleDisconnect(client *ws.Client) {
+	if client.AccountID == "" || h.router.ClientByAccount(client.AccountID) != client {
+		return
+	}
+	p, err := h.parties.Leave(client.AccountID)
+	if err != nil {
+		return
+	}
+	h.broadcast(p)
+}
+
+// prepare checks that the client leads a party whose members are all online
+// and outside rooms, and creates a player for each member, leader first.
+// Returns a user-facing error message on failure.
+func (h *PartyHandler) prepare(client *ws.Client) (party.Party, []*game.Player, []*ws.Client, string) {
+	p, ok := h.parties.Get(client.AccountID)
+	if !ok {
+		return party.Party{}, nil, nil, partyErrorMessage(party.ErrNotInParty)
+	}
+	if p.Leader() != client.AccountID {
+		return party.Party{}, nil, nil, partyErrorMessage(party.ErrNotLeader)
+	}
+
+	players := make([]*game.Player, len(p.Members))
+	clients := make([]*ws.Client, len(p.Members))
+	for i, m := range p.Members {
+		c := h.router.ClientByAccount(m.AccountID)
+		if c == nil {
+			return party.Party{}, nil, nil, "접속하지 않은 파티원이 있습니다"
+		}
+		if h.router.GetPlayerID(c.ID) != "" {
+			return party.Party{}, nil, nil, "이미 방에 참가한 파티원이 있습니다"
+		}
+		players[i] = newAccountPlayer(c)
+		clients[i] = c
+	}
+	return p, players, clients, ""
+}
+
+// announce registers placed party members and tells them where they are.
+func (h *PartyHandler) announce(r *room.Room, players []*game.Player, clients []*ws.Client, msgType string) {
+	for i, player := range players {
+		h.router.RegisterPlayer(clients[i].ID, player.ID)
+		resp, _ := ws.NewMessage(msgType, createRoomResponse{
+			Code:     r.Code,
+			PlayerID: player.ID,
+		})
+		clients[i].SendMessage(resp)
+	}
+	h.router.lobby.broadcastRoomInfo(r)
+}
+
+// broadcast sends the party's current state to its online members.
+func (h *PartyHandler) broadcast(p party.Party) {
+	if len(p.Members) == 0 {
+		return
+	}
+	msg, _ := ws.NewMessage(ws.TypePartyUpdate, partyInfoResponse{
+		PartyID:  p.ID,
+		LeaderID: p.Leader(),
+		Members:  p.Members,
+	})
+	for _, id := range p.MemberIDs() {
+		if c := h.router.ClientByAccount(id); c != nil {
+			c.SendMessage(msg)
+		}
+	}
+}
+
+// parseTeam resolves an optional team for the whole party.
+// Returns a user-facing error message on failure.
+func parseTeam(name string) (game.Role, string) {
+	switch name {
+	case "":
+		return game.RoleNone, ""
+	case "police":
+		return game.RolePolice, ""
+	case "thief":
+		return game.RoleThief, ""
+	default:
+		return game.RoleNone, "잘못된 역할입니다"
+	}
+}
+
+func partyErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, party.ErrAlreadyInParty):
+		return "이미 파티에 속해 있습니다"
+	case errors.Is(err, party.ErrNotInParty):
+		return "파티에 속해 있지 않습니다"
+	case errors.Is(err, party.ErrNotFound):
+		return "파티를 찾을 수 없습니다"
+	case errors.Is(err, party.ErrNotLeader):
+		return "파티장만 할 수 있습니다"
+	case errors.Is(err, party.ErrNotInvited):
+		return "파티에 초대받지 않았습니다"
+	case errors.Is(err, party.ErrFull):
+		return fmt.Sprintf("파티는 최대 %d명입니다", party.MaxSize)
+	case errors.Is(err, party.ErrNotMember):
+		return "파티원이 아닙니다"
+	default:
+		return "서버 내부 오류입니다"
+	}
+}
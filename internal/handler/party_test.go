@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// formParty has alice lead a party that bob joins, draining the notices.
+func formParty(t *testing.T, router *Router) (alice *ws.Client, aliceCh chan sentMessage, bob *ws.Client, bobCh chan sentMessage) {
+	t.Helper()
+	alice, aliceCh = loginGuest(t, router, "c1", "Alice")
+	bob, bobCh = loginGuest(t, router, "c2", "Bob")
+
+	sendMessage(router, alice, ws.TypePartyCreate, nil)
+	require.Equal(t, ws.TypePartyUpdate, readResponse(t, aliceCh).Type)
+
+	sendMessage(router, alice, ws.TypePartyInvite, map[string]string{"account_id": bob.AccountID})
+	notice := readResponse(t, bobCh)
+	require.Equal(t, ws.TypePartyInviteReceived, notice.Type)
+	require.Equal(t, ws.TypePartyInvite, readResponse(t, aliceCh).Type)
+	var inv partyInviteNotice
+	require.NoError(t, json.Unmarshal(notice.Data, &inv))
+	assert.Equal(t, "Alice", inv.FromNickname)
+
+	sendMessage(router, bob, ws.TypePartyJoin, map[string]string{"party_id": inv.PartyID})
+	update := readResponse(t, aliceCh)
+	require.Equal(t, ws.TypePartyUpdate, update.Type)
+	require.Equal(t, ws.TypePartyUpdate, readResponse(t, bobCh).Type)
+	var info partyInfoResponse
+	require.NoError(t, json.Unmarshal(update.Data, &info))
+	assert.Equal(t, alice.AccountID, info.LeaderID)
+	require.Len(t, info.Members, 2)
+	return alice, aliceCh, bob, bobCh
+}
+
+func TestParty_CreateRoomTogether(t *testing.T) {
+	router, rm := setupLobbyTest(t)
+	alice, aliceCh, bob, bobCh := formParty(t, router)
+
+	sendMessage(router, bob, ws.TypePartyCreateRoom, nil)
+	assert.Equal(t, ws.TypeError, readResponse(t, bobCh).Type)
+
+	sendMessage(router, alice, ws.TypePartyCreateRoom, map[string]string{"team": "thief"})
+	resp := readResponse(t, aliceCh)
+	require.Equal(t, ws.TypePartyCreateRoom, resp.Type)
+	var created createRoomResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &created))
+	require.Equal(t, ws.TypePartyCreateRoom, readResponse(t, bobCh).Type)
+
+	r := rm.GetRoom(created.Code)
+	require.NotNil(t, r)
+	assert.Equal(t, created.PlayerID, r.HostID)
+	assert.Equal(t, 2, r.PlayerCount())
+	for _, p := range r.GetPlayerList() {
+		assert.Equal(t, game.RoleThief, p.Role)
+	}
+	assert.NotEmpty(t, router.GetPlayerID(bob.ID))
+
+	sendMessage(router, alice, ws.TypePartyRandomJoin, nil)
+	readResponse(t, aliceCh) // room_info
+	assert.Equal(t, ws.TypeError, readResponse(t, aliceCh).Type)
+}
+
+func TestParty_RandomJoinNeedsRoomForAll(t *testing.T) {
+	router, rm := setupLobbyTest(t)
+	alice, aliceCh, bob, bobCh := formParty(t, router)
+
+	almostFull := rm.CreateRoom()
+	for i := 0; i < game.MaxPlayers-1; i++ {
+		almostFull.AddPlayer(game.NewPlayer("p"), &ws.Client{ID: "x", Send: make(chan []byte, 256)})
+	}
+
+	sendMessage(router, alice, ws.TypePartyRandomJoin, nil)
+	assert.Equal(t, ws.TypeError, readResponse(t, aliceCh).Type)
+	assert.Equal(t, game.MaxPlayers-1, almostFull.PlayerCount())
+
+	open := rm.CreateRoom()
+	open.AddPlayer(game.NewPlayer("host"), &ws.Client{ID: "y", Send: make(chan []byte, 256)})
+	sendMessage(router, alice, ws.TypePartyRandomJoin, map[string]string{"team": "police"})
+	require.Equal(t, ws.TypePartyRandomJoin, readResponse(t, aliceCh).Type)
+	require.Equal(t, ws.TypePartyRandomJoin, readResponse(t, bobCh).Type)
+	assert.Equal(t, 3, open.PlayerCount())
+	assert.Equal(t, 2, open.PoliceCount())
+	assert.Same(t, open, rm.FindRoomByPlayerID(router.GetPlayerID(bob.ID)))
+}
+
+func TestParty_LeaveAndKick(t *testing.T) {
+	router, _ := setupLobbyTest(t)
+	alice, aliceCh, bob, bobCh := formParty(t, router)
+
+	sendMessage(router, bob, ws.TypePartyKick, map[string]string{"account_id": alice.AccountID})
+	assert.Equal(t, ws.TypeError, readResponse(t, bobCh).Type)
+
+	sendMessage(router, alice, ws.TypePartyKick, map[string]string{"account_id": bob.AccountID})
+	kicked := readResponse(t, bobCh)
+	require.Equal(t, ws.TypePartyLeave, kicked.Type)
+	var leave partyLeaveResponse
+	require.NoError(t, json.Unmarshal(kicked.Data, &leave))
+	assert.True(t, leave.Kicked)
+	require.Equal(t, ws.TypePartyUpdate, readResponse(t, aliceCh).Type)
+
+	sendMessage(router, bob, ws.TypePartyLeave, nil)
+	assert.Equal(t, ws.TypeError, readResponse(t, bobCh).Type)
+
+	router.HandleDisconnect(alice)
+	sendMessage(router, bob, ws.TypePartyCreate, nil)
+	resp := readResponse(t, bobCh)
+	require.Equal(t, ws.TypePartyUpdate, resp.Type)
+	var info partyInfoResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &info))
+	assert.Equal(t, bob.AccountID, info.LeaderID)
+}
+
+func TestParty_LeaderLeavesPassesLeadership(t *testing.T) {
+	router, _ := setupLobbyTest(t)
+	alice, aliceCh, _, bobCh := formParty(t, router)
+
+	router.HandleDisconnect(alice)
+	update := readResponse(t, bobCh)
+	require.Equal(t, ws.TypePartyUpdate, update.Type)
+	var info partyInfoResponse
+	require.NoError(t, json.Unmarshal(update.Data, &info))
+	require.Len(t, info.Members, 1)
+	assert.Equal(t, info.Members[0].AccountID, info.LeaderID)
+	assert.Empty(t, aliceCh)
+}
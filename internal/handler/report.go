@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/store"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// ReportHandler handles player reports.
+type ReportHandler struct {
+	store  store.ModerationStore
+	rm     *room.Manager
+	router *Router
+}
+
+// NewReportHandler creates a new report handler.
+func NewReportHandler(store store.ModerationStore, rm *room.Manager, router *Router) *ReportHandler {
+	return &ReportHandler{
+		store:  store,
+		rm:     rm,
+		router: router,
+	}
+}
+
+type reportPlayerRequest struct {
+	PlayerID string              `json:"player_id"`
+	Category moderation.Category `json:"category"`
+	Comment  string              `json:"comment"`
+}
+
+type reportPlayerResponse struct {
+	ReportID string                  `json:"report_id"`
+	Status   moderation.ReportStatus `json:"status"`
+}
+
+// HandleReportPlayer files a report against another player in the client's
+// room, in the lobby or after a match. The room's recent match state is
+// attached as evidence; each player can be reported once per match.
+func (h *ReportHandler) HandleReportPlayer(client *ws.Client, msg ws.Message) {
+	var req reportPlayerRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil || req.PlayerID == "" {
+		client.SendMessage(ws.NewErrorMessage("잘못된 신고 요청입니다"))
+		return
+	}
+	if !req.Category.Valid() {
+		client.SendMessage(ws.NewErrorMessage("알 수 없는 신고 유형입니다"))
+		return
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if utf8.RuneCountInString(comment) > moderation.MaxCommentLength {
+		client.SendMessage(ws.NewErrorMessage("신고 내용이 너무 깁니다"))
+		return
+	}
+
+	playerID := h.router.GetPlayerID(client.ID)
+	r := h.rm.FindRoomByPlayerID(playerID)
+	if r == nil {
+		client.SendMessage(ws.NewErrorMessage("방에 참가하고 있지 않습니다"))
+		return
+	}
+	if req.PlayerID == playerID {
+		client.SendMessage(ws.NewErrorMessage("자기 자신은 신고할 수 없습니다"))
+		return
+	}
+	rc, ok := r.ReportContext(req.PlayerID)
+	if !ok {
+		client.SendMessage(ws.NewErrorMessage("플레이어를 찾을 수 없습니다"))
+		return
+	}
+	if rc.State != game.StateWaiting && rc.State != game.StateEnded {
+		client.SendMessage(ws.NewErrorMessage("게임 중에는 신고할 수 없습니다"))
+		return
+	}
+	if rc.TargetAccountID == "" {
+		client.SendMessage(ws.NewErrorMessage("신고할 수 없는 플레이어입니다"))
+		return
+	}
+
+	report := moderation.NewReport(client.AccountID, rc.TargetAccountID, req.Category, comment, rc.RoomCode, rc.MatchID, rc.Evidence)
+	report.TargetNickname = rc.TargetNickname
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.store.CreateReport(ctx, report); err != nil {
+		if errors.Is(err, moderation.ErrDuplicateReport) {
+			client.SendMessage(ws.NewErrorMessage("이번 경기에서 이미 신고한 플레이어입니다"))
+			return
+		}
+		slog.Error("failed to store report", "reporter", client.AccountID, "target", rc.TargetAccountID, "error", err)
+		client.SendMessage(ws.NewErrorMessage("서버 내부 오류입니다"))
+		return
+	}
+
+	slog.Info("player reported", "report", report.ID, "reporter", client.AccountID, "target", rc.TargetAccountID,
+		"category", report.Category, "room", rc.RoomCode)
+	resp, _ := ws.NewMessage(ws.TypeReportPlayer, reportPlayerResponse{ReportID: report.ID, Status: report.Status})
+	client.SendMessage(resp)
+}
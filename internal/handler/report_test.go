@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// seatPlayers puts authenticated clients into a new room as players p1, p2, ...
+func seatPlayers(router *Router, rm *room.Manager, clients ...*ws.Client) *room.Room {
+	r := rm.CreateRoom()
+	for i, c := range clients {
+		id := "p" + string(rune('1'+i))
+		r.AddPlayer(&game.Player{ID: id, AccountID: c.AccountID, Nickname: c.Nickname}, c)
+		router.RegisterPlayer(c.ID, id)
+	}
+	return r
+}
+
+func TestReportPlayer_DedupPerMatch(t *testing.T) {
+	a := setupAdminTest(t, "admin-1")
+	alice, aliceCh := loginGuest(t, a.router, "c1", "Alice")
+	bob, _ := loginGuest(t, a.router, "c2", "Bob")
+	r := seatPlayers(a.router, a.rm, alice, bob)
+
+	sendMessage(a.router, alice, ws.TypeReportPlayer, map[string]string{"player_id": "p2", "category": "abusive_nickname", "comment": "욕설"})
+	resp := readResponse(t, aliceCh)
+	require.Equal(t, ws.TypeReportPlayer, resp.Type)
+	var result reportPlayerResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &result))
+	assert.Equal(t, moderation.StatusOpen, result.Status)
+
+	require.Len(t, a.store.reports, 1)
+	report := a.store.reports[0]
+	assert.Equal(t, alice.AccountID, report.ReporterID)
+	assert.Equal(t, bob.AccountID, report.TargetID)
+	assert.Equal(t, "Bob", report.TargetNickname)
+	assert.Equal(t, r.Code, report.RoomCode)
+	assert.Contains(t, string(report.Evidence), `"state":"waiting"`)
+
+	sendMessage(a.router, alice, ws.TypeReportPlayer, map[string]string{"player_id": "p2", "category": "afk"})
+	assert.Equal(t, "이번 경기에서 이미 신고한 플레이어입니다", readErrorText(t, aliceCh))
+
+	// A new match allows a new report; reports are refused while it is played
+	r.PrepareGame()
+	sendMessage(a.router, alice, ws.TypeReportPlayer, map[string]string{"player_id": "p2", "category": "cheating"})
+	assert.Equal(t, "게임 중에는 신고할 수 없습니다", readErrorText(t, aliceCh))
+	r.StopGame(game.WinPolice)
+	for readResponse(t, aliceCh).Type != ws.TypeGameOver {
+	}
+
+	sendMessage(a.router, alice, ws.TypeReportPlayer, map[string]string{"player_id": "p2", "category": "cheating"})
+	require.Equal(t, ws.TypeReportPlayer, readResponse(t, aliceCh).Type)
+	require.Len(t, a.store.reports, 2)
+	assert.NotEqual(t, a.store.reports[0].MatchID, a.store.reports[1].MatchID)
+	assert.Contains(t, string(a.store.reports[1].Evidence), `"state":"ended"`)
+}
+
+func TestReportPlayer_Invalid(t *testing.T) {
+	a := setupAdminTest(t, "admin-1")
+	alice, aliceCh := loginGuest(t, a.router, "c1", "Alice")
+	bob, _ := loginGuest(t, a.router, "c2", "Bob")
+
+	sendMessage(a.router, alice, ws.TypeReportPlayer, map[string]string{"player_id": "p2", "category": "cheating"})
+	assert.Equal(t, "방에 참가하고 있지 않습니다", readErrorText(t, aliceCh))
+
+	seatPlayers(a.router, a.rm, alice, bob)
+	tests := []struct {
+		name string
+		data map[string]string
+		want string
+	}{
+		{"unknown category", map[string]string{"player_id": "p2", "category": "rude"}, "알 수 없는 신고 유형입니다"},
+		{"self", map[string]string{"player_id": "p1", "category": "afk"}, "자기 자신은 신고할 수 없습니다"},
+		{"unknown player", map[string]string{"player_id": "p9", "category": "afk"}, "플레이어를 찾을 수 없습니다"},
+		{"missing player", map[string]string{"category": "afk"}, "잘못된 신고 요청입니다"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sendMessage(a.router, alice, ws.TypeReportPlayer, tt.data)
+			assert.Equal(t, tt.want, readErrorText(t, aliceCh))
+		})
+	}
+	assert.Empty(t, a.store.reports)
+}
+
+func TestReportPlayer_Disabled(t *testing.T) {
+	router := NewRouter(room.NewManager(), auth.NewGameCenterVerifier(nil, 0), newMockAccountStore(), Options{})
+	alice, aliceCh := loginGuest(t, router, "c1", "Alice")
+	sendMessage(router, alice, ws.TypeReportPlayer, map[string]string{"player_id": "p2", "category": "afk"})
+	assert.Equal(t, "신고 기능이 비활성화되어 있습니다", readErrorText(t, aliceCh))
+}
+
+func TestAdmin_ReviewReportQueue(t *testing.T) {
+	a := setupAdminTest(t, "admin-1")
+	alice, aliceCh := loginGuest(t, a.router, "c1", "Alice")
+	bob, _ := loginGuest(t, a.router, "c2", "Bob")
+	seatPlayers(a.router, a.rm, alice, bob)
+	sendMessage(a.router, alice, ws.TypeReportPlayer, map[string]string{"player_id": "p2", "category": "afk"})
+	require.Equal(t, ws.TypeReportPlayer, readResponse(t, aliceCh).Type)
+
+	rec := a.do(t, "admin-1", http.MethodGet, "/admin/reports?status=open", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var queue struct {
+		Reports []moderation.Report `json:"reports"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &queue))
+	require.Len(t, queue.Reports, 1)
+	id := queue.Reports[0].ID
+
+	rec = a.do(t, "admin-1", http.MethodPost, "/admin/reports/"+id+"/review", `{"status":"open"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = a.do(t, "admin-1", http.MethodPost, "/admin/reports/"+id+"/review", `{"status":"dismissed","note":"no evidence"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = a.do(t, "admin-1", http.MethodPost, "/admin/reports/"+id+"/review", `{"status":"actioned"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = a.do(t, "admin-1", http.MethodPost, "/admin/reports/missing/review", `{"status":"actioned"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = a.do(t, "admin-1", http.MethodGet, "/admin/reports?status=open", "")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &queue))
+	assert.Empty(t, queue.Reports)
+}
@@ -4,35 +4,155 @@ import (
 	"encoding/json"
 	"log/slog"
 	"sync"
+	"time"
 
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/achievement"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/anticheat"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/nickname"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/session"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/store"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
 )
 
+// Options configures optional router subsystems.
+// A nil store disables the subsystem that depends on it.
+type Options struct {
+	AchievementStore store.AchievementStore
+	Achievements     []achievement.Definition
+
+	LeaderboardStore store.LeaderboardStore
+	Season           string // active season ID, empty to disable seasons
+
+	FriendStore store.FriendStore
+
+	// AuthProviders are registered alongside the built-in Game Center and guest providers.
+	AuthProviders []auth.AuthProvider
+
+	// GuestCredentials signs guest device credentials. Nil uses a random key,
+	// so guests cannot sign back in after a restart.
+	GuestCredentials *auth.GuestCredentials
+
+	// SessionTokens signs session tokens. Nil uses a random key, so tokens
+	// do not survive a restart.
+	SessionTokens *auth.SessionTokens
+
+	// NicknamePolicy validates nicknames. Nil uses the built-in banned words.
+	NicknamePolicy *nickname.Policy
+	// NicknameCooldown is the minimum time between nickname changes.
+	NicknameCooldown time.Duration
+
+	// ModerationStore enables ban checks at sign-in, player reports and,
+	// with AdminAccountIDs, the admin HTTP API.
+	ModerationStore store.ModerationStore
+	AdminAccountIDs []string
+
+	// AntiCheat tunes violation scoring. Nil uses anticheat.DefaultConfig.
+	AntiCheat *anticheat.Config
+}
+
 // Router dispatches incoming messages to the appropriate handler.
 type Router struct {
-	authH    *AuthHandler
-	lobby    *LobbyHandler
-	gameplay *GameplayHandler
+	authH        *AuthHandler
+	link         *LinkHandler
+	nickname     *NicknameHandler
+	lobby        *LobbyHandler
+	gameplay     *GameplayHandler
+	achievements *AchievementHandler
+	leaderboard  *LeaderboardHandler
+	social       *SocialHandler
+	party        *PartyHandler
+	report       *ReportHandler
+	admin        *AdminHandler
+
+	anticheat  *anticheat.Monitor
+	moderation store.ModerationStore // nil disables the audit of anti-cheat actions
+	violations *violationLog         // nil when moderation is nil
 
 	// playerMap tracks client ID -> player ID mapping, shared across handlers.
 	playerMap map[string]string
-	mu        sync.RWMutex
+	// sessions tracks the single active client of each authenticated account.
+	sessions *session.Registry
+	mu       sync.RWMutex
 }
 
 // NewRouter creates a new message router.
-func NewRouter(rm *room.Manager, verifier *auth.GameCenterVerifier, accountStore store.AccountStore) *Router {
+func NewRouter(rm *room.Manager, verifier *auth.GameCenterVerifier, accountStore store.AccountStore, opts Options) *Router {
+	cheatConfig := anticheat.DefaultConfig()
+	if opts.AntiCheat != nil {
+		cheatConfig = *opts.AntiCheat
+	}
 	r := &Router{
-		playerMap: make(map[string]string),
+		anticheat:  anticheat.NewMonitor(cheatConfig),
+		moderation: opts.ModerationStore,
+		playerMap:  make(map[string]string),
+		sessions:   session.NewRegistry(),
+	}
+	if opts.ModerationStore != nil {
+		r.violations = newViolationLog(opts.ModerationStore)
 	}
 	r.authH = NewAuthHandler(verifier, accountStore)
+	r.authH.onAuthenticated = r.registerAccount
+	if opts.GuestCredentials != nil {
+		r.authH.SetGuestCredentials(opts.GuestCredentials)
+	}
+	if opts.SessionTokens != nil {
+		r.authH.SetSessionTokens(opts.SessionTokens)
+	}
+	r.authH.bans = opts.ModerationStore
+	if opts.NicknamePolicy != nil {
+		r.authH.SetNicknamePolicy(opts.NicknamePolicy)
+	}
+	for _, p := range opts.AuthProviders {
+		r.authH.RegisterProvider(p)
+	}
+	r.party = NewPartyHandler(rm, r)
+	r.link = NewLinkHandler(verifier, accountStore, r.authH.tokens, r.party.parties, opts.ModerationStore, r)
+	r.nickname = NewNicknameHandler(r.authH.nicknames, accountStore, opts.NicknameCooldown, r)
 	r.lobby = NewLobbyHandler(rm, r)
 	r.gameplay = NewGameplayHandler(rm, r)
+	if opts.AchievementStore != nil {
+		r.achievements = NewAchievementHandler(opts.Achievements, opts.AchievementStore)
+	}
+	if opts.LeaderboardStore != nil {
+		r.leaderboard = NewLeaderboardHandler(opts.LeaderboardStore, opts.Season)
+	}
+	if opts.ModerationStore != nil {
+		r.report = NewReportHandler(opts.ModerationStore, rm, r)
+	}
+	if opts.ModerationStore != nil && len(opts.AdminAccountIDs) > 0 {
+		r.admin = NewAdminHandler(opts.ModerationStore, accountStore, opts.AdminAccountIDs, r)
+	}
+	if opts.FriendStore != nil {
+		r.social = NewSocialHandler(opts.FriendStore, accountStore, rm, r)
+	}
+
+	rm.OnGameOver = r.handleGameOver
+	rm.OnAFKRemoved = r.lobby.HandleAFKRemoved
 	return r
 }
 
+// handleGameOver runs post-game processing off the room's game loop goroutine.
+func (r *Router) handleGameOver(rm *room.Room, result room.MatchResult) {
+	if r.achievements != nil {
+		go r.achievements.HandleGameOver(rm, result)
+	}
+	if r.leaderboard != nil {
+		go r.leaderboard.HandleGameOver(result)
+	}
+}
+
+// Leaderboard returns the leaderboard handler for HTTP routing, or nil if disabled.
+func (r *Router) Leaderboard() *LeaderboardHandler {
+	return r.leaderboard
+}
+
+// Admin returns the admin HTTP handler, or nil if disabled.
+func (r *Router) Admin() *AdminHandler {
+	return r.admin
+}
+
 // RegisterPlayer maps a client ID to a player ID.
 func (r *Router) RegisterPlayer(clientID, playerID string) {
 	r.mu.Lock()
@@ -54,6 +174,29 @@ func (r *Router) GetPlayerID(clientID string) string {
 	return r.playerMap[clientID]
 }
 
+// registerAccount makes an authenticated client its account's active session.
+// An older session of the same account is told it was replaced and closed;
+// its disconnect then cleans up its room as usual.
+func (r *Router) registerAccount(client *ws.Client) {
+	old := r.sessions.Register(client)
+	if old == nil {
+		return
+	}
+	msg, _ := ws.NewMessage(ws.TypeSessionReplaced, nil)
+	old.Kick(msg)
+	slog.Info("session replaced", "account_id", client.AccountID, "old_client", old.ID, "client", client.ID)
+}
+
+// unregisterAccount ends the client's session unless a newer one replaced it.
+func (r *Router) unregisterAccount(client *ws.Client) {
+	r.sessions.Unregister(client)
+}
+
+// ClientByAccount returns the connected client of an account, or nil if offline.
+func (r *Router) ClientByAccount(accountID string) *ws.Client {
+	return r.sessions.Get(accountID)
+}
+
 // HandleMessage parses and routes an incoming client message.
 func (r *Router) HandleMessage(cm *ws.ClientMessage) {
 	var msg ws.Message
@@ -70,12 +213,21 @@ func (r *Router) HandleMessage(cm *ws.ClientMessage) {
 	}
 
 	// Auth guard: block unauthenticated clients
-	if !cm.Client.Authenticated {
+	if !cm.Client.Authenticated() {
 		cm.Client.SendMessage(ws.NewErrorMessage("인증이 필요합니다"))
 		return
 	}
 
 	switch msg.Type {
+	case ws.TypeLinkAccount:
+		r.link.HandleLinkAccount(cm.Client, msg)
+	case ws.TypeRefreshToken:
+		r.authH.HandleRefreshToken(cm.Client, msg)
+	case ws.TypeRevokeToken:
+		r.authH.HandleRevokeToken(cm.Client, msg)
+	case ws.TypeChangeNickname:
+		r.nickname.HandleChangeNickname(cm.Client, msg)
+
 	// Lobby messages
 	case ws.TypeCreateRoom:
 		r.lobby.HandleCreateRoom(cm.Client, msg)
@@ -91,10 +243,65 @@ func (r *Router) HandleMessage(cm *ws.ClientMessage) {
 		r.lobby.HandlePlayerReady(cm.Client, msg)
 	case ws.TypeReturnToLobby:
 		r.lobby.HandleReturnToLobby(cm.Client, msg)
+	case ws.TypeRoomSettings:
+		r.lobby.HandleRoomSettings(cm.Client, msg)
+	case ws.TypeListRooms:
+		r.lobby.HandleListRooms(cm.Client, msg)
 
 	// Gameplay messages
 	case ws.TypePlayerMove:
 		r.gameplay.HandlePlayerMove(cm.Client, msg)
+	case ws.TypeClientLoaded:
+		r.gameplay.HandleClientLoaded(cm.Client, msg)
+
+	// Achievement messages
+	case ws.TypeGetAchievements:
+		if r.achievements == nil {
+			cm.Client.SendMessage(ws.NewErrorMessage("업적 기능이 비활성화되어 있습니다"))
+			return
+		}
+		r.achievements.HandleGetAchievements(cm.Client, msg)
+
+	// Leaderboard messages
+	case ws.TypeGetLeaderboard:
+		if r.leaderboard == nil {
+			cm.Client.SendMessage(ws.NewErrorMessage("리더보드 기능이 비활성화되어 있습니다"))
+			return
+		}
+		r.leaderboard.HandleGetLeaderboard(cm.Client, msg)
+
+	// Social messages
+	case ws.TypeListFriends, ws.TypeFriendRequest, ws.TypeFriendAccept, ws.TypeFriendRemove,
+		ws.TypeInviteToRoom, ws.TypeAcceptInvite:
+		if r.social == nil {
+			cm.Client.SendMessage(ws.NewErrorMessage("친구 기능이 비활성화되어 있습니다"))
+			return
+		}
+		r.routeSocial(cm.Client, msg)
+
+	// Party messages
+	case ws.TypePartyCreate:
+		r.party.HandlePartyCreate(cm.Client, msg)
+	case ws.TypePartyInvite:
+		r.party.HandlePartyInvite(cm.Client, msg)
+	case ws.TypePartyJoin:
+		r.party.HandlePartyJoin(cm.Client, msg)
+	case ws.TypePartyLeave:
+		r.party.HandlePartyLeave(cm.Client, msg)
+	case ws.TypePartyKick:
+		r.party.HandlePartyKick(cm.Client, msg)
+	case ws.TypePartyCreateRoom:
+		r.party.HandlePartyCreateRoom(cm.Client, msg)
+	case ws.TypePartyRandomJoin:
+		r.party.HandlePartyRandomJoin(cm.Client, msg)
+
+	// Moderation messages
+	case ws.TypeReportPlayer:
+		if r.report == nil {
+			cm.Client.SendMessage(ws.NewErrorMessage("신고 기능이 비활성화되어 있습니다"))
+			return
+		}
+		r.report.HandleReportPlayer(cm.Client, msg)
 
 	default:
 		slog.Warn("unknown message type", "type", msg.Type, "client", cm.Client.ID)
@@ -102,9 +309,28 @@ func (r *Router) HandleMessage(cm *ws.ClientMessage) {
 	}
 }
 
+func (r *Router) routeSocial(client *ws.Client, msg ws.Message) {
+	switch msg.Type {
+	case ws.TypeListFriends:
+		r.social.HandleListFriends(client, msg)
+	case ws.TypeFriendRequest:
+		r.social.HandleFriendRequest(client, msg)
+	case ws.TypeFriendAccept:
+		r.social.HandleFriendAccept(client, msg)
+	case ws.TypeFriendRemove:
+		r.social.HandleFriendRemove(client, msg)
+	case ws.TypeInviteToRoom:
+		r.social.HandleInviteToRoom(client, msg)
+	case ws.TypeAcceptInvite:
+		r.social.HandleAcceptInvite(client, msg)
+	}
+}
+
 // HandleDisconnect handles client disconnection.
 func (r *Router) HandleDisconnect(client *ws.Client) {
 	r.lobby.HandleDisconnect(client)
+	r.party.HandleDisconnect(client)
+	r.unregisterAccount(client)
 }
 
 // StartAuthTimeout starts the authentication timeout for a new client.
@@ -0,0 +1,339 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/social"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/store"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// SocialHandler handles friends, presence and room invites.
+type SocialHandler struct {
+	store    store.FriendStore
+	accounts store.AccountStore
+	rm       *room.Manager
+	router   *Router
+	invites  *social.InviteBook
+}
+
+// NewSocialHandler creates a new social handler.
+func NewSocialHandler(friends store.FriendStore, accounts store.AccountStore, rm *room.Manager, router *Router) *SocialHandler {
+	return &SocialHandler{
+		store:    friends,
+		accounts: accounts,
+		rm:       rm,
+		router:   router,
+		invites:  social.NewInviteBook(social.InviteTTL),
+	}
+}
+
+type friendTargetRequest struct {
+	AccountID string `json:"account_id"`
+}
+
+type friendStatusResponse struct {
+	AccountID string              `json:"account_id"`
+	Status    social.FriendStatus `json:"status,omitempty"`
+}
+
+type friendNotice struct {
+	AccountID string `json:"account_id"`
+	Nickname  string `json:"nickname"`
+}
+
+type listFriendsResponse struct {
+	Friends []social.Friend `json:"friends"`
+}
+
+type inviteSentResponse struct {
+	AccountID string `json:"account_id"`
+	InviteID  string `json:"invite_id"`
+}
+
+type acceptInviteRequest struct {
+	InviteID string `json:"invite_id"`
+}
+
+// HandleListFriends returns the client's friends and requests with presence.
+func (h *SocialHandler) HandleListFriends(client *ws.Client, _ ws.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	friends, err := h.store.ListFriends(ctx, client.AccountID)
+	if err != nil {
+		slog.Error("failed to list friends", "account_id", client.AccountID, "error", err)
+		client.SendMessage(ws.NewErrorMessage(socialErrorMessage(err)))
+		return
+	}
+	if friends == nil {
+		friends = []social.Friend{}
+	}
+	for i := range friends {
+		if friends[i].Status == social.FriendAccepted {
+			p := h.Presence(friends[i].AccountID)
+			friends[i].Presence = &p
+		}
+	}
+
+	resp, _ := ws.NewMessage(ws.TypeListFriends, listFriendsResponse{Friends: friends})
+	client.SendMessage(resp)
+}
+
+// HandleFriendRequest sends a friend request, accepting it if the other account already asked.
+func (h *SocialHandler) HandleFriendRequest(client *ws.Client, msg ws.Message) {
+	var req friendTargetRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil || req.AccountID == "" {
+		client.SendMessage(ws.NewErrorMessage("친구 계정을 지정해주세요"))
+		return
+	}
+	if req.AccountID == client.AccountID {
+		client.SendMessage(ws.NewErrorMessage(socialErrorMessage(social.ErrSelfRequest)))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	target, err := h.accounts.FindByID(ctx, req.AccountID)
+	if err != nil {
+		slog.Error("failed to find account", "account_id", req.AccountID, "error", err)
+		client.SendMessage(ws.NewErrorMessage("서버 내부 오류입니다"))
+		return
+	}
+	if target == nil {
+		client.SendMessage(ws.NewErrorMessage("계정을 찾을 수 없습니다"))
+		return
+	}
+
+	friends, err := h.store.ListFriends(ctx, client.AccountID)
+	if err != nil {
+		slog.Error("failed to list friends", "account_id", client.AccountID, "error", err)
+		client.SendMessage(ws.NewErrorMessage(socialErrorMessage(err)))
+		return
+	}
+	if len(friends) >= social.MaxFriends {
+		client.SendMessage(ws.NewErrorMessage(socialErrorMessage(social.ErrTooManyFriends)))
+		return
+	}
+
+	status, err := h.store.RequestFriend(ctx, client.AccountID, req.AccountID)
+	if err != nil {
+		client.SendMessage(ws.NewErrorMessage(socialErrorMessage(err)))
+		if !isSocialRequestError(err) {
+			slog.Error("failed to request friend", "from", client.AccountID, "to", req.AccountID, "error", err)
+		}
+		return
+	}
+
+	resp, _ := ws.NewMessage(ws.TypeFriendRequest, friendStatusResponse{AccountID: req.AccountID, Status: status})
+	client.SendMessage(resp)
+
+	notice := ws.TypeFriendRequestReceived
+	if status == social.FriendAccepted {
+		notice = ws.TypeFriendAccepted
+	}
+	h.notify(ctx, req.AccountID, notice, client.AccountID)
+
+	slog.Info("friend requested", "from", client.AccountID, "to", req.AccountID, "status", status)
+}
+
+// HandleFriendAccept accepts a pending friend request.
+func (h *SocialHandler) HandleFriendAccept(client *ws.Client, msg ws.Message) {
+	var req friendTargetRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil || req.AccountID == "" {
+		client.SendMessage(ws.NewErrorMessage("친구 계정을 지정해주세요"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.store.AcceptFriend(ctx, client.AccountID, req.AccountID); err != nil {
+		client.SendMessage(ws.NewErrorMessage(socialErrorMessage(err)))
+		if !isSocialRequestError(err) {
+			slog.Error("failed to accept friend", "account_id", client.AccountID, "requester", req.AccountID, "error", err)
+		}
+		return
+	}
+
+	resp, _ := ws.NewMessage(ws.TypeFriendAccept, friendStatusResponse{AccountID: req.AccountID, Status: social.FriendAccepted})
+	client.SendMessage(resp)
+
+	h.notify(ctx, req.AccountID, ws.TypeFriendAccepted, client.AccountID)
+
+	slog.Info("friend accepted", "account_id", client.AccountID, "requester", req.AccountID)
+}
+
+// HandleFriendRemove removes a friend, or cancels or declines a pending request.
+func (h *SocialHandler) HandleFriendRemove(client *ws.Client, msg ws.Message) {
+	var req friendTargetRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil || req.AccountID == "" {
+		client.SendMessage(ws.NewErrorMessage("친구 계정을 지정해주세요"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.store.RemoveFriend(ctx, client.AccountID, req.AccountID); err != nil {
+		client.SendMessage(ws.NewErrorMessage(socialErrorMessage(err)))
+		if !isSocialRequestError(err) {
+			slog.Error("failed to remove friend", "account_id", client.AccountID, "friend", req.AccountID, "error", err)
+		}
+		return
+	}
+
+	resp, _ := ws.NewMessage(ws.TypeFriendRemove, friendStatusResponse{AccountID: req.AccountID})
+	client.SendMessage(resp)
+
+	slog.Info("friend removed", "account_id", client.AccountID, "friend", req.AccountID)
+}
+
+// HandleInviteToRoom invites an online friend to the client's current room.
+func (h *SocialHandler) HandleInviteToRoom(client *ws.Client, msg ws.Message) {
+	var req friendTargetRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil || req.AccountID == "" {
+		client.SendMessage(ws.NewErrorMessage("친구 계정을 지정해주세요"))
+		return
+	}
+
+	playerID := h.router.GetPlayerID(client.ID)
+	r := h.rm.FindRoomByPlayerID(playerID)
+	if r == nil {
+		client.SendMessage(ws.NewErrorMessage("방에 참가하고 있지 않습니다"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ok, err := h.store.AreFriends(ctx, client.AccountID, req.AccountID)
+	if err != nil {
+		slog.Error("failed to check friendship", "account_id", client.AccountID, "friend", req.AccountID, "error", err)
+		client.SendMessage(ws.NewErrorMessage("서버 내부 오류입니다"))
+		return
+	}
+	if !ok {
+		client.SendMessage(ws.NewErrorMessage(socialErrorMessage(social.ErrNotFriends)))
+		return
+	}
+
+	target := h.router.ClientByAccount(req.AccountID)
+	if target == nil {
+		client.SendMessage(ws.NewErrorMessage("친구가 접속 중이 아닙니다"))
+		return
+	}
+
+	inv := h.invites.Add(client.AccountID, client.Nickname, req.AccountID, r.Code, r.ID, time.Now())
+
+	push, _ := ws.NewMessage(ws.TypeRoomInvite, inv)
+	target.SendMessage(push)
+
+	resp, _ := ws.NewMessage(ws.TypeInviteToRoom, inviteSentResponse{AccountID: req.AccountID, InviteID: inv.ID})
+	client.SendMessage(resp)
+
+	slog.Info("room invite sent", "from", client.AccountID, "to", req.AccountID, "room", r.Code)
+}
+
+// HandleAcceptInvite joins the room an invite points to.
+// Invites bypass room passwords and private visibility.
+func (h *SocialHandler) HandleAcceptInvite(client *ws.Client, msg ws.Message) {
+	var req acceptInviteRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil || req.InviteID == "" {
+		client.SendMessage(ws.NewErrorMessage("초대 ID를 입력해주세요"))
+		return
+	}
+
+	if h.router.GetPlayerID(client.ID) != "" {
+		client.SendMessage(ws.NewErrorMessage("이미 방에 참가하고 있습니다"))
+		return
+	}
+
+	inv, err := h.invites.Take(req.InviteID, client.AccountID, time.Now())
+	if err != nil {
+		client.SendMessage(ws.NewErrorMessage(socialErrorMessage(err)))
+		return
+	}
+
+	// The code may belong to a newer room if the invited one was removed
+	r := h.rm.GetRoom(inv.RoomCode)
+	if r == nil || r.ID != inv.RoomID {
+		client.SendMessage(ws.NewErrorMessage("방을 찾을 수 없습니다"))
+		return
+	}
+	if r.CurrentState() != game.StateWaiting {
+		client.SendMessage(ws.NewErrorMessage("게임이 진행 중인 방입니다"))
+		return
+	}
+
+	if h.router.lobby.joinRoom(client, r) {
+		slog.Info("player joined room by invite", "account_id", client.AccountID, "room", r.Code, "from", inv.FromAccountID)
+	}
+}
+
+// Presence derives an account's presence from its connection and room.
+func (h *SocialHandler) Presence(accountID string) social.Presence {
+	client := h.router.ClientByAccount(accountID)
+	if client == nil {
+		return social.Presence{Status: social.PresenceOffline}
+	}
+	r := h.rm.FindRoomByPlayerID(h.router.GetPlayerID(client.ID))
+	if r == nil {
+		return social.Presence{Status: social.PresenceOnline}
+	}
+	if r.CurrentState() == game.StateWaiting {
+		return social.Presence{Status: social.PresenceLobby, RoomCode: r.Code}
+	}
+	return social.Presence{Status: social.PresenceInGame, RoomCode: r.Code}
+}
+
+// notify pushes a friend notice about fromAccountID to accountID if it is online.
+func (h *SocialHandler) notify(ctx context.Context, accountID, msgType, fromAccountID string) {
+	target := h.router.ClientByAccount(accountID)
+	if target == nil {
+		return
+	}
+	notice := friendNotice{AccountID: fromAccountID}
+	if acc, err := h.accounts.FindByID(ctx, fromAccountID); err == nil && acc != nil {
+		notice.Nickname = acc.Nickname
+	}
+	msg, _ := ws.NewMessage(msgType, notice)
+	target.SendMessage(msg)
+}
+
+func isSocialRequestError(err error) bool {
+	return errors.Is(err, social.ErrAlreadyFriends) ||
+		errors.Is(err, social.ErrAlreadyRequested) ||
+		errors.Is(err, social.ErrRequestNotFound) ||
+		errors.Is(err, social.ErrNotFriends)
+}
+
+func socialErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, social.ErrSelfRequest):
+		return "자신에게는 친구 요청을 보낼 수 없습니다"
+	case errors.Is(err, social.ErrAlreadyFriends):
+		return "이미 친구입니다"
+	case errors.Is(err, social.ErrAlreadyRequested):
+		return "이미 친구 요청을 보냈습니다"
+	case errors.Is(err, social.ErrRequestNotFound):
+		return "친구 요청을 찾을 수 없습니다"
+	case errors.Is(err, social.ErrNotFriends):
+		return "친구가 아닙니다"
+	case errors.Is(err, social.ErrTooManyFriends):
+		return "친구 목록이 가득 찼습니다"
+	case errors.Is(err, social.ErrInviteNotFound):
+		return "초대를 찾을 수 없습니다"
+	case errors.Is(err, social.ErrInviteExpired):
+		return "초대가 만료되었습니다"
+	default:
+		return "서버 내부 오류입니다"
+	}
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/room"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/social"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+type friendLink struct {
+	requester string
+	status    social.FriendStatus
+}
+
+// mockFriendStore implements store.FriendStore for testing.
+type mockFriendStore struct {
+	links    map[[2]string]*friendLink
+	accounts *mockAccountStore
+}
+
+func newMockFriendStore(accounts *mockAccountStore) *mockFriendStore {
+	return &mockFriendStore{links: make(map[[2]string]*friendLink), accounts: accounts}
+}
+
+func pairKey(a, b string) [2]string {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+func (m *mockFriendStore) RequestFriend(_ context.Context, from, to string) (social.FriendStatus, error) {
+	l, ok := m.links[pairKey(from, to)]
+	switch {
+	case !ok:
+		m.links[pairKey(from, to)] = &friendLink{requester: from, status: social.FriendPending}
+		return social.FriendPending, nil
+	case l.status == social.FriendAccepted:
+		return "", social.ErrAlreadyFriends
+	case l.requester == from:
+		return "", social.ErrAlreadyRequested
+	default:
+		l.status = social.FriendAccepted
+		return social.FriendAccepted, nil
+	}
+}
+
+func (m *mockFriendStore) AcceptFriend(_ context.Context, accountID, requesterID string) error {
+	l, ok := m.links[pairKey(accountID, requesterID)]
+	if !ok || l.requester != requesterID || l.status != social.FriendPending {
+		return social.ErrRequestNotFound
+	}
+	l.status = social.FriendAccepted
+	return nil
+}
+
+func (m *mockFriendStore) RemoveFriend(_ context.Context, a, b string) error {
+	if _, ok := m.links[pairKey(a, b)]; !ok {
+		return social.ErrNotFriends
+	}
+	delete(m.links, pairKey(a, b))
+	return nil
+}
+
+func (m *mockFriendStore) ListFriends(_ context.Context, accountID string) ([]social.Friend, error) {
+	var friends []social.Friend
+	for key, l := range m.links {
+		other := key[0]
+		if other == accountID {
+			other = key[1]
+		} else if key[1] != accountID {
+			continue
+		}
+		f := social.Friend{AccountID: other, Status: l.status, Incoming: l.status == social.FriendPending && l.requester != accountID}
+		if acc := m.accounts.accounts[other]; acc != nil {
+			f.Nickname = acc.Nickname
+		}
+		friends = append(friends, f)
+	}
+	return friends, nil
+}
+
+func (m *mockFriendStore) AreFriends(_ context.Context, a, b string) (bool, error) {
+	l, ok := m.links[pairKey(a, b)]
+	return ok && l.status == social.FriendAccepted, nil
+}
+
+func setupSocialTest(t *testing.T) (*Router, *room.Manager) {
+	t.Helper()
+	accounts := newMockAccountStore()
+	rm := room.NewManager()
+	router := NewRouter(rm, auth.NewGameCenterVerifier(nil, 0), accounts, Options{
+		FriendStore: newMockFriendStore(accounts),
+	})
+	return router, rm
+}
+
+// loginGuest authenticates a new test client as a guest.
+func loginGuest(t *testing.T, router *Router, id, nickname string) (*ws.Client, chan sentMessage) {
+	t.Helper()
+	client, ch := newTestClient(id)
+	sendMessage(router, client, ws.TypeAuthenticate, map[string]string{"method": "guest", "nickname": nickname})
+	require.Equal(t, ws.TypeAuthResult, readResponse(t, ch).Type)
+	require.True(t, client.Authenticated())
+	return client, ch
+}
+
+func TestSocial_FriendRequestAndAccept(t *testing.T) {
+	router, _ := setupSocialTest(t)
+	alice, aliceCh := loginGuest(t, router, "c1", "Alice")
+	bob, bobCh := loginGuest(t, router, "c2", "Bob")
+
+	sendMessage(router, alice, ws.TypeFriendRequest, map[string]string{"account_id": bob.AccountID})
+	resp := readResponse(t, aliceCh)
+	require.Equal(t, ws.TypeFriendRequest, resp.Type)
+	var status friendStatusResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &status))
+	assert.Equal(t, social.FriendPending, status.Status)
+
+	received := readResponse(t, bobCh)
+	require.Equal(t, ws.TypeFriendRequestReceived, received.Type)
+	var notice friendNotice
+	require.NoError(t, json.Unmarshal(received.Data, &notice))
+	assert.Equal(t, alice.AccountID, notice.AccountID)
+	assert.Equal(t, "Alice", notice.Nickname)
+
+	sendMessage(router, bob, ws.TypeFriendAccept, map[string]string{"account_id": alice.AccountID})
+	assert.Equal(t, ws.TypeFriendAccept, readResponse(t, bobCh).Type)
+	assert.Equal(t, ws.TypeFriendAccepted, readResponse(t, aliceCh).Type)
+
+	sendMessage(router, alice, ws.TypeListFriends, nil)
+	resp = readResponse(t, aliceCh)
+	require.Equal(t, ws.TypeListFriends, resp.Type)
+	var list listFriendsResponse
+	require.NoError(t, json.Unmarshal(resp.Data, &list))
+	require.Len(t, list.Friends, 1)
+	assert.Equal(t, "Bob", list.Friends[0].Nickname)
+	require.NotNil(t, list.Friends[0].Presence)
+	assert.Equal(t, social.PresenceOnline, list.Friends[0].Presence.Status)
+
+	sendMessage(router, alice, ws.TypeFriendRequest, map[string]string{"account_id": alice.AccountID})
+	assert.Equal(t, ws.TypeError, readResponse(t, aliceCh).Type)
+}
+
+func TestSocial_InviteToRoom(t *testing.T) {
+	router, rm := setupSocialTest(t)
+	alice, aliceCh := loginGuest(t, router, "c1", "Alice")
+	bob, bobCh := loginGuest(t, router, "c2", "Bob")
+	carol, carolCh := loginGuest(t, router, "c3", "Carol")
+
+	sendMessage(router, alice, ws.TypeFriendRequest, map[string]string{"account_id": bob.AccountID})
+	readResponse(t, aliceCh)
+	readResponse(t, bobCh)
+	sendMessage(router, bob, ws.TypeFriendRequest, map[string]string{"account_id": alice.AccountID})
+	readResponse(t, bobCh)
+	readResponse(t, aliceCh)
+
+	sendMessage(router, alice, ws.TypeCreateRoom, map[string]string{"nickname": "Alice", "visibility": "private"})
+	created := readResponse(t, aliceCh)
+	require.Equal(t, ws.TypeCreateRoom, created.Type)
+	var createResp createRoomResponse
+	require.NoError(t, json.Unmarshal(created.Data, &createResp))
+
+	// Only friends can be invited
+	sendMessage(router, alice, ws.TypeInviteToRoom, map[string]string{"account_id": carol.AccountID})
+	assert.Equal(t, ws.TypeError, readResponse(t, aliceCh).Type)
+
+	sendMessage(router, alice, ws.TypeInviteToRoom, map[string]string{"account_id": bob.AccountID})
+	assert.Equal(t, ws.TypeInviteToRoom, readResponse(t, aliceCh).Type)
+
+	pushed := readResponse(t, bobCh)
+	require.Equal(t, ws.TypeRoomInvite, pushed.Type)
+	var inv social.Invite
+	require.NoError(t, json.Unmarshal(pushed.Data, &inv))
+	assert.Equal(t, createResp.Code, inv.RoomCode)
+	assert.Equal(t, "Alice", inv.FromNickname)
+
+	// The invite belongs to Bob
+	sendMessage(router, carol, ws.TypeAcceptInvite, map[string]string{"invite_id": inv.ID, "nickname": "Carol"})
+	assert.Equal(t, ws.TypeError, readResponse(t, carolCh).Type)
+
+	// Private rooms are joinable through an invite
+	sendMessage(router, bob, ws.TypeAcceptInvite, map[string]string{"invite_id": inv.ID, "nickname": "Bob"})
+	assert.Equal(t, ws.TypeJoinRoom, readResponse(t, bobCh).Type)
+
+	r := rm.GetRoom(createResp.Code)
+	require.NotNil(t, r)
+	assert.Equal(t, 2, r.PlayerCount())
+
+	presence := router.social.Presence(bob.AccountID)
+	assert.Equal(t, social.PresenceLobby, presence.Status)
+	assert.Equal(t, createResp.Code, presence.RoomCode)
+
+	router.HandleDisconnect(bob)
+	assert.Equal(t, social.PresenceOffline, router.social.Presence(bob.AccountID).Status)
+}
+
+func TestSocial_InviteToReusedRoomCode(t *testing.T) {
+	router, rm := setupSocialTest(t)
+	bob, bobCh := loginGuest(t, router, "c2", "Bob")
+
+	// An invite to a removed room whose code now belongs to another room
+	r := rm.CreateRoom()
+	inv := router.social.invites.Add("acc-alice", "Alice", bob.AccountID, r.Code, "removed-room", time.Now())
+
+	sendMessage(router, bob, ws.TypeAcceptInvite, map[string]string{"invite_id": inv.ID, "nickname": "Bob"})
+	assert.Equal(t, "방을 찾을 수 없습니다", readErrorText(t, bobCh))
+	assert.Zero(t, r.PlayerCount())
+}
+
+func TestSocial_Disabled(t *testing.T) {
+	router, _ := setupLobbyTest(t)
+	client, ch := newTestClient("c1")
+	client.MarkAuthenticated()
+
+	sendMessage(router, client, ws.TypeListFriends, nil)
+	assert.Equal(t, ws.TypeError, readResponse(t, ch).Type)
+}
@@ -0,0 +1,146 @@
+package leaderboard
+
+import (
+	"errors"
+	"math"
+)
+
+// Metric is a column standings can be ranked by.
+type Metric string
+
+const (
+	MetricWins    Metric = "wins"
+	MetricRating  Metric = "rating"
+	MetricArrests Metric = "arrests"
+	MetricRescues Metric = "rescues"
+)
+
+// SeasonAllTime selects the all-time standings.
+const SeasonAllTime = "all"
+
+// Rating parameters (Elo)
+const (
+	InitialRating = 1000.0
+	ratingK       = 32.0
+)
+
+// Paging limits
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var (
+	ErrUnknownMetric = errors.New("unknown leaderboard metric")
+	ErrNoSeason      = errors.New("no active season")
+	ErrSeasonEnded   = errors.New("season already ended")
+)
+
+// Entry is one account's standing on a leaderboard.
+type Entry struct {
+	Rank      int     `json:"rank"`
+	AccountID string  `json:"account_id"`
+	Nickname  string  `json:"nickname"`
+	Games     int     `json:"games"`
+	Wins      int     `json:"wins"`
+	Arrests   int     `json:"arrests"`
+	Rescues   int     `json:"rescues"`
+	Rating    float64 `json:"rating"`
+}
+
+// Query selects a page of standings.
+type Query struct {
+	Season string
+	Metric Metric
+	Offset int
+	Limit  int
+}
+
+// Normalize validates the metric and clamps paging values.
+func (q Query) Normalize() (Query, error) {
+	if q.Metric == "" {
+		q.Metric = MetricRating
+	}
+	switch q.Metric {
+	case MetricWins, MetricRating, MetricArrests, MetricRescues:
+	default:
+		return q, ErrUnknownMetric
+	}
+	if q.Season == "" {
+		q.Season = SeasonAllTime
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		q.Limit = MaxLimit
+	}
+	return q, nil
+}
+
+// Page is a leaderboard response. Self is the requester's own standing, if ranked.
+type Page struct {
+	Season  string  `json:"season"`
+	Metric  Metric  `json:"metric"`
+	Total   int     `json:"total"`
+	Offset  int     `json:"offset"`
+	Entries []Entry `json:"entries"`
+	Self    *Entry  `json:"self,omitempty"`
+}
+
+// Result is one account's contribution from a finished match. Each board
+// keeps its own rating, so the all-time and season rating changes differ.
+type Result struct {
+	AccountID         string
+	Won               bool
+	Arrests           int
+	Rescues           int
+	RatingDelta       float64 // all-time rating change
+	SeasonRatingDelta float64 // season rating change
+}
+
+// Participant is an input to team rating calculation.
+type Participant struct {
+	AccountID string
+	Team      int // any value shared by teammates
+	Rating    float64
+	Won       bool
+}
+
+// RatingDeltas computes Elo rating changes for a team match.
+// Each player is rated against the average rating of the opposing team.
+func RatingDeltas(participants []Participant) map[string]float64 {
+	sum := make(map[int]float64)
+	count := make(map[int]int)
+	for _, p := range participants {
+		sum[p.Team] += p.Rating
+		count[p.Team]++
+	}
+
+	deltas := make(map[string]float64, len(participants))
+	for _, p := range participants {
+		var oppSum float64
+		oppCount := 0
+		for team, s := range sum {
+			if team != p.Team {
+				oppSum += s
+				oppCount += count[team]
+			}
+		}
+		if oppCount == 0 {
+			deltas[p.AccountID] = 0
+			continue
+		}
+		opponent := oppSum / float64(oppCount)
+		expected := 1 / (1 + math.Pow(10, (opponent-p.Rating)/400))
+		actual := 0.0
+		if p.Won {
+			actual = 1
+		}
+		deltas[p.AccountID] = math.Round(ratingK*(actual-expected)*10) / 10
+	}
+	return deltas
+}
@@ -0,0 +1,47 @@
+package leaderboard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryNormalize(t *testing.T) {
+	q, err := Query{}.Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, MetricRating, q.Metric)
+	assert.Equal(t, SeasonAllTime, q.Season)
+	assert.Equal(t, DefaultLimit, q.Limit)
+
+	q, err = Query{Metric: MetricWins, Offset: -5, Limit: 1000}.Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, 0, q.Offset)
+	assert.Equal(t, MaxLimit, q.Limit)
+
+	_, err = Query{Metric: "kills"}.Normalize()
+	assert.ErrorIs(t, err, ErrUnknownMetric)
+}
+
+func TestRatingDeltas_EvenMatch(t *testing.T) {
+	deltas := RatingDeltas([]Participant{
+		{AccountID: "a", Team: 1, Rating: 1000, Won: true},
+		{AccountID: "b", Team: 2, Rating: 1000, Won: false},
+	})
+	assert.Equal(t, 16.0, deltas["a"])
+	assert.Equal(t, -16.0, deltas["b"])
+}
+
+func TestRatingDeltas_UpsetGainsMore(t *testing.T) {
+	deltas := RatingDeltas([]Participant{
+		{AccountID: "weak", Team: 1, Rating: 900, Won: true},
+		{AccountID: "strong", Team: 2, Rating: 1100, Won: false},
+	})
+	assert.Greater(t, deltas["weak"], 16.0)
+	assert.Less(t, deltas["strong"], -16.0)
+}
+
+func TestRatingDeltas_NoOpponents(t *testing.T) {
+	deltas := RatingDeltas([]Participant{{AccountID: "solo", Team: 1, Rating: 1000, Won: true}})
+	assert.Equal(t, 0.0, deltas["solo"])
+}
@@ -0,0 +1,55 @@
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFlagNotFound = errors.New("flag not found")
+	ErrFlagClosed   = errors.New("flag already reviewed")
+)
+
+// ActionReviewFlag is the audit action for closing a flag.
+const ActionReviewFlag = "review_flag"
+
+// Flag puts an account in the review queue on the server's own initiative,
+// such as the anti-cheat escalating past its flag score. Flags are reviewed
+// like reports.
+type Flag struct {
+	ID         string          `json:"id"`
+	AccountID  string          `json:"account_id"`
+	Source     string          `json:"source"` // what raised the flag, e.g. "anticheat"
+	Reason     string          `json:"reason"`
+	Evidence   json.RawMessage `json:"evidence,omitempty"`
+	Status     ReportStatus    `json:"status"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ReviewedBy string          `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time      `json:"reviewed_at,omitempty"`
+	ReviewNote string          `json:"review_note,omitempty"`
+}
+
+// NewFlag creates an open flag with evidence marshaled to JSON.
+func NewFlag(accountID, source, reason string, evidence any) *Flag {
+	data, _ := json.Marshal(evidence)
+	return &Flag{
+		ID:        uuid.New().String(),
+		AccountID: accountID,
+		Source:    source,
+		Reason:    reason,
+		Evidence:  data,
+		Status:    StatusOpen,
+		CreatedAt: time.Now(),
+	}
+}
+
+// FlagQuery filters the flag queue, oldest first.
+type FlagQuery struct {
+	Status    ReportStatus // empty for all statuses
+	AccountID string       // empty for all accounts
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,123 @@
+// Package moderation defines account bans, player reports and the audit
+// trail of moderator actions.
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrBanNotFound   = errors.New("ban not found")
+	ErrAlreadyLifted = errors.New("ban already lifted")
+)
+
+// Audit actions.
+const (
+	ActionBan   = "ban"
+	ActionUnban = "unban"
+
+	// Recorded when a guest is merged into a linked account, whose
+	// moderation history it then carries
+	ActionMergeAccounts = "merge_accounts"
+
+	// Taken automatically by the anti-cheat
+	ActionAntiCheatKick = "anticheat_kick"
+	ActionAntiCheatFlag = "anticheat_flag"
+	// Every scored violation, including those that only rubber-banded the player
+	ActionAntiCheatViolation = "anticheat_violation"
+)
+
+// Ban keeps an account off the server until it expires or is lifted.
+type Ban struct {
+	ID        string     `json:"id"`
+	AccountID string     `json:"account_id"`
+	Reason    string     `json:"reason"`
+	IssuedBy  string     `json:"issued_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // nil when permanent
+	Permanent bool       `json:"permanent"`
+	LiftedAt  *time.Time `json:"lifted_at,omitempty"`
+	LiftedBy  string     `json:"lifted_by,omitempty"`
+}
+
+// NewBan creates a ban for the given duration. A zero duration is permanent.
+func NewBan(accountID, reason, issuedBy string, duration time.Duration) *Ban {
+	now := time.Now()
+	b := &Ban{
+		ID:        uuid.New().String(),
+		AccountID: accountID,
+		Reason:    reason,
+		IssuedBy:  issuedBy,
+		CreatedAt: now,
+		Permanent: duration <= 0,
+	}
+	if !b.Permanent {
+		exp := now.Add(duration)
+		b.ExpiresAt = &exp
+	}
+	return b
+}
+
+// Active reports whether the ban is in force at now.
+func (b *Ban) Active(now time.Time) bool {
+	if b.LiftedAt != nil {
+		return false
+	}
+	return b.Permanent || (b.ExpiresAt != nil && now.Before(*b.ExpiresAt))
+}
+
+// Listing page sizes.
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// NormalizeLimit applies the default and maximum page size.
+func NormalizeLimit(n int) int {
+	if n <= 0 {
+		return DefaultLimit
+	}
+	return min(n, MaxLimit)
+}
+
+// BanQuery filters a ban listing.
+type BanQuery struct {
+	AccountID  string // empty for all accounts
+	ActiveOnly bool
+	Limit      int
+	Offset     int
+}
+
+// AuditEntry records one moderator action.
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	TargetID  string          `json:"target_id"`
+	Detail    json.RawMessage `json:"detail,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditQuery filters the audit trail, newest first.
+type AuditQuery struct {
+	Actor    string // empty for all actors
+	TargetID string // empty for all targets
+	Limit    int
+	Offset   int
+}
+
+// NewAuditEntry creates an audit entry with detail marshaled to JSON.
+func NewAuditEntry(actor, action, targetID string, detail any) AuditEntry {
+	data, _ := json.Marshal(detail)
+	return AuditEntry{
+		Actor:     actor,
+		Action:    action,
+		TargetID:  targetID,
+		Detail:    data,
+		CreatedAt: time.Now(),
+	}
+}
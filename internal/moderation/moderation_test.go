@@ -0,0 +1,51 @@
+package moderation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBan_Active(t *testing.T) {
+	now := time.Now()
+
+	temp := NewBan("acc-1", "speed hack", "admin-1", time.Hour)
+	require.NotNil(t, temp.ExpiresAt)
+	assert.False(t, temp.Permanent)
+	assert.True(t, temp.Active(now))
+	assert.False(t, temp.Active(now.Add(2*time.Hour)))
+
+	perm := NewBan("acc-1", "speed hack", "admin-1", 0)
+	assert.True(t, perm.Permanent)
+	assert.Nil(t, perm.ExpiresAt)
+	assert.True(t, perm.Active(now.Add(24*365*time.Hour)))
+
+	lifted := now
+	perm.LiftedAt = &lifted
+	assert.False(t, perm.Active(now))
+}
+
+func TestNewAuditEntry(t *testing.T) {
+	e := NewAuditEntry("admin-1", ActionBan, "acc-1", map[string]string{"reason": "speed hack"})
+	assert.JSONEq(t, `{"reason":"speed hack"}`, string(e.Detail))
+}
+
+func TestReportCategoryAndStatus(t *testing.T) {
+	assert.True(t, CategoryAFK.Valid())
+	assert.False(t, Category("rude").Valid())
+	assert.False(t, StatusOpen.Closed())
+	assert.True(t, StatusDismissed.Closed())
+
+	r := NewReport("acc-1", "acc-2", CategoryCheating, "", "ABCD", "match-1", map[string]string{"state": "ended"})
+	assert.Equal(t, StatusOpen, r.Status)
+	assert.JSONEq(t, `{"state":"ended"}`, string(r.Evidence))
+}
+
+func TestNewFlag(t *testing.T) {
+	f := NewFlag("acc-1", "anticheat", "speed", map[string]float64{"score": 95})
+	assert.Equal(t, StatusOpen, f.Status)
+	assert.NotEmpty(t, f.ID)
+	assert.JSONEq(t, `{"score":95}`, string(f.Evidence))
+}
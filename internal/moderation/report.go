@@ -0,0 +1,96 @@
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDuplicateReport = errors.New("player already reported for this match")
+	ErrReportNotFound  = errors.New("report not found")
+	ErrReportClosed    = errors.New("report already reviewed")
+)
+
+// Category is the reason a player is reported.
+type Category string
+
+const (
+	CategoryCheating        Category = "cheating"
+	CategoryAbusiveNickname Category = "abusive_nickname"
+	CategoryAFK             Category = "afk"
+)
+
+// Valid reports whether c is a known category.
+func (c Category) Valid() bool {
+	switch c {
+	case CategoryCheating, CategoryAbusiveNickname, CategoryAFK:
+		return true
+	}
+	return false
+}
+
+// ReportStatus tracks a report through review.
+type ReportStatus string
+
+const (
+	StatusOpen      ReportStatus = "open"
+	StatusActioned  ReportStatus = "actioned"  // a moderator acted on it
+	StatusDismissed ReportStatus = "dismissed" // reviewed, no action taken
+)
+
+// Closed reports whether s is a final review outcome.
+func (s ReportStatus) Closed() bool {
+	return s == StatusActioned || s == StatusDismissed
+}
+
+// ActionReviewReport is the audit action for closing a report.
+const ActionReviewReport = "review_report"
+
+// MaxCommentLength bounds the reporter's free-text comment, in characters.
+const MaxCommentLength = 200
+
+// Report is one player's complaint about another in a match.
+type Report struct {
+	ID             string          `json:"id"`
+	ReporterID     string          `json:"reporter_id"`
+	TargetID       string          `json:"target_id"`
+	TargetNickname string          `json:"target_nickname"`
+	Category       Category        `json:"category"`
+	Comment        string          `json:"comment,omitempty"`
+	RoomCode       string          `json:"room_code"`
+	MatchID        string          `json:"match_id"`
+	Evidence       json.RawMessage `json:"evidence,omitempty"`
+	Status         ReportStatus    `json:"status"`
+	CreatedAt      time.Time       `json:"created_at"`
+	ReviewedBy     string          `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time      `json:"reviewed_at,omitempty"`
+	ReviewNote     string          `json:"review_note,omitempty"`
+}
+
+// NewReport creates an open report with evidence marshaled to JSON.
+func NewReport(reporterID, targetID string, category Category, comment, roomCode, matchID string, evidence any) *Report {
+	data, _ := json.Marshal(evidence)
+	return &Report{
+		ID:         uuid.New().String(),
+		ReporterID: reporterID,
+		TargetID:   targetID,
+		Category:   category,
+		Comment:    comment,
+		RoomCode:   roomCode,
+		MatchID:    matchID,
+		Evidence:   data,
+		Status:     StatusOpen,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// ReportQuery filters the reviewer queue, oldest first.
+type ReportQuery struct {
+	Status   ReportStatus // empty for all statuses
+	TargetID string       // empty for all targets
+	Limit    int
+	Offset   int
+}
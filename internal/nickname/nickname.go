@@ -0,0 +1,201 @@
+// Package nickname validates player nicknames.
+package nickname
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Nickname length limits, in characters.
+const (
+	MinLength = 2
+	MaxLength = 12
+)
+
+//go:embed banned_words.txt
+var defaultBannedWords []byte
+
+// reservedWords impersonate staff or the system and are never allowed.
+var reservedWords = []string{
+	"admin", "moderator", "gamemaster", "system",
+	"관리자", "운영자", "운영진", "개발자", "시스템",
+}
+
+// ErrInvalid is matched by every policy violation.
+var ErrInvalid = errors.New("invalid nickname")
+
+var (
+	ErrEmpty      = fmt.Errorf("%w: empty", ErrInvalid)
+	ErrLength     = fmt.Errorf("%w: must be %d-%d characters", ErrInvalid, MinLength, MaxLength)
+	ErrCharacters = fmt.Errorf("%w: only Hangul, letters, digits and _ are allowed", ErrInvalid)
+	ErrReserved   = fmt.Errorf("%w: reserved word", ErrInvalid)
+	ErrBanned     = fmt.Errorf("%w: banned word", ErrInvalid)
+)
+
+// Policy decides which nicknames are allowed.
+type Policy struct {
+	reserved []form
+	banned   []form
+}
+
+// NewPolicy creates a policy with the built-in banned words plus extra ones.
+func NewPolicy(extraBanned []string) *Policy {
+	p := &Policy{}
+	for _, w := range reservedWords {
+		p.reserved = append(p.reserved, decompose(w, false))
+	}
+	for _, w := range append(parseWords(defaultBannedWords), extraBanned...) {
+		if f := decompose(w, false); len(f.jamo) > 0 {
+			p.banned = append(p.banned, f)
+		}
+	}
+	return p
+}
+
+// LoadWords reads a banned-word list with one word per line.
+// Blank lines and lines starting with # are ignored.
+func LoadWords(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseWords(data), nil
+}
+
+func parseWords(data []byte) []string {
+	var words []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words
+}
+
+// Validate checks a nickname against the policy and returns it trimmed.
+func (p *Policy) Validate(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrEmpty
+	}
+	if n := utf8.RuneCountInString(name); n < MinLength || n > MaxLength {
+		return "", ErrLength
+	}
+	for _, r := range name {
+		if !allowed(r) {
+			return "", ErrCharacters
+		}
+	}
+
+	// Digits are tried both as look-alike letters and as filler.
+	for _, leet := range []bool{true, false} {
+		f := decompose(name, leet)
+		if f.contains(p.reserved) {
+			return "", ErrReserved
+		}
+		if f.contains(p.banned) {
+			return "", ErrBanned
+		}
+	}
+	return name, nil
+}
+
+// Generate returns a placeholder nickname for accounts created without one.
+func Generate() string {
+	return fmt.Sprintf("플레이어%04d", rand.IntN(10000))
+}
+
+func allowed(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+		return true
+	case isSyllable(r), r >= 0x3131 && r <= 0x3163: // Hangul syllables and compatibility jamo
+		return true
+	}
+	return false
+}
+
+// form is a normalized string: lowercase letters with Hangul syllables split
+// into compatibility jamo. bounds marks the indexes where a character starts,
+// so a match cannot begin or end in the middle of a syllable. words marks the
+// edges of Latin words, which end at anything but a Latin letter.
+type form struct {
+	jamo   []rune
+	bounds map[int]bool
+	words  map[int]bool
+	latin  bool // only Latin letters, matched as whole words
+}
+
+var (
+	choseong  = []rune("ㄱㄲㄴㄷㄸㄹㅁㅂㅃㅅㅆㅇㅈㅉㅊㅋㅌㅍㅎ")
+	jungseong = []rune("ㅏㅐㅑㅒㅓㅔㅕㅖㅗㅘㅙㅚㅛㅜㅝㅞㅟㅠㅡㅢㅣ")
+	jongseong = []rune(" ㄱㄲㄳㄴㄵㄶㄷㄹㄺㄻㄼㄽㄾㄿㅀㅁㅂㅄㅅㅆㅇㅈㅊㅋㅌㅍㅎ") // index 0: no final consonant
+)
+
+var leetLetters = map[rune]rune{'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b'}
+
+func isSyllable(r rune) bool { return r >= 0xAC00 && r <= 0xD7A3 }
+
+// decompose normalizes s, dropping anything that is not a letter so spacing
+// and symbols cannot split a word. With leet set, digits become the letters
+// they resemble instead of being dropped.
+func decompose(s string, leet bool) form {
+	f := form{bounds: map[int]bool{0: true}, words: map[int]bool{0: true}, latin: true}
+	for _, r := range strings.ToLower(s) {
+		latin := r >= 'a' && r <= 'z' || leet && leetLetters[r] != 0
+		if !latin {
+			f.words[len(f.jamo)] = true
+		}
+		switch {
+		case isSyllable(r):
+			idx := r - 0xAC00
+			f.jamo = append(f.jamo, choseong[idx/588], jungseong[idx%588/28])
+			if t := idx % 28; t != 0 {
+				f.jamo = append(f.jamo, jongseong[t])
+			}
+		case leet && leetLetters[r] != 0:
+			f.jamo = append(f.jamo, leetLetters[r])
+		case unicode.IsLetter(r):
+			f.jamo = append(f.jamo, r)
+		default:
+			continue
+		}
+		f.bounds[len(f.jamo)] = true
+		if !latin {
+			f.words[len(f.jamo)] = true
+			f.latin = false
+		}
+	}
+	f.words[len(f.jamo)] = true
+	return f
+}
+
+// contains reports whether any word occurs in f aligned to character bounds.
+// Latin words must also stand alone, so "sex" does not match "Essex";
+// Hangul words match anywhere.
+func (f form) contains(words []form) bool {
+	for _, w := range words {
+		edges := f.bounds
+		if w.latin {
+			edges = f.words
+		}
+		for i := 0; i+len(w.jamo) <= len(f.jamo); i++ {
+			if edges[i] && edges[i+len(w.jamo)] && slices.Equal(f.jamo[i:i+len(w.jamo)], w.jamo) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package nickname
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_Allowed(t *testing.T) {
+	p := NewPolicy(nil)
+	for _, name := range []string{"경찰왕", "thief_99", "ㅋㅋ도둑", "조정", "옷방", "AB"} {
+		got, err := p.Validate(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, name, got)
+	}
+
+	got, err := p.Validate("  도둑  ")
+	require.NoError(t, err)
+	assert.Equal(t, "도둑", got)
+}
+
+func TestValidate_Rejected(t *testing.T) {
+	p := NewPolicy(nil)
+	cases := map[string]error{
+		"":    ErrEmpty,
+		"   ": ErrEmpty,
+		"가":   ErrLength,
+		"가나다라마바사아자차카타파": ErrLength,
+		"경찰 왕":   ErrCharacters,
+		"도둑!":    ErrCharacters,
+		"😀😀":     ErrCharacters,
+		"Admin1": ErrReserved,
+		"진짜운영자":  ErrReserved,
+		"씨발":     ErrBanned,
+		"씨1발":    ErrBanned,
+		"ㅆㅣ발":    ErrBanned,
+		"시바ㄹ":    ErrBanned,
+		"ㅅㅂ_ㅅㅂ":  ErrBanned,
+		"sh1t":   ErrBanned,
+		"FUCK":   ErrBanned,
+	}
+	for name, want := range cases {
+		_, err := p.Validate(name)
+		assert.ErrorIs(t, err, want, name)
+		assert.ErrorIs(t, err, ErrInvalid, name)
+	}
+}
+
+func TestValidate_LatinWordsStandAlone(t *testing.T) {
+	p := NewPolicy(nil)
+	// The Scunthorpe problem: banned Latin words inside ordinary ones
+	for _, name := range []string{"Essex", "Sussex", "Middlesex", "Scunthorpe", "badminton", "Shitake"} {
+		_, err := p.Validate(name)
+		assert.NoError(t, err, name)
+	}
+	for _, name := range []string{"sex", "sex_king", "sex1", "s_e_x", "왕sex", "fuck_you", "f_u_c_k"} {
+		_, err := p.Validate(name)
+		assert.ErrorIs(t, err, ErrBanned, name)
+	}
+	_, err := p.Validate("admin_kim")
+	assert.ErrorIs(t, err, ErrReserved)
+}
+
+func TestNewPolicy_ExtraWords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "banned.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# comment\n\n바보\n"), 0o644))
+	words, err := LoadWords(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"바보"}, words)
+
+	_, err = NewPolicy(words).Validate("바_보야")
+	assert.ErrorIs(t, err, ErrBanned)
+	_, err = NewPolicy(nil).Validate("바보야")
+	assert.NoError(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	name, err := NewPolicy(nil).Validate(Generate())
+	require.NoError(t, err)
+	assert.Contains(t, name, "플레이어")
+}
@@ -0,0 +1,223 @@
+package party
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// MaxSize is the largest party, leaving room in a match for other players.
+const MaxSize = 4
+
+var (
+	ErrAlreadyInParty = errors.New("already in a party")
+	ErrNotInParty     = errors.New("not in a party")
+	ErrNotFound       = errors.New("party not found")
+	ErrNotLeader      = errors.New("only the party leader can do that")
+	ErrNotInvited     = errors.New("not invited to this party")
+	ErrFull           = errors.New("party is full")
+	ErrNotMember      = errors.New("account is not a party member")
+)
+
+// Member is one account in a party.
+type Member struct {
+	AccountID string `json:"account_id"`
+	Nickname  string `json:"nickname"`
+}
+
+// Party is a group of accounts that joins rooms together.
+// Parties exist independently of rooms. The leader is always Members[0].
+type Party struct {
+	ID      string   `json:"party_id"`
+	Members []Member `json:"members"`
+
+	invited map[string]bool
+}
+
+// Leader returns the account ID of the party leader.
+func (p *Party) Leader() string {
+	return p.Members[0].AccountID
+}
+
+// MemberIDs returns the account IDs of all members, leader first.
+func (p *Party) MemberIDs() []string {
+	ids := make([]string, len(p.Members))
+	for i, m := range p.Members {
+		ids[i] = m.AccountID
+	}
+	return ids
+}
+
+func (p *Party) indexOf(accountID string) int {
+	for i, m := range p.Members {
+		if m.AccountID == accountID {
+			return i
+		}
+	}
+	return -1
+}
+
+// Manager tracks all parties. Methods return snapshots of the parties they
+// change so callers can notify members without holding the manager's lock.
+type Manager struct {
+	parties   map[string]*Party // party ID -> party
+	byAccount map[string]string // account ID -> party ID
+	mu        sync.Mutex
+}
+
+// NewManager creates an empty party manager.
+func NewManager() *Manager {
+	return &Manager{
+		parties:   make(map[string]*Party),
+		byAccount: make(map[string]string),
+	}
+}
+
+// Create starts a party led by the given account.
+func (m *Manager) Create(accountID, nickname string) (Party, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.byAccount[accountID]; ok {
+		return Party{}, ErrAlreadyInParty
+	}
+	p := &Party{
+		ID:      uuid.New().String(),
+		Members: []Member{{AccountID: accountID, Nickname: nickname}},
+		invited: make(map[string]bool),
+	}
+	m.parties[p.ID] = p
+	m.byAccount[accountID] = p.ID
+	return p.snapshot(), nil
+}
+
+// Invite lets target join the leader's party.
+func (m *Manager) Invite(leaderID, targetID string) (Party, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.led(leaderID)
+	if err != nil {
+		return Party{}, err
+	}
+	if p.indexOf(targetID) >= 0 {
+		return Party{}, ErrAlreadyInParty
+	}
+	if len(p.Members) >= MaxSize {
+		return Party{}, ErrFull
+	}
+	p.invited[targetID] = true
+	return p.snapshot(), nil
+}
+
+// Join adds an invited account to a party.
+func (m *Manager) Join(partyID, accountID, nickname string) (Party, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.parties[partyID]
+	if !ok {
+		return Party{}, ErrNotFound
+	}
+	if _, ok := m.byAccount[accountID]; ok {
+		return Party{}, ErrAlreadyInParty
+	}
+	if !p.invited[accountID] {
+		return Party{}, ErrNotInvited
+	}
+	if len(p.Members) >= MaxSize {
+		return Party{}, ErrFull
+	}
+	delete(p.invited, accountID)
+	p.Members = append(p.Members, Member{AccountID: accountID, Nickname: nickname})
+	m.byAccount[accountID] = p.ID
+	return p.snapshot(), nil
+}
+
+// Leave removes an account from its party. Leadership passes to the next
+// member; the party is disbanded when the last member leaves, in which case
+// the returned party has no members.
+func (m *Manager) Leave(accountID string) (Party, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, ok := m.byAccount[accountID]
+	if !ok {
+		return Party{}, ErrNotInParty
+	}
+	return m.remove(m.parties[id], accountID), nil
+}
+
+// Kick removes a member from the leader's party.
+func (m *Manager) Kick(leaderID, targetID string) (Party, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.led(leaderID)
+	if err != nil {
+		return Party{}, err
+	}
+	if targetID == leaderID || p.indexOf(targetID) < 0 {
+		return Party{}, ErrNotMember
+	}
+	return m.remove(p, targetID), nil
+}
+
+// Rename updates a member's nickname and returns the party it belongs to.
+func (m *Manager) Rename(accountID, nickname string) (Party, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, ok := m.byAccount[accountID]
+	if !ok {
+		return Party{}, false
+	}
+	p := m.parties[id]
+	p.Members[p.indexOf(accountID)].Nickname = nickname
+	return p.snapshot(), true
+}
+
+// Get returns the party an account belongs to.
+func (m *Manager) Get(accountID string) (Party, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, ok := m.byAccount[accountID]
+	if !ok {
+		return Party{}, false
+	}
+	return m.parties[id].snapshot(), true
+}
+
+// led returns the party led by accountID. Caller must hold m.mu.
+func (m *Manager) led(accountID string) (*Party, error) {
+	id, ok := m.byAccount[accountID]
+	if !ok {
+		return nil, ErrNotInParty
+	}
+	p := m.parties[id]
+	if p.Leader() != accountID {
+		return nil, ErrNotLeader
+	}
+	return p, nil
+}
+
+// remove drops a member and disbands an empty party. Caller must hold m.mu.
+func (m *Manager) remove(p *Party, accountID string) Party {
+	i := p.indexOf(accountID)
+	p.Members = append(p.Members[:i], p.Members[i+1:]...)
+	delete(m.byAccount, accountID)
+	if len(p.Members) == 0 {
+		delete(m.parties, p.ID)
+	}
+	return p.snapshot()
+}
+
+// snapshot copies the party for use outside the manager's lock.
+func (p *Party) snapshot() Party {
+	return Party{
+		ID:      p.ID,
+		Members: append([]Member(nil), p.Members...),
+	}
+}
@@ -0,0 +1,97 @@
+package party
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_InviteAndJoin(t *testing.T) {
+	m := NewManager()
+	p, err := m.Create("a", "Alice")
+	require.NoError(t, err)
+	assert.Equal(t, "a", p.Leader())
+
+	_, err = m.Join(p.ID, "b", "Bob")
+	assert.ErrorIs(t, err, ErrNotInvited)
+
+	_, err = m.Invite("b", "c")
+	assert.ErrorIs(t, err, ErrNotInParty)
+
+	_, err = m.Invite("a", "b")
+	require.NoError(t, err)
+	p, err = m.Join(p.ID, "b", "Bob")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, p.MemberIDs())
+
+	_, err = m.Invite("b", "c")
+	assert.ErrorIs(t, err, ErrNotLeader)
+
+	_, err = m.Create("b", "Bob")
+	assert.ErrorIs(t, err, ErrAlreadyInParty)
+}
+
+func TestManager_Full(t *testing.T) {
+	m := NewManager()
+	p, _ := m.Create("m0", "")
+	for i := 1; i < MaxSize; i++ {
+		id := string(rune('0' + i))
+		_, err := m.Invite("m0", id)
+		require.NoError(t, err)
+		_, err = m.Join(p.ID, id, "")
+		require.NoError(t, err)
+	}
+	_, err := m.Invite("m0", "late")
+	assert.ErrorIs(t, err, ErrFull)
+}
+
+func TestManager_LeavePassesLeadership(t *testing.T) {
+	m := NewManager()
+	p, _ := m.Create("a", "Alice")
+	m.Invite("a", "b")
+	m.Join(p.ID, "b", "Bob")
+
+	p, err := m.Leave("a")
+	require.NoError(t, err)
+	assert.Equal(t, "b", p.Leader())
+
+	_, ok := m.Get("a")
+	assert.False(t, ok)
+
+	p, err = m.Leave("b")
+	require.NoError(t, err)
+	assert.Empty(t, p.Members, "last member disbands the party")
+
+	_, err = m.Join(p.ID, "c", "Carol")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestManager_Kick(t *testing.T) {
+	m := NewManager()
+	p, _ := m.Create("a", "Alice")
+	m.Invite("a", "b")
+	m.Join(p.ID, "b", "Bob")
+
+	_, err := m.Kick("b", "a")
+	assert.ErrorIs(t, err, ErrNotLeader)
+	_, err = m.Kick("a", "a")
+	assert.ErrorIs(t, err, ErrNotMember)
+
+	p, err = m.Kick("a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, p.MemberIDs())
+	_, ok := m.Get("b")
+	assert.False(t, ok)
+}
+
+func TestManager_Rename(t *testing.T) {
+	m := NewManager()
+	_, ok := m.Rename("a", "Alice")
+	assert.False(t, ok)
+
+	m.Create("a", "Alice")
+	p, ok := m.Rename("a", "Alicia")
+	require.True(t, ok)
+	assert.Equal(t, "Alicia", p.Members[0].Nickname)
+}
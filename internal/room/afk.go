@@ -0,0 +1,210 @@
+package room
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// AFKAction is what happens to a player who stays idle through a match.
+type AFKAction string
+
+const (
+	AFKKick AFKAction = "kick" // remove the player; the match may be forfeited
+	AFKBot  AFKAction = "bot"  // hand the player to a server bot until the match ends
+)
+
+// AFKActionByName returns the AFK action with the given name.
+func AFKActionByName(name string) (AFKAction, bool) {
+	switch a := AFKAction(name); a {
+	case AFKKick, AFKBot:
+		return a, true
+	}
+	return "", false
+}
+
+// AFKPolicy sets how long a player may go without input before being warned
+// and then removed. Lobby limits apply only to players who are not ready.
+// A zero timeout disables detection for that phase.
+type AFKPolicy struct {
+	LobbyWarn    time.Duration
+	LobbyTimeout time.Duration
+	GameWarn     time.Duration
+	GameTimeout  time.Duration
+}
+
+// AFKRemoval describes a player taken out of a room for inactivity.
+type AFKRemoval struct {
+	PlayerID string
+	Client   *ws.Client
+	Action   AFKAction
+}
+
+type afkWarningMessage struct {
+	SecondsLeft int       `json:"seconds_left"`
+	Action      AFKAction `json:"action"`
+}
+
+type afkRemovedMessage struct {
+	Action AFKAction `json:"action"`
+}
+
+// SetAFKAction changes what happens to players who go AFK in a match.
+// Returns false unless the room is waiting.
+func (r *Room) SetAFKAction(action AFKAction) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.State != game.StateWaiting {
+		return false
+	}
+	r.AFKAction = action
+	return true
+}
+
+// GetAFKAction returns the room's in-match AFK action.
+func (r *Room) GetAFKAction() AFKAction {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.AFKAction
+}
+
+// TouchPlayer records input from a player, resetting their AFK timer.
+func (r *Room) TouchPlayer(playerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.Players[playerID]; ok {
+		p.Touch(time.Now())
+	}
+}
+
+// touchAll resets every player's AFK timer, e.g. when a new phase begins.
+// Caller must hold r.mu.
+func (r *Room) touchAll() {
+	now := time.Now()
+	for _, p := range r.Players {
+		p.Touch(now)
+	}
+}
+
+// humanCount returns the number of players not controlled by a bot. Caller must hold r.mu.
+func (r *Room) humanCount() int {
+	n := 0
+	for _, p := range r.Players {
+		if !p.Bot {
+			n++
+		}
+	}
+	return n
+}
+
+// CheckAFK warns idle players and removes those idle past the timeout.
+// In the lobby AFK players are always kicked; during a match the room's
+// AFKAction applies, except that the last human is kicked rather than
+// leaving a room of bots. Returns the players taken out of the room.
+func (r *Room) CheckAFK(now time.Time, policy AFKPolicy) []AFKRemoval {
+	type warning struct {
+		client      *ws.Client
+		secondsLeft int
+		action      AFKAction
+	}
+	var warnings []warning
+	var removals []AFKRemoval
+
+	r.mu.Lock()
+	warn, timeout, action := policy.LobbyWarn, policy.LobbyTimeout, AFKKick
+	switch r.State {
+	case game.StateWaiting:
+	case game.StatePlaying:
+		warn, timeout, action = policy.GameWarn, policy.GameTimeout, r.AFKAction
+	default:
+		// Countdowns and intermissions are too short to go AFK in
+		r.mu.Unlock()
+		return nil
+	}
+	if timeout <= 0 {
+		r.mu.Unlock()
+		return nil
+	}
+
+	for id, p := range r.Players {
+		if p.Bot || (r.State == game.StateWaiting && p.Ready) {
+			continue
+		}
+		idle := now.Sub(p.LastInputTime)
+		switch {
+		case idle >= timeout:
+			removals = append(removals, AFKRemoval{PlayerID: id, Client: r.clients[id], Action: action})
+		case idle >= warn && !p.AFKWarned:
+			p.AFKWarned = true
+			warnings = append(warnings, warning{
+				client:      r.clients[id],
+				secondsLeft: int((timeout - idle).Round(time.Second).Seconds()),
+				action:      action,
+			})
+		}
+	}
+
+	// Hand players to bots while another human remains; everyone else is kicked below
+	for i := range removals {
+		rm := &removals[i]
+		if rm.Action != AFKBot {
+			continue
+		}
+		if r.humanCount() <= 1 {
+			rm.Action = AFKKick
+			continue
+		}
+		r.convertToBot(rm.PlayerID)
+	}
+	r.mu.Unlock()
+
+	for _, w := range warnings {
+		if w.client == nil {
+			continue
+		}
+		msg, _ := ws.NewMessage(ws.TypeAFKWarning, afkWarningMessage{SecondsLeft: w.secondsLeft, Action: w.action})
+		w.client.SendMessage(msg)
+	}
+	for _, rm := range removals {
+		if rm.Client != nil {
+			msg, _ := ws.NewMessage(ws.TypeAFKRemoved, afkRemovedMessage{Action: rm.Action})
+			rm.Client.SendMessage(msg)
+		}
+		if rm.Action == AFKKick {
+			r.RemovePlayer(rm.PlayerID)
+		}
+		slog.Info("afk player removed", "room", r.Code, "player", rm.PlayerID, "action", rm.Action)
+	}
+	return removals
+}
+
+// convertToBot detaches a player's client and lets the server play for them.
+// The owner is recorded as a leaver. Caller must hold r.mu.
+func (r *Room) convertToBot(playerID string) {
+	p, ok := r.Players[playerID]
+	if !ok {
+		return
+	}
+	r.recordLeaver(p)
+	p.Bot = true
+	p.Ready = false
+	delete(r.clients, playerID)
+	delete(r.loading, playerID)
+
+	if r.HostID == playerID {
+		r.transferHost()
+	}
+}
+
+// removeBots drops every bot-controlled player. Caller must hold r.mu.
+func (r *Room) removeBots() {
+	for id, p := range r.Players {
+		if p.Bot {
+			delete(r.Players, id)
+			delete(r.lobbyRoles, id)
+		}
+	}
+}
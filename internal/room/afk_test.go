@@ -0,0 +1,172 @@
+package room
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+var testAFKPolicy = AFKPolicy{
+	LobbyWarn:    90 * time.Second,
+	LobbyTimeout: 120 * time.Second,
+	GameWarn:     20 * time.Second,
+	GameTimeout:  30 * time.Second,
+}
+
+func TestAFKActionByName(t *testing.T) {
+	a, ok := AFKActionByName("bot")
+	assert.True(t, ok)
+	assert.Equal(t, AFKBot, a)
+
+	_, ok = AFKActionByName("ban")
+	assert.False(t, ok)
+}
+
+func TestCheckAFK_LobbyWarnsThenKicksUnready(t *testing.T) {
+	r, clients := setupTestRoom()
+	c3 := mockClient("client3")
+	r.AddPlayer(&game.Player{ID: "p3", Role: game.RoleThief}, c3)
+	start := r.Players["p3"].LastInputTime
+
+	// Ready players may wait as long as they like
+	assert.Empty(t, r.CheckAFK(start.Add(100*time.Second), testAFKPolicy))
+	assert.Empty(t, drainMessages(clients[0]))
+
+	warning := findMessageByType(drainMessages(c3), ws.TypeAFKWarning)
+	require.NotNil(t, warning)
+	var data afkWarningMessage
+	require.NoError(t, json.Unmarshal(warning.Data, &data))
+	assert.Equal(t, 20, data.SecondsLeft)
+	assert.Equal(t, AFKKick, data.Action)
+
+	// Warned once per idle period
+	r.CheckAFK(start.Add(110*time.Second), testAFKPolicy)
+	assert.Nil(t, findMessageByType(drainMessages(c3), ws.TypeAFKWarning))
+
+	removed := r.CheckAFK(start.Add(120*time.Second), testAFKPolicy)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "p3", removed[0].PlayerID)
+	assert.Equal(t, c3, removed[0].Client)
+	assert.NotNil(t, findMessageByType(drainMessages(c3), ws.TypeAFKRemoved))
+	assert.Equal(t, 2, r.PlayerCount())
+}
+
+func TestCheckAFK_InputResetsTimer(t *testing.T) {
+	r := NewRoom("TEST")
+	c1 := mockClient("client1")
+	r.AddPlayer(&game.Player{ID: "p1"}, c1)
+	start := r.Players["p1"].LastInputTime
+
+	r.CheckAFK(start.Add(100*time.Second), testAFKPolicy)
+	require.True(t, r.Players["p1"].AFKWarned)
+
+	r.TouchPlayer("p1")
+	assert.False(t, r.Players["p1"].AFKWarned)
+	assert.Empty(t, r.CheckAFK(start.Add(120*time.Second), testAFKPolicy))
+}
+
+func TestCheckAFK_GameKickForfeits(t *testing.T) {
+	r, clients := setupTestRoom()
+	r.PrepareGame()
+	start := r.Players["p1"].LastInputTime
+	r.TouchPlayer("p2")
+
+	removed := r.CheckAFK(start.Add(30*time.Second), testAFKPolicy)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "p1", removed[0].PlayerID)
+	assert.Equal(t, game.StateEnded, r.State)
+
+	over := findMessageByType(drainMessages(clients[1]), ws.TypeGameOver)
+	require.NotNil(t, over)
+	var data gameOverMessage
+	require.NoError(t, json.Unmarshal(over.Data, &data))
+	assert.Equal(t, "thief", data.Winner)
+	assert.Equal(t, game.ReasonForfeit, data.Reason)
+	assert.Equal(t, []string{"p1"}, data.Leavers)
+}
+
+func TestCheckAFK_GameBotTakesOver(t *testing.T) {
+	r, _ := setupTestRoom()
+	c3 := mockClient("client3")
+	r.AddPlayer(&game.Player{ID: "p3", Role: game.RoleThief, Ready: true}, c3)
+	require.True(t, r.SetAFKAction(AFKBot))
+	r.HostID = "p2"
+
+	var result MatchResult
+	r.onGameOver = func(mr MatchResult) { result = mr }
+
+	r.PrepareGame()
+	start := r.Players["p2"].LastInputTime
+	r.TouchPlayer("p1")
+	r.TouchPlayer("p3")
+
+	removed := r.CheckAFK(start.Add(30*time.Second), testAFKPolicy)
+	require.Len(t, removed, 1)
+	assert.Equal(t, AFKBot, removed[0].Action)
+	assert.Equal(t, game.StatePlaying, r.State, "bot keeps the thief team alive")
+	assert.True(t, r.Players["p2"].Bot)
+	assert.Nil(t, r.GetClient("p2"))
+	assert.NotEqual(t, "p2", r.HostID, "host passes to a human")
+
+	r.StopGame(game.WinThief)
+	for _, pr := range result.Players {
+		if pr.PlayerID == "p2" {
+			assert.True(t, pr.Left)
+			assert.False(t, pr.Won)
+		}
+	}
+
+	r.Reset()
+	assert.NotContains(t, r.Players, "p2", "bots leave with the lobby")
+	assert.Equal(t, 2, r.PlayerCount())
+}
+
+func TestCheckAFK_LastHumanKickedInsteadOfBot(t *testing.T) {
+	r, _ := setupTestRoom()
+	require.True(t, r.SetAFKAction(AFKBot))
+	r.PrepareGame()
+	start := r.Players["p1"].LastInputTime
+
+	removed := r.CheckAFK(start.Add(time.Minute), testAFKPolicy)
+	require.Len(t, removed, 2)
+	assert.True(t, r.IsEmpty(), "no room of bots is left behind")
+	assert.Equal(t, game.StateEnded, r.State)
+}
+
+func TestCheckAFK_DisabledAndSkippedStates(t *testing.T) {
+	r, _ := setupTestRoom()
+	r.SetPlayerReady("p1", false)
+	start := r.Players["p1"].LastInputTime
+
+	assert.Empty(t, r.CheckAFK(start.Add(time.Hour), AFKPolicy{}))
+
+	r.mu.Lock()
+	r.State = game.StateEnded
+	r.mu.Unlock()
+	assert.Empty(t, r.CheckAFK(start.Add(time.Hour), testAFKPolicy))
+}
+
+func TestManagerCheckAFK_InvokesHook(t *testing.T) {
+	m := NewManager()
+	r := m.CreateRoom()
+	c1 := mockClient("client1")
+	r.AddPlayer(&game.Player{ID: "p1"}, c1)
+	start := r.Players["p1"].LastInputTime
+
+	var got []AFKRemoval
+	m.OnAFKRemoved = func(hr *Room, removal AFKRemoval) {
+		assert.Equal(t, r, hr)
+		got = append(got, removal)
+	}
+
+	m.CheckAFK(start.Add(2*time.Minute), testAFKPolicy)
+	require.Len(t, got, 1)
+	assert.Equal(t, "p1", got[0].PlayerID)
+	assert.True(t, r.IsEmpty())
+}
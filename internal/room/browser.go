@@ -0,0 +1,156 @@
+package room
+
+import (
+	"crypto/subtle"
+	"sort"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+)
+
+// Visibility controls how a room can be found and joined.
+type Visibility string
+
+const (
+	VisibilityPublic   Visibility = "public"   // listed and open to random join
+	VisibilityPrivate  Visibility = "private"  // joinable by code only
+	VisibilityPassword Visibility = "password" // listed, joinable by code with the password
+)
+
+// MaxPasswordLength is the longest room password a host can set.
+const MaxPasswordLength = 32
+
+// Room list paging limits.
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 50
+)
+
+// VisibilityByName returns the visibility with the given name.
+func VisibilityByName(name string) (Visibility, bool) {
+	switch v := Visibility(name); v {
+	case VisibilityPublic, VisibilityPrivate, VisibilityPassword:
+		return v, true
+	}
+	return "", false
+}
+
+// RoomSummary describes a waiting room in the room browser.
+type RoomSummary struct {
+	Code         string `json:"code"`
+	Mode         string `json:"mode"`
+	Locked       bool   `json:"locked"` // password required to join
+	SeriesLength int    `json:"series_length"`
+	HostNickname string `json:"host_nickname"`
+	Players      int    `json:"players"`
+	MaxPlayers   int    `json:"max_players"`
+	Police       int    `json:"police"`
+	Thieves      int    `json:"thieves"`
+}
+
+// RoomList is a page of the room browser.
+type RoomList struct {
+	Total  int           `json:"total"`
+	Offset int           `json:"offset"`
+	Rooms  []RoomSummary `json:"rooms"`
+}
+
+// SetVisibility changes how the room can be found. A password is required
+// for VisibilityPassword and cleared otherwise. Returns false unless the
+// room is waiting and the password fits the visibility.
+func (r *Room) SetVisibility(v Visibility, password string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.State != game.StateWaiting {
+		return false
+	}
+	if v == VisibilityPassword {
+		if password == "" || len(password) > MaxPasswordLength {
+			return false
+		}
+	} else {
+		password = ""
+	}
+	r.Visibility = v
+	r.password = password
+	return true
+}
+
+// GetVisibility returns the room's visibility.
+func (r *Room) GetVisibility() Visibility {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.Visibility
+}
+
+// CheckPassword reports whether password admits a player to the room.
+// Rooms without a password admit everyone.
+func (r *Room) CheckPassword(password string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.Visibility != VisibilityPassword {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(password), []byte(r.password)) == 1
+}
+
+// summary describes the room for the browser. Caller must hold r.mu.
+func (r *Room) summary() RoomSummary {
+	s := RoomSummary{
+		Code:         r.Code,
+		Mode:         r.Mode.Name(),
+		Locked:       r.Visibility == VisibilityPassword,
+		SeriesLength: r.SeriesLength,
+		Players:      len(r.Players),
+		MaxPlayers:   game.MaxPlayers,
+	}
+	if host, ok := r.Players[r.HostID]; ok {
+		s.HostNickname = host.Nickname
+	}
+	for _, p := range r.Players {
+		switch p.Role {
+		case game.RolePolice:
+			s.Police++
+		case game.RoleThief:
+			s.Thieves++
+		}
+	}
+	return s
+}
+
+// ListRooms returns a page of listed rooms that are waiting and not full,
+// ordered by room code. Private rooms are never listed.
+func (m *Manager) ListRooms(offset, limit int) RoomList {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+
+	m.mu.RLock()
+	var listed []RoomSummary
+	for _, r := range m.rooms {
+		r.mu.RLock()
+		if r.State == game.StateWaiting && r.Visibility != VisibilityPrivate && len(r.Players) < game.MaxPlayers {
+			listed = append(listed, r.summary())
+		}
+		r.mu.RUnlock()
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(listed, func(i, j int) bool { return listed[i].Code < listed[j].Code })
+
+	list := RoomList{Total: len(listed), Offset: offset, Rooms: []RoomSummary{}}
+	if offset < len(listed) {
+		end := offset + limit
+		if end > len(listed) {
+			end = len(listed)
+		}
+		list.Rooms = listed[offset:end]
+	}
+	return list
+}
@@ -0,0 +1,80 @@
+package room
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+)
+
+func TestSetVisibility_Password(t *testing.T) {
+	r := NewRoom("TEST")
+	assert.Equal(t, VisibilityPublic, r.GetVisibility())
+
+	assert.False(t, r.SetVisibility(VisibilityPassword, ""), "password required")
+	require.True(t, r.SetVisibility(VisibilityPassword, "secret"))
+	assert.True(t, r.CheckPassword("secret"))
+	assert.False(t, r.CheckPassword("wrong"))
+	assert.False(t, r.CheckPassword(""))
+
+	// Leaving password mode drops the password
+	require.True(t, r.SetVisibility(VisibilityPrivate, "secret"))
+	assert.True(t, r.CheckPassword(""))
+}
+
+func TestSetVisibility_OnlyWhileWaiting(t *testing.T) {
+	r, _ := setupTestRoom()
+	r.PrepareGame()
+	assert.False(t, r.SetVisibility(VisibilityPrivate, ""))
+	r.StopGame(game.WinNone)
+}
+
+func TestListRooms_FiltersAndPages(t *testing.T) {
+	m := NewManager()
+	var codes []string
+	for i := 0; i < 5; i++ {
+		r := m.CreateRoom()
+		r.AddPlayer(&game.Player{ID: r.Code + "-host", Nickname: "Host" + r.Code, Role: game.RolePolice}, mockClient(r.Code))
+		codes = append(codes, r.Code)
+	}
+	m.GetRoom(codes[0]).SetVisibility(VisibilityPrivate, "")
+	m.GetRoom(codes[1]).SetVisibility(VisibilityPassword, "pw")
+	m.GetRoom(codes[2]).PrepareGame()
+
+	list := m.ListRooms(0, 2)
+	assert.Equal(t, 3, list.Total)
+	require.Len(t, list.Rooms, 2)
+	assert.Less(t, list.Rooms[0].Code, list.Rooms[1].Code)
+
+	rest := m.ListRooms(2, 2)
+	require.Len(t, rest.Rooms, 1)
+	assert.Equal(t, 2, rest.Offset)
+
+	all := append(list.Rooms, rest.Rooms...)
+	for _, s := range all {
+		assert.NotEqual(t, codes[0], s.Code, "private rooms are hidden")
+		assert.NotEqual(t, codes[2], s.Code, "playing rooms are hidden")
+		assert.Equal(t, "Host"+s.Code, s.HostNickname)
+		assert.Equal(t, 1, s.Players)
+		assert.Equal(t, 1, s.Police)
+		assert.Equal(t, 0, s.Thieves)
+		assert.Equal(t, s.Code == codes[1], s.Locked)
+	}
+
+	assert.Empty(t, m.ListRooms(10, 2).Rooms)
+}
+
+func TestFindAvailableRoom_SkipsNonPublic(t *testing.T) {
+	m := NewManager()
+	private := m.CreateRoom()
+	private.SetVisibility(VisibilityPrivate, "")
+	locked := m.CreateRoom()
+	locked.SetVisibility(VisibilityPassword, "pw")
+
+	assert.Nil(t, m.FindAvailableRoom(game.RoleNone))
+
+	public := m.CreateRoom()
+	assert.Equal(t, public, m.FindAvailableRoom(game.RoleNone))
+}
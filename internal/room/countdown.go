@@ -0,0 +1,127 @@
+package room
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+type countdownMessage struct {
+	Remaining int `json:"remaining"` // seconds; 0 means play has started
+}
+
+type countdownCancelledMessage struct {
+	PlayerID string `json:"player_id"` // player who left or un-readied
+}
+
+type clientLoadedMessage struct {
+	PlayerID string `json:"player_id"`
+	Loaded   int    `json:"loaded"`
+	Total    int    `json:"total"`
+}
+
+// beginCountdown prepares the match and enters the starting state.
+// Returns the channel that cancels the countdown, or nil if the room
+// cannot start from its current state. Caller must hold r.mu.
+func (r *Room) beginCountdown() chan struct{} {
+	if err := r.transition(game.StateStarting); err != nil {
+		slog.Error("failed to begin countdown", "room", r.Code, "error", err)
+		return nil
+	}
+	r.prepareGame()
+	r.loading = make(map[string]bool, len(r.Players))
+	for id := range r.Players {
+		r.loading[id] = true
+	}
+	r.countdownCh = make(chan struct{})
+	return r.countdownCh
+}
+
+// runCountdown broadcasts the remaining seconds and starts the game loop
+// when the countdown completes, unless cancel is closed first.
+func (r *Room) runCountdown(cancel chan struct{}) {
+	for remaining := game.CountdownSeconds; remaining > 0; remaining-- {
+		select {
+		case <-cancel:
+			return
+		default:
+		}
+		r.broadcastCountdown(remaining)
+
+		select {
+		case <-cancel:
+			return
+		case <-time.After(r.countdownStep):
+		}
+	}
+
+	r.mu.Lock()
+	if r.countdownCh != cancel {
+		r.mu.Unlock()
+		return
+	}
+	r.countdownCh = nil
+	if err := r.transition(game.StatePlaying); err != nil {
+		r.mu.Unlock()
+		slog.Error("failed to start game after countdown", "room", r.Code, "error", err)
+		return
+	}
+	r.mu.Unlock()
+
+	r.broadcastCountdown(0)
+	r.StartGameLoop()
+	slog.Info("countdown finished, game started", "room", r.Code)
+}
+
+// cancelCountdown aborts a running countdown and returns the room to waiting.
+// Returns false if no countdown was running. Caller must hold r.mu.
+func (r *Room) cancelCountdown() bool {
+	if r.State != game.StateStarting {
+		return false
+	}
+	// Leaving starting closes countdownCh; entering waiting drops the series
+	return r.transition(game.StateWaiting) == nil
+}
+
+func (r *Room) broadcastCountdown(remaining int) {
+	msg, _ := ws.NewMessage(ws.TypeCountdown, countdownMessage{Remaining: remaining})
+	r.BroadcastMessage(msg)
+}
+
+func (r *Room) broadcastCountdownCancelled(playerID string) {
+	msg, _ := ws.NewMessage(ws.TypeCountdownCancelled, countdownCancelledMessage{PlayerID: playerID})
+	r.BroadcastMessage(msg)
+	slog.Info("countdown cancelled", "room", r.Code, "player", playerID)
+}
+
+// MarkLoaded records that a player finished loading the map and broadcasts
+// loading progress. Returns false if the player was not expected to load.
+func (r *Room) MarkLoaded(playerID string) bool {
+	r.mu.Lock()
+	if !r.loading[playerID] {
+		r.mu.Unlock()
+		return false
+	}
+	delete(r.loading, playerID)
+	total := len(r.Players)
+	loaded := total - len(r.loading)
+	r.mu.Unlock()
+
+	msg, _ := ws.NewMessage(ws.TypeClientLoaded, clientLoadedMessage{
+		PlayerID: playerID,
+		Loaded:   loaded,
+		Total:    total,
+	})
+	r.BroadcastMessage(msg)
+	return true
+}
+
+// AwaitingLoad reports whether the player has not yet sent client_loaded.
+// Movement from such players is rejected.
+func (r *Room) AwaitingLoad(playerID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.loading[playerID]
+}
@@ -0,0 +1,106 @@
+package room
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// waitForState waits until the room reaches the given state.
+func waitForState(t *testing.T, r *Room, state game.RoomState) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		return r.State == state
+	}, time.Second, time.Millisecond, "room should reach %s", state)
+}
+
+func TestStartRound_CountsDownThenPlays(t *testing.T) {
+	r, clients := setupTestRoom()
+	r.countdownStep = 20 * time.Millisecond
+
+	r.StartRound()
+	defer r.StopGame(game.WinNone)
+
+	assert.Equal(t, game.StateStarting, r.State)
+	assert.True(t, r.AwaitingLoad("p1"))
+	assert.True(t, r.AwaitingLoad("p2"))
+
+	waitForState(t, r, game.StatePlaying)
+
+	msgs := drainMessages(clients[0])
+	start := findMessageByType(msgs, ws.TypeGameStart)
+	require.NotNil(t, start)
+	var startData gameStartMessage
+	require.NoError(t, json.Unmarshal(start.Data, &startData))
+	assert.Equal(t, game.CountdownSeconds, startData.Countdown)
+	assert.NotEmpty(t, startData.MapObjects)
+
+	var remaining []int
+	for _, m := range msgs {
+		if m.Type == ws.TypeCountdown {
+			var cd countdownMessage
+			require.NoError(t, json.Unmarshal(m.Data, &cd))
+			remaining = append(remaining, cd.Remaining)
+		}
+	}
+	assert.Equal(t, []int{3, 2, 1, 0}, remaining)
+}
+
+func TestStartRound_CancelledByUnready(t *testing.T) {
+	r, clients := setupTestRoom()
+	r.countdownStep = time.Hour
+
+	r.StartRound()
+	allReady := r.SetPlayerReady("p2", false)
+
+	assert.False(t, allReady)
+	assert.Equal(t, game.StateWaiting, r.State)
+	assert.False(t, r.AwaitingLoad("p1"))
+	msgs := drainMessages(clients[0])
+	cancelled := findMessageByType(msgs, ws.TypeCountdownCancelled)
+	require.NotNil(t, cancelled)
+	assert.Contains(t, string(cancelled.Data), `"p2"`)
+
+	// Readying again starts from the lobby as usual
+	assert.True(t, r.SetPlayerReady("p2", true))
+}
+
+func TestStartRound_CancelledByLeave(t *testing.T) {
+	r, clients := setupTestRoom()
+	r.countdownStep = time.Hour
+
+	r.StartRound()
+	r.RemovePlayer("p2")
+
+	assert.Equal(t, game.StateWaiting, r.State)
+	msgs := drainMessages(clients[0])
+	assert.NotNil(t, findMessageByType(msgs, ws.TypeCountdownCancelled))
+}
+
+func TestMarkLoaded(t *testing.T) {
+	r, clients := setupTestRoom()
+	r.countdownStep = time.Hour
+
+	r.StartRound()
+	defer r.SetPlayerReady("p1", false)
+
+	assert.True(t, r.MarkLoaded("p1"))
+	assert.False(t, r.AwaitingLoad("p1"))
+	assert.True(t, r.AwaitingLoad("p2"))
+	assert.False(t, r.MarkLoaded("p1"), "second client_loaded is ignored")
+
+	msgs := drainMessages(clients[1])
+	loaded := findMessageByType(msgs, ws.TypeClientLoaded)
+	require.NotNil(t, loaded)
+	var data clientLoadedMessage
+	require.NoError(t, json.Unmarshal(loaded.Data, &data))
+	assert.Equal(t, clientLoadedMessage{PlayerID: "p1", Loaded: 1, Total: 2}, data)
+}
@@ -2,6 +2,7 @@ package room
 
 import (
 	"encoding/json"
+	"sync"
 	"testing"
 	"time"
 
@@ -166,7 +167,7 @@ func TestGameLoop_TimerExpiry(t *testing.T) {
 	}
 	r.mu.Unlock()
 
-	go r.gameLoop()
+	r.StartGameLoop()
 
 	// Wait for timer to expire
 	time.Sleep(300 * time.Millisecond)
@@ -182,9 +183,30 @@ func TestGameLoop_TimerExpiry(t *testing.T) {
 		var data gameOverMessage
 		json.Unmarshal(overMsg.Data, &data)
 		assert.Equal(t, "thief", data.Winner)
+		assert.Equal(t, game.ReasonTimeout, data.Reason)
 	}
 }
 
+func TestStopGame_GameOverIncludesStats(t *testing.T) {
+	r, clients := setupTestRoom()
+	r.PrepareGame()
+	r.StartGameLoop()
+
+	time.Sleep(game.TickInterval + 10*time.Millisecond)
+	drainMessages(clients[0])
+
+	r.StopGame(game.WinPolice)
+
+	overMsg := findMessageByType(drainMessages(clients[0]), ws.TypeGameOver)
+	require.NotNil(t, overMsg)
+
+	var data gameOverMessage
+	require.NoError(t, json.Unmarshal(overMsg.Data, &data))
+	assert.Len(t, data.Stats, 2)
+	assert.Equal(t, "p1", data.MVP.Police)
+	assert.Equal(t, "p2", data.MVP.Thief)
+}
+
 func TestStopGame_DoubleStopSafe(t *testing.T) {
 	r, _ := setupTestRoom()
 	r.PrepareGame()
@@ -198,3 +220,195 @@ func TestStopGame_DoubleStopSafe(t *testing.T) {
 
 	assert.Equal(t, game.StateEnded, r.State)
 }
+
+func TestGameLoop_EmitsArrestEvent(t *testing.T) {
+	r, clients := setupTestRoom()
+	r.PrepareGame()
+
+	var received []game.Event
+	var mu sync.Mutex
+	r.Events().Subscribe(func(e game.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, e)
+	})
+
+	// Put police on top of the thief with an almost full gauge
+	r.mu.Lock()
+	r.Players["p1"].SetPosition(1000, 1000)
+	r.Players["p2"].SetPosition(1000, 1000)
+	r.Players["p2"].ArrestGauge = game.ArrestDuration - 0.01
+	r.mu.Unlock()
+
+	r.StartGameLoop()
+	time.Sleep(game.TickInterval + 20*time.Millisecond)
+
+	mu.Lock()
+	var arrest *game.Event
+	for i := range received {
+		if received[i].Type == game.EventThiefArrested {
+			arrest = &received[i]
+		}
+	}
+	mu.Unlock()
+	require.NotNil(t, arrest, "subscriber should receive thief_arrested")
+	assert.Equal(t, "p1", arrest.ActorID)
+	assert.Equal(t, []string{"p2"}, arrest.TargetIDs)
+	assert.Equal(t, "TEST", arrest.Room)
+
+	for _, c := range clients {
+		msgs := drainMessages(c)
+		require.NotNil(t, findMessageByType(msgs, ws.TypeThiefArrested), "should receive thief_arrested message")
+	}
+}
+
+func TestStopGame_InvokesGameOverHook(t *testing.T) {
+	m := NewManager()
+	r := m.CreateRoom()
+	c1 := mockClient("client1")
+	c2 := mockClient("client2")
+	r.AddPlayer(&game.Player{ID: "p1", AccountID: "acc-police", Role: game.RolePolice, Ready: true}, c1)
+	r.AddPlayer(&game.Player{ID: "p2", AccountID: "acc-thief", Role: game.RoleThief, Ready: true}, c2)
+
+	results := make(chan MatchResult, 1)
+	m.OnGameOver = func(_ *Room, result MatchResult) { results <- result }
+
+	r.PrepareGame()
+	r.StartGameLoop()
+	r.StopGame(game.WinThief)
+
+	select {
+	case result := <-results:
+		assert.Equal(t, game.WinThief, result.Winner)
+		require.Len(t, result.Players, 2)
+		for _, pr := range result.Players {
+			switch pr.PlayerID {
+			case "p1":
+				assert.Equal(t, "acc-police", pr.AccountID)
+				assert.Equal(t, game.RolePolice, pr.Role)
+				assert.False(t, pr.Won)
+			case "p2":
+				assert.Equal(t, "acc-thief", pr.AccountID)
+				assert.Equal(t, game.RoleThief, pr.Role)
+				assert.True(t, pr.Won)
+				assert.True(t, pr.LastFreeThief)
+			}
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnGameOver was not called")
+	}
+}
+
+func TestGameLoop_InfectionConvertsThief(t *testing.T) {
+	r, clients := setupTestRoom()
+	require.True(t, r.SetMode(game.InfectionMode{}))
+	r.PrepareGame()
+
+	r.mu.Lock()
+	r.Players["p1"].SetPosition(1000, 1000)
+	r.Players["p2"].SetPosition(1000, 1000)
+	r.Players["p2"].ArrestGauge = game.ArrestDuration - 0.01
+	r.mu.Unlock()
+
+	r.StartGameLoop()
+	time.Sleep(game.TickInterval + 20*time.Millisecond)
+
+	// The last thief was infected, so police win
+	r.mu.RLock()
+	assert.Equal(t, game.StateEnded, r.State)
+	assert.Equal(t, game.RolePolice, r.Players["p2"].Role)
+	assert.False(t, r.Players["p2"].IsArrested())
+	r.mu.RUnlock()
+
+	msgs := drainMessages(clients[0])
+	require.NotNil(t, findMessageByType(msgs, ws.TypeThiefInfected), "should receive thief_infected message")
+	over := findMessageByType(msgs, ws.TypeGameOver)
+	require.NotNil(t, over)
+	var data struct {
+		Winner string `json:"winner"`
+	}
+	require.NoError(t, json.Unmarshal(over.Data, &data))
+	assert.Equal(t, "police", data.Winner)
+
+	// Returning to the lobby restores the roles picked before the match
+	r.Reset()
+	assert.Equal(t, game.RoleThief, r.Players["p2"].Role)
+}
+
+func TestSetMode_OnlyWhileWaiting(t *testing.T) {
+	r, _ := setupTestRoom()
+	assert.Equal(t, game.ModeClassic, r.ModeName())
+
+	require.True(t, r.SetMode(game.InfectionMode{}))
+	assert.Equal(t, game.ModeInfection, r.ModeName())
+
+	r.PrepareGame()
+	r.StartGameLoop()
+	defer r.StopGame(game.WinNone)
+
+	assert.False(t, r.SetMode(game.ClassicMode{}))
+	assert.Equal(t, game.ModeInfection, r.ModeName())
+}
+
+func TestRemovePlayer_PoliceLeavingForfeits(t *testing.T) {
+	m := NewManager()
+	r := m.CreateRoom()
+	c1 := mockClient("client1")
+	c2 := mockClient("client2")
+	r.AddPlayer(&game.Player{ID: "p1", AccountID: "acc-police", Role: game.RolePolice, Ready: true}, c1)
+	r.AddPlayer(&game.Player{ID: "p2", Role: game.RoleThief, Ready: true}, c2)
+
+	results := make(chan MatchResult, 1)
+	m.OnGameOver = func(_ *Room, result MatchResult) { results <- result }
+
+	r.PrepareGame()
+	r.StartGameLoop()
+	r.RemovePlayer("p1")
+
+	var result MatchResult
+	select {
+	case result = <-results:
+	case <-time.After(time.Second):
+		t.Fatal("OnGameOver was not called")
+	}
+	assert.Equal(t, game.WinThief, result.Winner)
+	assert.Equal(t, game.ReasonForfeit, result.Reason)
+	require.Len(t, result.Leavers, 1)
+	assert.Equal(t, Leaver{PlayerID: "p1", AccountID: "acc-police", Role: game.RolePolice, Elapsed: result.Leavers[0].Elapsed}, result.Leavers[0])
+	for _, pr := range result.Players {
+		if pr.PlayerID == "p1" {
+			assert.True(t, pr.Left)
+			assert.False(t, pr.Won)
+			assert.Equal(t, "acc-police", pr.AccountID, "leavers keep their account for penalties")
+		} else {
+			assert.True(t, pr.Won)
+		}
+	}
+	assert.Empty(t, result.MVP.Police, "leavers cannot be MVP")
+
+	over := findMessageByType(drainMessages(c2), ws.TypeGameOver)
+	require.NotNil(t, over)
+	var data gameOverMessage
+	require.NoError(t, json.Unmarshal(over.Data, &data))
+	assert.Equal(t, game.ReasonForfeit, data.Reason)
+	assert.Equal(t, []string{"p1"}, data.Leavers)
+}
+
+func TestGameLoop_EndReason(t *testing.T) {
+	r, clients := setupTestRoom()
+	r.PrepareGame()
+
+	r.mu.Lock()
+	r.Players["p2"].Arrest()
+	r.mu.Unlock()
+
+	r.StartGameLoop()
+	time.Sleep(game.TickInterval + 20*time.Millisecond)
+
+	over := findMessageByType(drainMessages(clients[0]), ws.TypeGameOver)
+	require.NotNil(t, over)
+	var data gameOverMessage
+	require.NoError(t, json.Unmarshal(over.Data, &data))
+	assert.Equal(t, "police", data.Winner)
+	assert.Equal(t, game.ReasonAllArrested, data.Reason)
+}
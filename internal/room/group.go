@@ -0,0 +1,86 @@
+package room
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// AddGroup adds several players at once, all or none. Players and clients are
+// matched by index and the first player becomes host of an empty room. A role
+// other than RoleNone puts the whole group on that team. Returns false if the
+// room is not waiting, lacks space for the group (or on the chosen team), or
+// already holds one of the group's accounts.
+func (r *Room) AddGroup(players []*game.Player, clients []*ws.Client, role game.Role) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.fitsGroup(len(players), role) {
+		return false
+	}
+	for _, p := range players {
+		if r.hasAccount(p.AccountID) {
+			return false
+		}
+	}
+
+	now := time.Now()
+	for i, p := range players {
+		if role != game.RoleNone {
+			p.SetRole(role)
+		}
+		p.Touch(now)
+		r.Players[p.ID] = p
+		r.clients[p.ID] = clients[i]
+	}
+	if len(r.Players) == len(players) {
+		r.HostID = players[0].ID
+	}
+	return true
+}
+
+// fitsGroup reports whether n players can join, optionally all on role.
+// Caller must hold r.mu.
+func (r *Room) fitsGroup(n int, role game.Role) bool {
+	if r.State != game.StateWaiting || len(r.Players)+n > game.MaxPlayers {
+		return false
+	}
+	if role == game.RolePolice {
+		police := 0
+		for _, p := range r.Players {
+			if p.Role == game.RolePolice {
+				police++
+			}
+		}
+		return police+n <= game.MaxPolice
+	}
+	return true
+}
+
+// PlaceGroup adds a group to a random public waiting room with space for all
+// of them, trying candidates until one accepts. Returns nil if none does.
+func (m *Manager) PlaceGroup(players []*game.Player, clients []*ws.Client, role game.Role) *Room {
+	m.mu.RLock()
+	var candidates []*Room
+	for _, r := range m.rooms {
+		r.mu.RLock()
+		ok := r.Visibility == VisibilityPublic && r.fitsGroup(len(players), role)
+		r.mu.RUnlock()
+		if ok {
+			candidates = append(candidates, r)
+		}
+	}
+	m.mu.RUnlock()
+
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	for _, r := range candidates {
+		if r.AddGroup(players, clients, role) {
+			return r
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package room
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+func testGroup(prefix string, n int) ([]*game.Player, []*ws.Client) {
+	players := make([]*game.Player, n)
+	clients := make([]*ws.Client, n)
+	for i := range players {
+		id := fmt.Sprintf("%s%d", prefix, i)
+		players[i] = &game.Player{ID: id, Nickname: id}
+		clients[i] = mockClient(id)
+	}
+	return players, clients
+}
+
+func TestAddGroup_AllOrNothing(t *testing.T) {
+	r := NewRoom("TEST")
+	players, clients := testGroup("a", 3)
+	require.True(t, r.AddGroup(players, clients, game.RoleNone))
+	assert.Equal(t, "a0", r.HostID, "first member hosts an empty room")
+
+	big, bigClients := testGroup("b", game.MaxPlayers-2)
+	assert.False(t, r.AddGroup(big, bigClients, game.RoleNone))
+	assert.Equal(t, 3, r.PlayerCount(), "nobody from a rejected group joins")
+
+	small, smallClients := testGroup("c", 2)
+	require.True(t, r.AddGroup(small, smallClients, game.RoleNone))
+	assert.Equal(t, "a0", r.HostID, "host is kept")
+}
+
+func TestAddGroup_SameTeam(t *testing.T) {
+	r := NewRoom("TEST")
+	trio, trioClients := testGroup("a", game.MaxPolice+1)
+	assert.False(t, r.AddGroup(trio, trioClients, game.RolePolice), "police team too small")
+
+	pair, pairClients := testGroup("b", game.MaxPolice)
+	require.True(t, r.AddGroup(pair, pairClients, game.RolePolice))
+	assert.Equal(t, game.MaxPolice, r.PoliceCount())
+
+	thieves, thiefClients := testGroup("c", 3)
+	require.True(t, r.AddGroup(thieves, thiefClients, game.RoleThief))
+	for _, p := range thieves {
+		assert.Equal(t, game.RoleThief, p.Role)
+	}
+}
+
+func TestAddGroup_OnlyWhileWaiting(t *testing.T) {
+	r, _ := setupTestRoom()
+	r.PrepareGame()
+	players, clients := testGroup("a", 1)
+	assert.False(t, r.AddGroup(players, clients, game.RoleNone))
+	r.StopGame(game.WinNone)
+}
+
+func TestPlaceGroup(t *testing.T) {
+	m := NewManager()
+	crowded := m.CreateRoom()
+	fill, fillClients := testGroup("x", game.MaxPlayers-1)
+	require.True(t, crowded.AddGroup(fill, fillClients, game.RoleNone))
+	private := m.CreateRoom()
+	private.SetVisibility(VisibilityPrivate, "")
+	open := m.CreateRoom()
+
+	players, clients := testGroup("a", 3)
+	assert.Same(t, open, m.PlaceGroup(players, clients, game.RoleNone))
+	assert.Equal(t, 3, open.PlayerCount())
+
+	m.RemoveRoom(open.Code)
+	more, moreClients := testGroup("b", 3)
+	assert.Nil(t, m.PlaceGroup(more, moreClients, game.RoleNone))
+}
+
+func TestAddPlayer_RejectsDuplicateAccount(t *testing.T) {
+	r := NewRoom("TEST")
+	require.True(t, r.AddPlayer(game.NewAccountPlayer("acc-1", "Alice", false), mockClient("c1")))
+	assert.True(t, r.HasAccount("acc-1"))
+
+	assert.False(t, r.AddPlayer(game.NewAccountPlayer("acc-1", "Alice", false), mockClient("c2")))
+
+	group, clients := testGroup("a", 2)
+	group[1].AccountID = "acc-1"
+	assert.False(t, r.AddGroup(group, clients, game.RoleNone))
+	assert.Equal(t, 1, r.PlayerCount())
+
+	// Players without an account never collide
+	require.True(t, r.AddPlayer(game.NewPlayer("x"), mockClient("c3")))
+	require.True(t, r.AddPlayer(game.NewPlayer("y"), mockClient("c4")))
+}
@@ -0,0 +1,118 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+)
+
+// ErrInvalidTransition is returned when a room state change is not an allowed edge.
+var ErrInvalidTransition = errors.New("invalid room state transition")
+
+// transitions lists the allowed room state edges.
+//
+//	waiting  -> starting  all players ready, countdown begins
+//	waiting  -> playing   direct start without countdown (PrepareGame)
+//	starting -> playing   countdown finished
+//	starting -> waiting   countdown cancelled (un-ready or leave)
+//	playing  -> ended     match decided, stopped or abandoned
+//	ended    -> waiting   return to lobby
+//	ended    -> starting  next series round
+var transitions = map[game.RoomState][]game.RoomState{
+	game.StateWaiting:  {game.StateStarting, game.StatePlaying},
+	game.StateStarting: {game.StatePlaying, game.StateWaiting},
+	game.StatePlaying:  {game.StateEnded},
+	game.StateEnded:    {game.StateWaiting, game.StateStarting},
+}
+
+// stateHook runs during a transition with r.mu held. It must not block or
+// call methods that take r.mu.
+type stateHook func(r *Room, from, to game.RoomState)
+
+// exitHooks run before the room leaves a state.
+var exitHooks = map[game.RoomState]stateHook{
+	game.StateStarting: func(r *Room, _, _ game.RoomState) {
+		// Stop the countdown goroutine if it is still running
+		if r.countdownCh != nil {
+			close(r.countdownCh)
+			r.countdownCh = nil
+		}
+	},
+	game.StatePlaying: func(r *Room, _, _ game.RoomState) {
+		// Signal the game loop to stop
+		if r.stopCh == nil {
+			return
+		}
+		select {
+		case <-r.stopCh:
+			// Already closed
+		default:
+			close(r.stopCh)
+		}
+	},
+	game.StateEnded: func(r *Room, _, _ game.RoomState) {
+		if r.intermissionTimer != nil {
+			r.intermissionTimer.Stop()
+			r.intermissionTimer = nil
+		}
+	},
+}
+
+// enterHooks run after the room enters a state.
+var enterHooks = map[game.RoomState]stateHook{
+	game.StateWaiting: func(r *Room, from, _ game.RoomState) {
+		// Back in the lobby: drop any series and bots and restore the roles chosen there
+		r.series = nil
+		r.loading = nil
+		r.removeBots()
+		r.touchAll()
+		for id, p := range r.Players {
+			if from == game.StateEnded {
+				p.Reset()
+			}
+			if role, ok := r.lobbyRoles[id]; ok {
+				p.SetRole(role)
+			}
+		}
+	},
+}
+
+// CanTransition reports whether a room may move from one state to another.
+func CanTransition(from, to game.RoomState) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentState returns the room's state. Use it instead of reading State
+// outside the room, since the game loop changes State under the room lock.
+func (r *Room) CurrentState() game.RoomState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.State
+}
+
+// transition moves the room to a new state, running the exit hook of the
+// current state and the enter hook of the new one. Caller must hold r.mu.
+func (r *Room) transition(to game.RoomState) error {
+	from := r.State
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+
+	if hook, ok := exitHooks[from]; ok {
+		hook(r, from, to)
+	}
+	r.State = to
+	if hook, ok := enterHooks[to]; ok {
+		hook(r, from, to)
+	}
+
+	slog.Debug("room state changed", "room", r.Code, "from", from.String(), "to", to.String())
+	return nil
+}
@@ -0,0 +1,168 @@
+package room
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+)
+
+var allRoomStates = []game.RoomState{
+	game.StateWaiting,
+	game.StateStarting,
+	game.StatePlaying,
+	game.StateEnded,
+}
+
+func TestCanTransition_AllEdges(t *testing.T) {
+	allowed := map[[2]game.RoomState]bool{
+		{game.StateWaiting, game.StateStarting}: true,
+		{game.StateWaiting, game.StatePlaying}:  true,
+		{game.StateStarting, game.StatePlaying}: true,
+		{game.StateStarting, game.StateWaiting}: true,
+		{game.StatePlaying, game.StateEnded}:    true,
+		{game.StateEnded, game.StateWaiting}:    true,
+		{game.StateEnded, game.StateStarting}:   true,
+	}
+
+	for _, from := range allRoomStates {
+		for _, to := range allRoomStates {
+			want := allowed[[2]game.RoomState{from, to}]
+			t.Run(from.String()+"->"+to.String(), func(t *testing.T) {
+				assert.Equal(t, want, CanTransition(from, to))
+
+				r, _ := setupTestRoom()
+				r.State = from
+				r.stopCh = make(chan struct{})
+				err := r.transition(to)
+				if want {
+					require.NoError(t, err)
+					assert.Equal(t, to, r.State)
+				} else {
+					assert.True(t, errors.Is(err, ErrInvalidTransition))
+					assert.Equal(t, from, r.State, "state must not change on a rejected edge")
+				}
+			})
+		}
+	}
+}
+
+func TestTransition_ExitPlayingStopsLoop(t *testing.T) {
+	r, _ := setupTestRoom()
+	r.PrepareGame()
+	stopCh := r.stopCh
+
+	r.mu.Lock()
+	require.NoError(t, r.transition(game.StateEnded))
+	r.mu.Unlock()
+
+	select {
+	case <-stopCh:
+	default:
+		t.Fatal("leaving playing should close stopCh")
+	}
+}
+
+func TestTransition_ExitStartingCancelsCountdown(t *testing.T) {
+	r, _ := setupTestRoom()
+	r.mu.Lock()
+	cancel := r.beginCountdown()
+	require.NotNil(t, cancel)
+	require.NoError(t, r.transition(game.StateWaiting))
+	r.mu.Unlock()
+
+	select {
+	case <-cancel:
+	default:
+		t.Fatal("leaving starting should close the countdown channel")
+	}
+	assert.Nil(t, r.countdownCh)
+	assert.False(t, r.AwaitingLoad("p1"))
+}
+
+func TestTransition_EnterWaitingFromEndedResetsPlayers(t *testing.T) {
+	r, _ := setupTestRoom()
+	require.True(t, r.SetSeriesLength(3))
+	r.PrepareGame()
+	r.Players["p2"].Infect()
+	r.StopGame(game.WinPolice)
+	require.True(t, r.InSeries())
+
+	r.mu.Lock()
+	require.NoError(t, r.transition(game.StateWaiting))
+	r.mu.Unlock()
+
+	assert.Nil(t, r.series)
+	assert.Nil(t, r.intermissionTimer, "leaving ended should stop the intermission")
+	for _, p := range r.Players {
+		assert.False(t, p.Ready)
+		assert.Zero(t, p.X)
+	}
+	assert.Equal(t, game.RoleThief, r.Players["p2"].Role, "lobby role restored")
+}
+
+func TestTransition_EnterWaitingFromStartingKeepsReady(t *testing.T) {
+	r, _ := setupTestRoom()
+	r.mu.Lock()
+	require.NotNil(t, r.beginCountdown())
+	require.NoError(t, r.transition(game.StateWaiting))
+	r.mu.Unlock()
+
+	assert.True(t, r.Players["p1"].Ready)
+	assert.True(t, r.Players["p2"].Ready)
+}
+
+func TestReset_OnlyFromEnded(t *testing.T) {
+	r, _ := setupTestRoom()
+	r.PrepareGame()
+	r.Reset()
+	assert.Equal(t, game.StatePlaying, r.State, "reset must not interrupt a match")
+
+	r.StopGame(game.WinNone)
+	r.Reset()
+	assert.Equal(t, game.StateWaiting, r.State)
+}
+
+func TestRemovePlayer_StopsMatchWhenTeamLeaves(t *testing.T) {
+	r, _ := setupTestRoom()
+	r.PrepareGame()
+	r.StartGameLoop()
+	stopCh := r.stopCh
+
+	r.RemovePlayer("p2")
+
+	assert.Equal(t, game.StateEnded, r.State)
+	select {
+	case <-stopCh:
+	case <-time.After(time.Second):
+		t.Fatal("game loop should be stopped")
+	}
+}
+
+func TestRemovePlayer_StopsMatchWhenRoomEmpties(t *testing.T) {
+	r, _ := setupTestRoom()
+	r.PrepareGame()
+	r.StartGameLoop()
+
+	r.RemovePlayer("p1")
+	r.RemovePlayer("p2")
+
+	assert.True(t, r.IsEmpty())
+	assert.Equal(t, game.StateEnded, r.State)
+}
+
+func TestRemovePlayer_KeepsMatchWhileTeamsRemain(t *testing.T) {
+	r, _ := setupTestRoom()
+	r.AddPlayer(&game.Player{ID: "p3", Role: game.RoleThief, Ready: true}, mockClient("client3"))
+	r.PrepareGame()
+	r.StartGameLoop()
+	defer r.StopGame(game.WinNone)
+
+	r.RemovePlayer("p3")
+
+	assert.Equal(t, game.StatePlaying, r.State)
+}
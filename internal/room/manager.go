@@ -1,26 +1,43 @@
 package room
 
 import (
+	"context"
 	"log/slog"
 	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
 )
 
 // Manager manages all active rooms.
 type Manager struct {
-	rooms map[string]*Room // code -> room
-	mu    sync.RWMutex
+	rooms  map[string]*Room // code -> room
+	events *game.EventBus   // gameplay events from every room
+	mu     sync.RWMutex
+
+	// OnGameOver is called after a match ends in any room.
+	// It runs on the room's game loop goroutine and must not block.
+	OnGameOver func(r *Room, result MatchResult)
+
+	// OnAFKRemoved is called after a player is taken out of a room for inactivity,
+	// so the caller can release the client's session state.
+	OnAFKRemoved func(r *Room, removal AFKRemoval)
 }
 
 // NewManager creates a new room manager.
 func NewManager() *Manager {
 	return &Manager{
-		rooms: make(map[string]*Room),
+		rooms:  make(map[string]*Room),
+		events: game.NewEventBus(),
 	}
 }
 
+// Events returns a bus that receives gameplay events from all rooms.
+func (m *Manager) Events() *game.EventBus {
+	return m.events
+}
+
 // CreateRoom creates a new room and returns it.
 func (m *Manager) CreateRoom() *Room {
 	m.mu.Lock()
@@ -33,6 +50,12 @@ func (m *Manager) CreateRoom() *Room {
 
 	code := GenerateCode(existing)
 	room := NewRoom(code)
+	room.Events().Subscribe(m.events.Publish)
+	room.onGameOver = func(result MatchResult) {
+		if m.OnGameOver != nil {
+			m.OnGameOver(room, result)
+		}
+	}
 	m.rooms[code] = room
 
 	slog.Info("room created", "code", code)
@@ -61,7 +84,7 @@ func (m *Manager) RoomCount() int {
 	return len(m.rooms)
 }
 
-// FindAvailableRoom returns a random room that is waiting and not full.
+// FindAvailableRoom returns a random public room that is waiting and not full.
 // If preferredRole is specified, it prefers rooms where that role is available.
 func (m *Manager) FindAvailableRoom(preferredRole game.Role) *Room {
 	m.mu.RLock()
@@ -72,10 +95,11 @@ func (m *Manager) FindAvailableRoom(preferredRole game.Role) *Room {
 	for _, r := range m.rooms {
 		r.mu.RLock()
 		isWaiting := r.State == game.StateWaiting
+		isPublic := r.Visibility == VisibilityPublic
 		hasSpace := len(r.Players) < game.MaxPlayers
 		canSelect := preferredRole == game.RoleNone || r.canSelectRole(preferredRole)
 		r.mu.RUnlock()
-		if isWaiting && hasSpace {
+		if isWaiting && isPublic && hasSpace {
 			available = append(available, r)
 			if canSelect {
 				preferred = append(preferred, r)
@@ -106,3 +130,36 @@ func (m *Manager) FindRoomByPlayerID(playerID string) *Room {
 	}
 	return nil
 }
+
+// CheckAFK applies the AFK policy to every room.
+func (m *Manager) CheckAFK(now time.Time, policy AFKPolicy) {
+	m.mu.RLock()
+	rooms := make([]*Room, 0, len(m.rooms))
+	for _, r := range m.rooms {
+		rooms = append(rooms, r)
+	}
+	m.mu.RUnlock()
+
+	for _, r := range rooms {
+		for _, removal := range r.CheckAFK(now, policy) {
+			if m.OnAFKRemoved != nil {
+				m.OnAFKRemoved(r, removal)
+			}
+		}
+	}
+}
+
+// MonitorAFK checks every room for AFK players each interval until ctx is done.
+func (m *Manager) MonitorAFK(ctx context.Context, policy AFKPolicy, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.CheckAFK(now, policy)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package room
+
+import "github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+
+// CrossesObstacle reports whether a straight move from (x1, y1) to (x2, y2)
+// passes through an obstacle on the room's map.
+func (r *Room) CrossesObstacle(x1, y1, x2, y2 float64) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return game.CrossesObstacle(x1, y1, x2, y2, r.MapObjects)
+}
+
+// SnappedOntoThief returns a free thief that a police move to (x, y) lands
+// squarely on top of from outside arrest range, or nil. Bodies cannot
+// overlap that much, so the move is a shortcut to an arrest rather than a chase.
+// The thief is judged where it stood when the police last moved: a thief that
+// ran into a lagging police closed the gap itself.
+func (r *Room) SnappedOntoThief(police *game.Player, x, y float64) *game.Player {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, p := range r.Players {
+		if p.Role != game.RoleThief || !p.IsFree() {
+			continue
+		}
+		prevX, prevY := p.PositionAt(police.LastMoveTime)
+		if game.Distance(x, y, p.X, p.Y) >= game.PlayerRadius/2 || game.Distance(x, y, prevX, prevY) >= game.PlayerRadius/2 {
+			continue
+		}
+		if game.InArrestRange(police, p) || game.Distance(police.X, police.Y, prevX, prevY) <= game.ArrestRange {
+			continue
+		}
+		return p
+	}
+	return nil
+}
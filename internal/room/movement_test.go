@@ -0,0 +1,57 @@
+package room
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+)
+
+func TestCrossesObstacle(t *testing.T) {
+	r := NewRoom("TEST")
+	r.MapObjects = []game.MapObject{{Type: "tree", X: 800, Y: 500}}
+
+	assert.True(t, r.CrossesObstacle(500, 500, 1000, 500))
+	assert.False(t, r.CrossesObstacle(500, 700, 1000, 700))
+}
+
+func TestSnappedOntoThief(t *testing.T) {
+	r := NewRoom("TEST")
+	police := &game.Player{ID: "p1", Role: game.RolePolice}
+	thief := &game.Player{ID: "p2", Role: game.RoleThief}
+	r.AddPlayer(police, mockClient("client1"))
+	r.AddPlayer(thief, mockClient("client2"))
+	police.SetPosition(500, 500)
+	thief.SetPosition(800, 500)
+
+	assert.Same(t, thief, r.SnappedOntoThief(police, 805, 500))
+	assert.Nil(t, r.SnappedOntoThief(police, 710, 500), "closing in is a chase")
+
+	police.SetPosition(760, 500)
+	assert.Nil(t, r.SnappedOntoThief(police, 805, 500), "already in arrest range")
+}
+
+func TestSnappedOntoThief_ThiefRunsIntoLaggingPolice(t *testing.T) {
+	r := NewRoom("TEST")
+	police := &game.Player{ID: "p1", Role: game.RolePolice}
+	thief := &game.Player{ID: "p2", Role: game.RoleThief}
+	r.AddPlayer(police, mockClient("client1"))
+	r.AddPlayer(thief, mockClient("client2"))
+
+	now := time.Now()
+	police.SetPosition(500, 500)
+	police.LastMoveTime = now.Add(-500 * time.Millisecond)
+	thief.SetPosition(900, 500)
+	for i := 1; i <= 10; i++ {
+		thief.Move(900-float64(i)*20, 500, police.LastMoveTime.Add(time.Duration(i)*50*time.Millisecond))
+	}
+
+	// Both closed half the gap while the police's moves were delayed
+	assert.Nil(t, r.SnappedOntoThief(police, 705, 500))
+
+	// A thief standing still since the police last moved is still a snap
+	thief.SetPosition(700, 500)
+	assert.Same(t, thief, r.SnappedOntoThief(police, 705, 500))
+}
@@ -0,0 +1,92 @@
+package room
+
+import (
+	"github.com/google/uuid"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+)
+
+// EvidenceEvents bounds how many recent gameplay events a room keeps
+// to attach to player reports.
+const EvidenceEvents = 30
+
+// Evidence is a bounded snapshot of recent match state attached to a report.
+type Evidence struct {
+	State  string            `json:"state"`
+	Target *game.PlayerStats `json:"target,omitempty"` // the reported player's match stats
+	Events []game.Event      `json:"events,omitempty"` // most recent last
+}
+
+// ReportContext identifies the match a player report refers to.
+type ReportContext struct {
+	RoomCode string
+	// MatchID identifies the current or most recent match, or the lobby
+	// session before the room's first match.
+	MatchID         string
+	State           game.RoomState
+	TargetAccountID string
+	TargetNickname  string
+	Evidence        Evidence
+}
+
+// ReportContext returns the context for reporting a player who is in the
+// room or left its current match. Returns false if the player is unknown.
+func (r *Room) ReportContext(playerID string) (ReportContext, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rc := ReportContext{
+		RoomCode: r.Code,
+		MatchID:  r.matchID,
+		State:    r.State,
+		Evidence: Evidence{
+			State:  r.State.String(),
+			Events: append([]game.Event(nil), r.recentEvents...),
+		},
+	}
+	if p, ok := r.Players[playerID]; ok {
+		rc.TargetAccountID = p.AccountID
+		rc.TargetNickname = p.Nickname
+	} else {
+		found := false
+		for _, l := range r.leavers {
+			if l.PlayerID == playerID {
+				rc.TargetAccountID = l.AccountID
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ReportContext{}, false
+		}
+	}
+	if r.stats != nil {
+		for _, s := range r.stats.Summary() {
+			if s.PlayerID == playerID {
+				stats := s
+				rc.Evidence.Target = &stats
+				if rc.TargetNickname == "" {
+					rc.TargetNickname = s.Nickname
+				}
+				break
+			}
+		}
+	}
+	return rc, true
+}
+
+// beginMatch starts a new match ID and clears the evidence of the last match.
+// Caller must hold r.mu.
+func (r *Room) beginMatch() {
+	r.matchID = uuid.NewString()
+	r.recentEvents = nil
+}
+
+// recordEvent keeps e as evidence, dropping the oldest event past
+// EvidenceEvents. Caller must hold r.mu.
+func (r *Room) recordEvent(e game.Event) {
+	if len(r.recentEvents) == EvidenceEvents {
+		copy(r.recentEvents, r.recentEvents[1:])
+		r.recentEvents = r.recentEvents[:EvidenceEvents-1]
+	}
+	r.recentEvents = append(r.recentEvents, e)
+}
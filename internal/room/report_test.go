@@ -0,0 +1,58 @@
+package room
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+)
+
+func TestReportContext(t *testing.T) {
+	r := NewRoom("TEST")
+	r.AddPlayer(&game.Player{ID: "p1", AccountID: "acc-1", Nickname: "P1", Role: game.RolePolice}, mockClient("client1"))
+	r.AddPlayer(&game.Player{ID: "p2", AccountID: "acc-2", Nickname: "P2", Role: game.RoleThief}, mockClient("client2"))
+
+	lobby, ok := r.ReportContext("p2")
+	require.True(t, ok)
+	assert.Equal(t, "acc-2", lobby.TargetAccountID)
+	assert.NotEmpty(t, lobby.MatchID)
+
+	_, ok = r.ReportContext("nobody")
+	assert.False(t, ok)
+
+	r.PrepareGame()
+	rc, ok := r.ReportContext("p2")
+	require.True(t, ok)
+	assert.NotEqual(t, lobby.MatchID, rc.MatchID, "each match gets its own ID")
+	require.NotNil(t, rc.Evidence.Target)
+	assert.Equal(t, "P2", rc.Evidence.Target.Nickname)
+}
+
+func TestReportContext_EvidenceIsBounded(t *testing.T) {
+	r := NewRoom("TEST")
+	r.AddPlayer(&game.Player{ID: "p1", Nickname: "P1"}, mockClient("client1"))
+	for i := 0; i < EvidenceEvents+5; i++ {
+		r.recordEvent(game.Event{Type: game.EventItemPickedUp, Elapsed: float64(i)})
+	}
+
+	rc, ok := r.ReportContext("p1")
+	require.True(t, ok)
+	require.Len(t, rc.Evidence.Events, EvidenceEvents)
+	assert.Equal(t, 5.0, rc.Evidence.Events[0].Elapsed, "oldest events are dropped first")
+	assert.Equal(t, float64(EvidenceEvents+4), rc.Evidence.Events[EvidenceEvents-1].Elapsed)
+}
+
+func TestReportContext_Leaver(t *testing.T) {
+	r := NewRoom("TEST")
+	r.AddPlayer(&game.Player{ID: "p1", AccountID: "acc-1", Nickname: "P1", Role: game.RolePolice}, mockClient("client1"))
+	r.AddPlayer(&game.Player{ID: "p2", AccountID: "acc-2", Nickname: "P2", Role: game.RoleThief}, mockClient("client2"))
+	r.PrepareGame()
+	r.RemovePlayer("p2")
+
+	rc, ok := r.ReportContext("p2")
+	require.True(t, ok, "players who left the match can still be reported")
+	assert.Equal(t, "acc-2", rc.TargetAccountID)
+	assert.Equal(t, "P2", rc.TargetNickname)
+}
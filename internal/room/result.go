@@ -0,0 +1,129 @@
+package room
+
+import "github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+
+// MatchResult summarizes a finished match for post-game consumers
+// such as achievements and leaderboards.
+type MatchResult struct {
+	RoomCode string
+	Winner   game.WinResult
+	Reason   game.EndReason // empty if the match was stopped outside the game rules
+	MVP      game.MVP
+	Players  []PlayerResult
+	Leavers  []Leaver
+}
+
+// PlayerResult is one participant's outcome within a MatchResult.
+type PlayerResult struct {
+	PlayerID  string
+	AccountID string // empty for players not bound to an account
+	Stats     game.PlayerStats
+	// Role is the role the player finished in; Stats.Role is the starting role.
+	// Infection converts thieves, so outcomes and teams are judged by Role.
+	Role game.Role
+	Won  bool
+	// LastFreeThief is set for the only thief still free when the match ended.
+	LastFreeThief bool
+	// Left is set for players who left mid-match. Leavers never win.
+	Left bool
+}
+
+// Leaver records a player who left during a match, for penalties.
+type Leaver struct {
+	PlayerID  string
+	AccountID string
+	Role      game.Role
+	Elapsed   float64 // seconds into the match
+}
+
+// recordLeaver remembers a player leaving the current match. Caller must hold r.mu.
+func (r *Room) recordLeaver(p *game.Player) {
+	l := Leaver{
+		PlayerID:  p.ID,
+		AccountID: p.AccountID,
+		Role:      p.Role,
+		Elapsed:   (game.GameDuration - r.remainingTime).Seconds(),
+	}
+	r.leavers = append(r.leavers, l)
+}
+
+// withoutLeavers filters leavers out of a stats summary. Caller must hold r.mu.
+func (r *Room) withoutLeavers(summary []game.PlayerStats) []game.PlayerStats {
+	if len(r.leavers) == 0 {
+		return summary
+	}
+	left := make(map[string]bool, len(r.leavers))
+	for _, l := range r.leavers {
+		left[l.PlayerID] = true
+	}
+	kept := make([]game.PlayerStats, 0, len(summary))
+	for _, s := range summary {
+		if !left[s.PlayerID] {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// buildMatchResult assembles the result of the current match. Caller must hold r.mu.
+func (r *Room) buildMatchResult(winner game.WinResult, summary []game.PlayerStats, mvp game.MVP) MatchResult {
+	var lastFree string
+	freeThieves := 0
+	for _, p := range r.Players {
+		if p.Role == game.RoleThief && !p.IsArrested() {
+			freeThieves++
+			lastFree = p.ID
+		}
+	}
+	if freeThieves != 1 {
+		lastFree = ""
+	}
+
+	result := MatchResult{
+		RoomCode: r.Code,
+		Winner:   winner,
+		MVP:      mvp,
+		Players:  make([]PlayerResult, 0, len(summary)),
+		Leavers:  append([]Leaver(nil), r.leavers...),
+	}
+	leavers := make(map[string]Leaver, len(r.leavers))
+	for _, l := range r.leavers {
+		leavers[l.PlayerID] = l
+	}
+	for _, s := range summary {
+		// Judge the outcome by the role a player finished in (infection converts thieves)
+		role := s.Role
+		accountID := ""
+		if p, ok := r.Players[s.PlayerID]; ok {
+			role = p.Role
+			accountID = p.AccountID
+		}
+		pr := PlayerResult{
+			PlayerID:      s.PlayerID,
+			AccountID:     accountID,
+			Stats:         s,
+			Role:          role,
+			Won:           wonAs(role, winner),
+			LastFreeThief: s.PlayerID == lastFree,
+		}
+		if l, ok := leavers[s.PlayerID]; ok {
+			pr.AccountID = l.AccountID
+			pr.Role = l.Role
+			pr.Won = false
+			pr.Left = true
+		}
+		result.Players = append(result.Players, pr)
+	}
+	return result
+}
+
+func wonAs(role game.Role, winner game.WinResult) bool {
+	switch winner {
+	case game.WinPolice:
+		return role == game.RolePolice
+	case game.WinThief:
+		return role == game.RoleThief
+	default:
+		return false
+	}
+}
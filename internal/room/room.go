@@ -5,17 +5,53 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
 )
 
 // Room represents a game room with players and state.
 type Room struct {
+	// ID identifies this room instance; codes are reused after a room is removed
+	ID      string                  `json:"-"`
 	Code    string                  `json:"code"`
 	State   game.RoomState          `json:"state"`
 	Players map[string]*game.Player `json:"players"`
 	HostID  string                  `json:"host_id"`
 
+	// Mode defines the win and arrest rules for the next match
+	Mode game.Mode `json:"-"`
+
+	// SeriesLength is the number of rounds in a best-of-N series (1 = single match)
+	SeriesLength int `json:"-"`
+
+	// AFKAction applies to players who go AFK during a match
+	AFKAction AFKAction `json:"-"`
+
+	// Visibility controls listing and joining; password guards VisibilityPassword rooms
+	Visibility Visibility `json:"-"`
+	password   string
+
+	// Roles chosen in the lobby, restored on Reset (modes and series change roles)
+	lobbyRoles map[string]game.Role
+
+	// Active series, nil between series
+	series *Series
+
+	// Pause between series rounds and the timer that starts the next one
+	intermission      time.Duration
+	intermissionTimer *time.Timer
+
+	// Pre-match countdown; countdownCh is closed to cancel it
+	countdownStep time.Duration
+	countdownCh   chan struct{}
+
+	// Players who have not yet reported client_loaded for the current match
+	loading map[string]bool
+
+	// Players who left during the current match
+	leavers []Leaver
+
 	// Client mapping: player ID -> ws client
 	clients map[string]*ws.Client
 
@@ -32,28 +68,105 @@ type Room struct {
 	stopCh        chan struct{}
 	remainingTime time.Duration
 
+	// Gameplay events emitted by the tick
+	events *game.EventBus
+
+	// Per-player match statistics
+	stats *game.StatsTracker
+
+	// Current match ID and its recent events, kept as report evidence
+	matchID      string
+	recentEvents []game.Event
+
+	// onGameOver is invoked after StopGame with the match result.
+	onGameOver func(MatchResult)
+
 	mu sync.RWMutex
 }
 
 // NewRoom creates a new room with the given code.
 func NewRoom(code string) *Room {
 	return &Room{
+		ID:      uuid.NewString(),
 		Code:    code,
 		State:   game.StateWaiting,
 		Players: make(map[string]*game.Player),
+		Mode:    game.ClassicMode{},
+
+		SeriesLength:  1,
+		AFKAction:     AFKKick,
+		Visibility:    VisibilityPublic,
+		intermission:  game.ResetDelay,
+		countdownStep: time.Second,
+
 		clients: make(map[string]*ws.Client),
+		events:  game.NewEventBus(),
+		matchID: uuid.NewString(),
 	}
 }
 
-// AddPlayer adds a player to the room. Returns false if the room is full.
+// SetMode changes the game mode. Returns false unless the room is waiting.
+func (r *Room) SetMode(mode game.Mode) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.State != game.StateWaiting {
+		return false
+	}
+	r.Mode = mode
+	return true
+}
+
+// ModeName returns the name of the room's game mode.
+func (r *Room) ModeName() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.Mode.Name()
+}
+
+// SetSeriesLength sets the number of rounds in the next series.
+// Returns false unless the room is waiting and n is a valid series length.
+func (r *Room) SetSeriesLength(n int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.State != game.StateWaiting || !ValidSeriesLength(n) {
+		return false
+	}
+	r.SeriesLength = n
+	return true
+}
+
+// GetSeriesLength returns the configured series length.
+func (r *Room) GetSeriesLength() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.SeriesLength
+}
+
+// InSeries reports whether a series is in progress, including intermissions.
+func (r *Room) InSeries() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.series != nil
+}
+
+// Events returns the room's gameplay event bus for in-process subscribers.
+func (r *Room) Events() *game.EventBus {
+	return r.events
+}
+
+// AddPlayer adds a player to the room. Returns false if the room is full
+// or the player's account is already in it.
 func (r *Room) AddPlayer(player *game.Player, client *ws.Client) bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if len(r.Players) >= game.MaxPlayers {
+	if len(r.Players) >= game.MaxPlayers || r.hasAccount(player.AccountID) {
 		return false
 	}
 
+	player.Touch(time.Now())
 	r.Players[player.ID] = player
 	r.clients[player.ID] = client
 
@@ -64,22 +177,87 @@ func (r *Room) AddPlayer(player *game.Player, client *ws.Client) bool {
 }
 
 // RemovePlayer removes a player from the room.
+// A departure during the countdown cancels it. During a match the player is
+// recorded as a leaver, and a team left without players forfeits.
+// Bots are dropped once no human is left.
 func (r *Room) RemovePlayer(playerID string) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
+
+	if p, ok := r.Players[playerID]; ok && r.State == game.StatePlaying {
+		r.recordLeaver(p)
+	}
 
 	delete(r.Players, playerID)
 	delete(r.clients, playerID)
+	delete(r.loading, playerID)
+
+	if r.humanCount() == 0 {
+		r.removeBots()
+	}
 
 	// Transfer host if the host left
-	if r.HostID == playerID && len(r.Players) > 0 {
-		for id := range r.Players {
+	if r.HostID == playerID {
+		r.transferHost()
+	}
+
+	cancelled := r.cancelCountdown()
+	forfeit := false
+	winner := game.WinNone
+	if r.State == game.StatePlaying {
+		winner, forfeit = game.CheckForfeit(r.playerList())
+	}
+	r.mu.Unlock()
+
+	if cancelled {
+		r.broadcastCountdownCancelled(playerID)
+	}
+	if forfeit {
+		slog.Info("team forfeited", "room", r.Code, "winner", winner.String(), "left", playerID)
+		r.endGame(winner, game.ReasonForfeit)
+	}
+}
+
+// HasAccount reports whether a player of the given account is in the room.
+func (r *Room) HasAccount(accountID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.hasAccount(accountID)
+}
+
+// hasAccount reports whether the account already has a player here, including
+// a bot that took over for it. Players without an account never match.
+// Caller must hold r.mu.
+func (r *Room) hasAccount(accountID string) bool {
+	if accountID == "" {
+		return false
+	}
+	for _, p := range r.Players {
+		if p.AccountID == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+// transferHost hands the host role to another human player. Caller must hold r.mu.
+func (r *Room) transferHost() {
+	for id, p := range r.Players {
+		if !p.Bot {
 			r.HostID = id
-			break
+			return
 		}
 	}
 }
 
+// playerList returns the room's players as a slice. Caller must hold r.mu.
+func (r *Room) playerList() []*game.Player {
+	players := make([]*game.Player, 0, len(r.Players))
+	for _, p := range r.Players {
+		players = append(players, p)
+	}
+	return players
+}
+
 // PlayerCount returns the number of players.
 func (r *Room) PlayerCount() int {
 	r.mu.RLock()
@@ -124,32 +302,53 @@ func (r *Room) canSelectRole(role game.Role) bool {
 
 // SetPlayerReady sets a player's ready status and returns whether all players are ready.
 // This must be used instead of setting Ready directly to avoid race conditions.
+// Un-readying during the countdown cancels it.
 func (r *Room) SetPlayerReady(playerID string, ready bool) bool {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if p, ok := r.Players[playerID]; ok {
-		p.Ready = ready
-	}
-
-	return r.allReady()
+	return r.updateReady(playerID, func(bool) bool { return ready })
 }
 
 // TogglePlayerReady toggles a player's ready status and returns whether all players are ready.
+// Un-readying during the countdown cancels it.
 func (r *Room) TogglePlayerReady(playerID string) bool {
+	return r.updateReady(playerID, func(ready bool) bool { return !ready })
+}
+
+func (r *Room) updateReady(playerID string, next func(bool) bool) bool {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 
+	cancelled := false
 	if p, ok := r.Players[playerID]; ok {
-		p.Ready = !p.Ready
+		p.Ready = next(p.Ready)
+		if !p.Ready {
+			cancelled = r.cancelCountdown()
+		}
 	}
+	allReady := r.allReady()
+	r.mu.Unlock()
 
-	return r.allReady()
+	if cancelled {
+		r.broadcastCountdownCancelled(playerID)
+	}
+	return allReady
 }
 
-// allReady checks if all players are ready and team composition is valid.
-// Caller must hold r.mu.
+// allReady checks if the room is waiting, all players are ready and team
+// composition is valid. Caller must hold r.mu.
 func (r *Room) allReady() bool {
+	if r.State != game.StateWaiting {
+		return false
+	}
+	for _, p := range r.Players {
+		if !p.Ready {
+			return false
+		}
+	}
+	return r.teamsValid()
+}
+
+// teamsValid checks that there are enough players, everyone has a role
+// and both teams are represented. Caller must hold r.mu.
+func (r *Room) teamsValid() bool {
 	if len(r.Players) < game.MinPlayers {
 		return false
 	}
@@ -157,14 +356,13 @@ func (r *Room) allReady() bool {
 	policeCount := 0
 	thiefCount := 0
 	for _, p := range r.Players {
-		if !p.Ready || p.Role == game.RoleNone {
-			return false
-		}
 		switch p.Role {
 		case game.RolePolice:
 			policeCount++
 		case game.RoleThief:
 			thiefCount++
+		default:
+			return false
 		}
 	}
 
@@ -182,6 +380,13 @@ func (r *Room) GetPlayerList() []*game.Player {
 	return players
 }
 
+// GetPlayer returns a player by ID, or nil if the player is not in the room.
+func (r *Room) GetPlayer(playerID string) *game.Player {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.Players[playerID]
+}
+
 // BroadcastMessage sends a message to all players in the room.
 func (r *Room) BroadcastMessage(msg ws.Message) {
 	r.mu.RLock()
@@ -212,32 +417,63 @@ func (r *Room) IsEmpty() bool {
 	return r.PlayerCount() == 0
 }
 
-// Reset resets the room state to waiting, preserving players and roles.
+// Reset returns an ended room to waiting, preserving players and lobby roles.
+// It does nothing in any other state.
 func (r *Room) Reset() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.State = game.StateWaiting
-	for _, p := range r.Players {
-		p.Reset()
+	if r.State != game.StateEnded {
+		return
+	}
+	if err := r.transition(game.StateWaiting); err != nil {
+		slog.Error("failed to reset room", "room", r.Code, "error", err)
 	}
 }
 
 // PrepareGame assigns spawn positions and transitions to playing state.
 // Must be called before broadcasting game_start so clients receive correct positions.
+// Skips the countdown and client_loaded handshake; StartRound is the normal path.
 func (r *Room) PrepareGame() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.State = game.StatePlaying
+	if err := r.transition(game.StatePlaying); err != nil {
+		slog.Error("failed to prepare game", "room", r.Code, "error", err)
+		return
+	}
+	r.prepareGame()
+}
+
+// prepareGame sets up the next match without changing the room state. Caller must hold r.mu.
+func (r *Room) prepareGame() {
 	r.remainingTime = game.GameDuration
+	r.loading = nil
+	r.leavers = nil
 	r.stopCh = make(chan struct{})
+	r.beginMatch()
+	r.touchAll()
 
-	// Generate and apply spawn positions
 	players := make([]*game.Player, 0, len(r.Players))
 	for _, p := range r.Players {
 		players = append(players, p)
 	}
+
+	// Remember lobby roles once per series; later rounds rotate them
+	if r.series == nil {
+		r.lobbyRoles = make(map[string]game.Role, len(r.Players))
+		for _, p := range r.Players {
+			r.lobbyRoles[p.ID] = p.Role
+		}
+		if r.SeriesLength > 1 {
+			r.series = NewSeries(r.SeriesLength)
+		}
+	}
+	if r.series != nil {
+		r.series.BeginRound(players)
+	}
+
+	// Generate and apply spawn positions
 	positions := game.GenerateSpawnPositions(players)
 	for id, pos := range positions {
 		r.Players[id].SetPosition(pos.X, pos.Y)
@@ -252,42 +488,160 @@ func (r *Room) PrepareGame() {
 	// Initialize stumble stones
 	r.stumbleStones = game.NewStumbleStoneManager(r.MapObjects)
 
-	slog.Info("game prepared", "room", r.Code, "players", len(r.Players), "objects", len(r.MapObjects))
+	// Start tracking match statistics from spawn positions
+	r.stats = game.NewStatsTracker(players)
+
+	slog.Info("game prepared", "room", r.Code, "mode", r.Mode.Name(), "players", len(r.Players), "objects", len(r.MapObjects))
+}
+
+// StartRound prepares the game, broadcasts game_start and runs the countdown.
+// The game loop starts when the countdown completes.
+func (r *Room) StartRound() {
+	r.mu.Lock()
+	cancel := r.beginCountdown()
+	if cancel == nil {
+		r.mu.Unlock()
+		return
+	}
+	msg := r.gameStartMessage()
+	r.mu.Unlock()
+
+	r.BroadcastMessage(msg)
+	go r.runCountdown(cancel)
+}
+
+// gameStartMessage builds the game_start message. Caller must hold r.mu.
+func (r *Room) gameStartMessage() ws.Message {
+	players := make([]*game.Player, 0, len(r.Players))
+	for _, p := range r.Players {
+		players = append(players, p)
+	}
+	start := gameStartMessage{
+		Mode:       r.Mode.Name(),
+		Players:    players,
+		MapObjects: r.MapObjects,
+		Countdown:  game.CountdownSeconds,
+	}
+	if r.series != nil {
+		status := r.series.Status(r.Players)
+		start.Series = &status
+	}
+	msg, _ := ws.NewMessage(ws.TypeGameStart, start)
+	return msg
+}
+
+// nextRound rotates roles and starts the next round of series s after an intermission.
+// It does nothing if the series was cancelled or has already moved on.
+func (r *Room) nextRound(s *Series, round int) {
+	r.mu.Lock()
+	if r.series != s || s.Round != round || r.State != game.StateEnded {
+		r.mu.Unlock()
+		return
+	}
+	r.intermissionTimer = nil
+
+	players := make([]*game.Player, 0, len(r.Players))
+	for _, p := range r.Players {
+		p.Reset()
+		players = append(players, p)
+	}
+	s.RotateRoles(players)
+
+	if !r.teamsValid() {
+		r.series = nil
+		r.mu.Unlock()
+
+		r.BroadcastMessage(ws.NewErrorMessage("인원이 부족해 시리즈가 중단되었습니다"))
+		slog.Info("series cancelled", "room", r.Code, "round", round, "players", len(players))
+		return
+	}
+
+	cancel := r.beginCountdown()
+	if cancel == nil {
+		r.mu.Unlock()
+		return
+	}
+	msg := r.gameStartMessage()
+	r.mu.Unlock()
+
+	r.BroadcastMessage(msg)
+	go r.runCountdown(cancel)
+	slog.Info("series round starting", "room", r.Code, "round", round+1, "length", s.Length)
 }
 
 // StartGameLoop starts the game tick loop. Must be called after PrepareGame and broadcasting game_start.
 func (r *Room) StartGameLoop() {
-	go r.gameLoop()
+	r.mu.RLock()
+	stopCh := r.stopCh
+	r.mu.RUnlock()
+	go r.gameLoop(stopCh)
 }
 
 // StopGame stops the game loop and transitions to ended state.
+// It is for stops outside the game rules; game_over carries no reason.
 func (r *Room) StopGame(result game.WinResult) {
+	r.endGame(result, "")
+}
+
+// endGame ends the match with the given winner and reason.
+func (r *Room) endGame(result game.WinResult, reason game.EndReason) {
 	r.mu.Lock()
 
-	if r.State != game.StatePlaying {
+	// Leaving playing closes stopCh, which stops the game loop
+	if err := r.transition(game.StateEnded); err != nil {
 		r.mu.Unlock()
 		return
 	}
 
-	r.State = game.StateEnded
+	var summary []game.PlayerStats
+	if r.stats != nil {
+		summary = r.stats.Summary()
+	}
+	mvp := game.SelectMVP(r.withoutLeavers(summary))
+	matchResult := r.buildMatchResult(result, summary, mvp)
+	matchResult.Reason = reason
+	onGameOver := r.onGameOver
 
-	// Signal the game loop to stop
-	select {
-	case <-r.stopCh:
-		// Already closed
-	default:
-		close(r.stopCh)
+	over := gameOverMessage{
+		Winner: result.String(),
+		Reason: reason,
+		Stats:  summary,
+		MVP:    mvp,
+	}
+	for _, l := range r.leavers {
+		over.Leavers = append(over.Leavers, l.PlayerID)
+	}
+	if s := r.series; s != nil {
+		s.RecordRound(result, game.GameDuration-r.remainingTime, matchResult.Players)
+		status := s.Status(r.Players)
+		over.Series = &status
+		switch {
+		case result == game.WinNone:
+			// Aborted round: the series cannot continue
+			r.series = nil
+		case status.Finished:
+			r.series = nil
+			slog.Info("series ended", "room", r.Code, "rounds", s.Round, "winners", status.Winners)
+		default:
+			round := s.Round
+			over.NextRoundIn = r.intermission.Seconds()
+			r.intermissionTimer = time.AfterFunc(r.intermission, func() {
+				r.nextRound(s, round)
+			})
+		}
 	}
 
 	r.mu.Unlock()
 
 	// Broadcast game over
-	msg, _ := ws.NewMessage(ws.TypeGameOver, gameOverMessage{
-		Winner: result.String(),
-	})
+	msg, _ := ws.NewMessage(ws.TypeGameOver, over)
 	r.BroadcastMessage(msg)
 
-	slog.Info("game ended", "room", r.Code, "winner", result.String())
+	slog.Info("game ended", "room", r.Code, "winner", result.String(), "reason", reason, "leavers", len(over.Leavers), "mvp_police", mvp.Police, "mvp_thief", mvp.Thief)
+
+	if onGameOver != nil {
+		onGameOver(matchResult)
+	}
 }
 
 // RemainingTime returns the remaining game time.
@@ -297,15 +651,29 @@ func (r *Room) RemainingTime() time.Duration {
 	return r.remainingTime
 }
 
+type gameStartMessage struct {
+	Mode       string           `json:"mode"`
+	Players    []*game.Player   `json:"players"`
+	MapObjects []game.MapObject `json:"map_objects"`
+	Countdown  int              `json:"countdown"` // seconds until play starts
+	Series     *SeriesStatus    `json:"series,omitempty"`
+}
+
 type gameOverMessage struct {
-	Winner string `json:"winner"`
+	Winner      string             `json:"winner"`
+	Reason      game.EndReason     `json:"reason,omitempty"`
+	Leavers     []string           `json:"leavers,omitempty"` // players who left mid-match
+	Stats       []game.PlayerStats `json:"stats"`
+	MVP         game.MVP           `json:"mvp"`
+	Series      *SeriesStatus      `json:"series,omitempty"`
+	NextRoundIn float64            `json:"next_round_in,omitempty"` // seconds until the next series round
 }
 
 type gameStateMessage struct {
-	RemainingTime float64               `json:"remaining_time"`
-	Players       []playerStateEntry    `json:"players"`
-	Boosters      []*game.Booster       `json:"boosters"`
-	StumbleStones []*game.StumbleStone  `json:"stumble_stones"`
+	RemainingTime float64              `json:"remaining_time"`
+	Players       []playerStateEntry   `json:"players"`
+	Boosters      []*game.Booster      `json:"boosters"`
+	StumbleStones []*game.StumbleStone `json:"stumble_stones"`
 }
 
 type playerStateEntry struct {
@@ -318,19 +686,26 @@ type playerStateEntry struct {
 	RescueGauge float64 `json:"rescue_gauge"`
 	Boosted     bool    `json:"boosted"`
 	Slowed      bool    `json:"slowed"`
+	Bot         bool    `json:"bot,omitempty"`
 }
 
-// gameLoop runs the game tick loop at TickRate frequency.
-func (r *Room) gameLoop() {
+// gameLoop runs the game tick loop at TickRate frequency until stopCh is closed.
+// The channel is passed in so a finished round's loop never observes the next round's.
+func (r *Room) gameLoop(stopCh chan struct{}) {
 	ticker := time.NewTicker(game.TickInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-r.stopCh:
+		case <-stopCh:
 			return
 		case <-ticker.C:
 			r.mu.Lock()
+			if r.stopCh != stopCh {
+				// A later round has started; this loop belongs to a finished one
+				r.mu.Unlock()
+				return
+			}
 			r.remainingTime -= game.TickInterval
 			timerExpired := r.remainingTime <= 0
 			dt := game.TickInterval.Seconds()
@@ -340,6 +715,25 @@ func (r *Room) gameLoop() {
 				playerList = append(playerList, p)
 			}
 
+			// --- Bots stand in for AFK players ---
+			now := time.Now()
+			for _, p := range playerList {
+				if p.Bot {
+					game.StepBot(p, playerList, r.MapObjects, dt, now)
+				}
+			}
+
+			var events []game.Event
+			emit := func(e game.Event) {
+				e.Room = r.Code
+				e.Elapsed = (game.GameDuration - r.remainingTime).Seconds()
+				events = append(events, e)
+				r.recordEvent(e)
+				if r.stats != nil {
+					r.stats.HandleEvent(e)
+				}
+			}
+
 			// --- Invincibility timer ---
 			for _, p := range playerList {
 				if p.IsInvincible() {
@@ -347,6 +741,7 @@ func (r *Room) gameLoop() {
 					if p.InvincibleTimer <= 0 {
 						p.State = game.StateFree
 						p.InvincibleTimer = 0
+						emit(game.Event{Type: game.EventInvincibilityEnded, ActorID: p.ID, X: p.X, Y: p.Y})
 					}
 				}
 			}
@@ -355,45 +750,59 @@ func (r *Room) gameLoop() {
 			game.UpdatePlayerBoosts(playerList, game.TickInterval)
 			if r.boosters != nil {
 				r.boosters.Update(game.TickInterval)
-				r.boosters.CheckPickup(playerList)
+				for _, pu := range r.boosters.CheckPickup(playerList) {
+					emit(game.Event{Type: game.EventItemPickedUp, ActorID: pu.PlayerID, Item: game.ItemBooster, ItemID: pu.ItemID, X: pu.X, Y: pu.Y})
+				}
 			}
 
 			// --- Stumble stone mechanics ---
 			game.UpdatePlayerSlows(playerList, game.TickInterval)
 			if r.stumbleStones != nil {
 				r.stumbleStones.Update(game.TickInterval)
-				r.stumbleStones.CheckPickup(playerList)
+				for _, pu := range r.stumbleStones.CheckPickup(playerList) {
+					emit(game.Event{Type: game.EventStoneTripped, ActorID: pu.PlayerID, Item: game.ItemStumbleStone, ItemID: pu.ItemID, X: pu.X, Y: pu.Y})
+				}
 			}
 
 			// --- Arrest mechanics (cumulative gauge) ---
 			arrestPairs := game.FindArrestPairs(playerList)
-			// Track which thieves are being chased this tick
-			chasedThieves := make(map[string]bool)
 			for _, pair := range arrestPairs {
-				thief := pair[1]
-				chasedThieves[thief.ID] = true
+				cop, thief := pair[0], pair[1]
+				if thief.Role != game.RoleThief || thief.IsArrested() {
+					// Already arrested (or infected) by another officer this tick
+					continue
+				}
 				thief.ArrestGauge += dt
 				if thief.ArrestGauge >= game.ArrestDuration {
-					thief.Arrest()
-					slog.Info("thief arrested", "thief", thief.ID, "room", r.Code)
+					eventType := r.Mode.OnArrest(thief)
+					emit(game.Event{Type: eventType, ActorID: cop.ID, TargetIDs: []string{thief.ID}, X: thief.X, Y: thief.Y})
+					slog.Info("thief arrested", "thief", thief.ID, "by", cop.ID, "mode", r.Mode.Name(), "room", r.Code)
 				}
 			}
 			// Gauge is cumulative — do NOT reset when out of range
 
 			// --- Rescue mechanics (continuous gauge) ---
-			rescueCandidates := game.FindJailRescueCandidates(playerList, game.JailX, game.JailY)
+			var rescueCandidates []*game.Player
+			if r.Mode.AllowsRescue() {
+				rescueCandidates = game.FindJailRescueCandidates(playerList, game.JailX, game.JailY)
+			}
 			rescuingThieves := make(map[string]bool)
 			for _, thief := range rescueCandidates {
 				rescuingThieves[thief.ID] = true
 				thief.RescueGauge += dt
 				if thief.RescueGauge >= game.RescueDuration {
 					// Release all arrested thieves
+					var released []string
 					for _, p := range playerList {
 						if p.Role == game.RoleThief && p.IsArrested() {
 							p.Release()
+							released = append(released, p.ID)
 							slog.Info("thief rescued", "thief", p.ID, "room", r.Code)
 						}
 					}
+					if len(released) > 0 {
+						emit(game.Event{Type: game.EventThievesRescued, ActorID: thief.ID, TargetIDs: released, X: thief.X, Y: thief.Y})
+					}
 					thief.RescueGauge = 0
 				}
 			}
@@ -404,6 +813,11 @@ func (r *Room) gameLoop() {
 				}
 			}
 
+			// --- Match statistics ---
+			if r.stats != nil {
+				r.stats.Tick(playerList, dt)
+			}
+
 			// Build game state snapshot (after processing mechanics)
 			players := make([]playerStateEntry, 0, len(r.Players))
 			for _, p := range playerList {
@@ -417,6 +831,7 @@ func (r *Room) gameLoop() {
 					RescueGauge: p.RescueGauge,
 					Boosted:     p.Boosted,
 					Slowed:      p.Slowed,
+					Bot:         p.Bot,
 				})
 			}
 
@@ -432,8 +847,11 @@ func (r *Room) gameLoop() {
 			if remaining < 0 {
 				remaining = 0
 			}
+			winner, reason, decided := r.Mode.CheckWin(playerList, timerExpired)
 			r.mu.Unlock()
 
+			r.publishEvents(events)
+
 			// Broadcast game state
 			msg, _ := ws.NewMessage(ws.TypeGameState, gameStateMessage{
 				RemainingTime: remaining,
@@ -444,14 +862,24 @@ func (r *Room) gameLoop() {
 			r.BroadcastMessage(msg)
 
 			// Check win conditions
-			if game.CheckPoliceWin(playerList) {
-				r.StopGame(game.WinPolice)
-				return
-			}
-			if timerExpired {
-				r.StopGame(game.WinThief)
+			if decided {
+				r.endGame(winner, reason)
 				return
 			}
 		}
 	}
 }
+
+// publishEvents broadcasts tick events to clients and in-process subscribers.
+// Must be called without holding r.mu.
+func (r *Room) publishEvents(events []game.Event) {
+	for _, e := range events {
+		msg, err := ws.NewMessage(string(e.Type), e)
+		if err != nil {
+			slog.Error("failed to encode game event", "type", e.Type, "error", err)
+			continue
+		}
+		r.BroadcastMessage(msg)
+		r.events.Publish(e)
+	}
+}
@@ -0,0 +1,213 @@
+package room
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+)
+
+// MaxSeriesLength is the longest best-of-N series a host can configure.
+const MaxSeriesLength = 7
+
+// ValidSeriesLength reports whether n is a supported series length (odd, 1..MaxSeriesLength).
+// A length of 1 is a single match.
+func ValidSeriesLength(n int) bool {
+	return n >= 1 && n <= MaxSeriesLength && n%2 == 1
+}
+
+// Series tracks a best-of-N series of rounds with rotating roles.
+// Players earn a round win when their side wins; ties on wins are broken
+// by the average time the player needed to capture every thief as police
+// (rounds the thieves survived count as the full GameDuration).
+type Series struct {
+	Length int
+	Round  int // 1-based index of the current or last played round
+
+	wins         map[string]int
+	policeRounds map[string]int
+	captureTime  map[string]time.Duration // summed over police rounds
+	roles        map[string]game.Role     // roles at the start of the current round
+}
+
+// SeriesScore is one player's standing within a series.
+type SeriesScore struct {
+	PlayerID     string  `json:"player_id"`
+	Wins         int     `json:"wins"`
+	PoliceRounds int     `json:"police_rounds"`
+	CaptureTime  float64 `json:"capture_time"` // average seconds to capture as police, 0 if never police
+}
+
+// SeriesStatus is the series progress announced in game_start and game_over.
+type SeriesStatus struct {
+	Length   int           `json:"length"`
+	Round    int           `json:"round"`
+	Scores   []SeriesScore `json:"scores"`
+	Finished bool          `json:"finished"`
+	Winners  []string      `json:"winners,omitempty"`
+}
+
+// NewSeries creates a series of the given length.
+func NewSeries(length int) *Series {
+	return &Series{
+		Length:       length,
+		wins:         make(map[string]int),
+		policeRounds: make(map[string]int),
+		captureTime:  make(map[string]time.Duration),
+	}
+}
+
+// BeginRound advances the round counter and records each player's starting role.
+func (s *Series) BeginRound(players []*game.Player) {
+	s.Round++
+	s.roles = make(map[string]game.Role, len(players))
+	for _, p := range players {
+		s.roles[p.ID] = p.Role
+	}
+}
+
+// RecordRound applies a finished round. elapsed is how long the round lasted.
+func (s *Series) RecordRound(winner game.WinResult, elapsed time.Duration, players []PlayerResult) {
+	for _, pr := range players {
+		if pr.Won {
+			s.wins[pr.PlayerID]++
+		}
+	}
+
+	capture := game.GameDuration
+	if winner == game.WinPolice {
+		capture = elapsed
+	}
+	for id, role := range s.roles {
+		if role != game.RolePolice {
+			continue
+		}
+		s.policeRounds[id]++
+		s.captureTime[id] += capture
+	}
+}
+
+// Status returns the series standings for the given players.
+func (s *Series) Status(players map[string]*game.Player) SeriesStatus {
+	status := SeriesStatus{
+		Length: s.Length,
+		Round:  s.Round,
+		Scores: make([]SeriesScore, 0, len(players)),
+	}
+	for id := range players {
+		score := SeriesScore{
+			PlayerID:     id,
+			Wins:         s.wins[id],
+			PoliceRounds: s.policeRounds[id],
+		}
+		if score.PoliceRounds > 0 {
+			score.CaptureTime = (s.captureTime[id] / time.Duration(score.PoliceRounds)).Seconds()
+		}
+		status.Scores = append(status.Scores, score)
+	}
+	sort.Slice(status.Scores, func(i, j int) bool {
+		return rankBefore(status.Scores[i], status.Scores[j])
+	})
+
+	status.Finished = s.Round >= s.Length || s.clinched(status.Scores)
+	if status.Finished && len(status.Scores) > 0 {
+		best := status.Scores[0]
+		for _, sc := range status.Scores {
+			if sc.Wins != best.Wins || captureKey(sc) != captureKey(best) {
+				break
+			}
+			status.Winners = append(status.Winners, sc.PlayerID)
+		}
+	}
+	return status
+}
+
+// clinched reports whether the leader can no longer be caught on wins.
+// scores must be sorted by rank.
+func (s *Series) clinched(scores []SeriesScore) bool {
+	if len(scores) < 2 {
+		return false
+	}
+	remaining := s.Length - s.Round
+	leader := scores[0].Wins
+	for _, sc := range scores[1:] {
+		if sc.Wins == leader {
+			// Teammates share the lead; compare against the first rival below them
+			continue
+		}
+		return leader > sc.Wins+remaining
+	}
+	return false
+}
+
+// rankBefore orders scores by wins, then faster average capture, then player ID.
+// Players who were never police rank as if every capture took GameDuration.
+func rankBefore(a, b SeriesScore) bool {
+	if a.Wins != b.Wins {
+		return a.Wins > b.Wins
+	}
+	ca, cb := captureKey(a), captureKey(b)
+	if ca != cb {
+		return ca < cb
+	}
+	return a.PlayerID < b.PlayerID
+}
+
+func captureKey(s SeriesScore) float64 {
+	if s.PoliceRounds == 0 {
+		return game.GameDuration.Seconds()
+	}
+	return s.CaptureTime
+}
+
+// RotateRoles swaps sides for the next round: current police become thieves
+// and the thieves who have been police the fewest times take the police slots.
+// The number of police stays the same where possible, capped by MaxPolice and
+// leaving at least one thief. Players without a role join the thieves.
+func (s *Series) RotateRoles(players []*game.Player) {
+	// Rotate from the roles the round started with (modes may change roles in-game)
+	for _, p := range players {
+		if role, ok := s.roles[p.ID]; ok {
+			p.SetRole(role)
+		}
+	}
+
+	var police, candidates []*game.Player
+	for _, p := range players {
+		if p.Role == game.RolePolice {
+			police = append(police, p)
+		} else {
+			candidates = append(candidates, p)
+		}
+	}
+
+	slots := len(police)
+	if slots > game.MaxPolice {
+		slots = game.MaxPolice
+	}
+	if slots > len(candidates) {
+		slots = len(candidates)
+	}
+	if slots == 0 && len(candidates) > 1 {
+		slots = 1
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		pi, pj := s.policeRounds[candidates[i].ID], s.policeRounds[candidates[j].ID]
+		if pi != pj {
+			return pi < pj
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+
+	for _, p := range police {
+		p.SetRole(game.RoleThief)
+	}
+	for i, p := range candidates {
+		if i < slots {
+			p.SetRole(game.RolePolice)
+		} else {
+			p.SetRole(game.RoleThief)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package room
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/game"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+func TestValidSeriesLength(t *testing.T) {
+	for _, n := range []int{1, 3, 5, 7} {
+		assert.True(t, ValidSeriesLength(n), "%d should be valid", n)
+	}
+	for _, n := range []int{0, 2, 4, 9, -1} {
+		assert.False(t, ValidSeriesLength(n), "%d should be invalid", n)
+	}
+}
+
+func TestSeries_RotateRoles(t *testing.T) {
+	tests := []struct {
+		name       string
+		roles      map[string]game.Role
+		wantPolice int
+	}{
+		{"one on one swaps", map[string]game.Role{"a": game.RolePolice, "b": game.RoleThief}, 1},
+		{"keeps police count", map[string]game.Role{"a": game.RolePolice, "b": game.RolePolice, "c": game.RoleThief, "d": game.RoleThief, "e": game.RoleThief}, 2},
+		{"leaves a thief", map[string]game.Role{"a": game.RolePolice, "b": game.RolePolice, "c": game.RoleThief}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var players []*game.Player
+			for id, role := range tt.roles {
+				players = append(players, &game.Player{ID: id, Role: role})
+			}
+			s := NewSeries(3)
+			s.BeginRound(players)
+			s.RotateRoles(players)
+
+			police := 0
+			for _, p := range players {
+				if p.Role == game.RolePolice {
+					police++
+					assert.Equal(t, game.RoleThief, tt.roles[p.ID], "previous police %s should not stay police", p.ID)
+				}
+			}
+			assert.Equal(t, tt.wantPolice, police)
+			assert.LessOrEqual(t, police, game.MaxPolice)
+		})
+	}
+}
+
+func TestSeries_RotateRolesPrefersFewestPoliceRounds(t *testing.T) {
+	a := &game.Player{ID: "a", Role: game.RolePolice}
+	b := &game.Player{ID: "b", Role: game.RoleThief}
+	c := &game.Player{ID: "c", Role: game.RoleThief}
+	players := []*game.Player{a, b, c}
+	s := NewSeries(5)
+
+	// Round 1: a is police, then b (lowest ID among never-police)
+	s.BeginRound(players)
+	s.RecordRound(game.WinThief, game.GameDuration, nil)
+	s.RotateRoles(players)
+	assert.Equal(t, game.RolePolice, b.Role)
+
+	// Round 2: b is police, then c has still never been police
+	s.BeginRound(players)
+	s.RecordRound(game.WinThief, game.GameDuration, nil)
+	s.RotateRoles(players)
+	assert.Equal(t, game.RolePolice, c.Role)
+	assert.Equal(t, game.RoleThief, a.Role)
+	assert.Equal(t, game.RoleThief, b.Role)
+}
+
+func TestSeries_RotateRolesUndoesInfection(t *testing.T) {
+	cop := &game.Player{ID: "a", Role: game.RolePolice}
+	thief := &game.Player{ID: "b", Role: game.RoleThief}
+	players := []*game.Player{cop, thief}
+	s := NewSeries(3)
+	s.BeginRound(players)
+
+	thief.Infect()
+	s.RotateRoles(players)
+
+	assert.Equal(t, game.RoleThief, cop.Role)
+	assert.Equal(t, game.RolePolice, thief.Role)
+}
+
+func TestSeries_StatusTiebreakOnCaptureTime(t *testing.T) {
+	a := &game.Player{ID: "a", Role: game.RolePolice}
+	b := &game.Player{ID: "b", Role: game.RoleThief}
+	players := map[string]*game.Player{"a": a, "b": b}
+	s := NewSeries(3)
+
+	// Round 1: a captures b in 60s
+	s.BeginRound([]*game.Player{a, b})
+	s.RecordRound(game.WinPolice, 60*time.Second, []PlayerResult{{PlayerID: "a", Won: true}, {PlayerID: "b"}})
+	assert.False(t, s.Status(players).Finished)
+
+	// Round 2: b captures a in 90s
+	a.Role, b.Role = game.RoleThief, game.RolePolice
+	s.BeginRound([]*game.Player{a, b})
+	s.RecordRound(game.WinPolice, 90*time.Second, []PlayerResult{{PlayerID: "a"}, {PlayerID: "b", Won: true}})
+
+	// Round 3: a survives as thief, so b's police capture time is the full duration
+	a.Role, b.Role = game.RolePolice, game.RoleThief
+	s.BeginRound([]*game.Player{a, b})
+	s.RecordRound(game.WinThief, game.GameDuration, []PlayerResult{{PlayerID: "a"}, {PlayerID: "b", Won: true}})
+
+	status := s.Status(players)
+	require.True(t, status.Finished)
+	assert.Equal(t, []string{"b"}, status.Winners)
+	require.Len(t, status.Scores, 2)
+	assert.Equal(t, "b", status.Scores[0].PlayerID)
+	assert.Equal(t, 2, status.Scores[0].Wins)
+	assert.Equal(t, 1, status.Scores[1].Wins)
+	assert.InDelta(t, 120.0, status.Scores[1].CaptureTime, 0.001) // (60 + 180) / 2
+}
+
+func TestSeries_StatusClinchedEarly(t *testing.T) {
+	a := &game.Player{ID: "a", Role: game.RolePolice}
+	b := &game.Player{ID: "b", Role: game.RoleThief}
+	players := map[string]*game.Player{"a": a, "b": b}
+	s := NewSeries(3)
+
+	for i := 0; i < 2; i++ {
+		s.BeginRound([]*game.Player{a, b})
+		s.RecordRound(game.WinPolice, 30*time.Second, []PlayerResult{{PlayerID: "a", Won: true}, {PlayerID: "b"}})
+		a.Role, b.Role = b.Role, a.Role
+	}
+
+	status := s.Status(players)
+	assert.Equal(t, 2, status.Round)
+	assert.True(t, status.Finished, "2-0 in a best-of-3 cannot be caught")
+	assert.Equal(t, []string{"a"}, status.Winners)
+}
+
+func TestRoom_SeriesRotatesRolesBetweenRounds(t *testing.T) {
+	r, clients := setupTestRoom()
+	require.True(t, r.SetSeriesLength(3))
+	r.intermission = 10 * time.Millisecond
+	r.countdownStep = time.Millisecond
+
+	r.StartRound()
+	waitForState(t, r, game.StatePlaying)
+	r.StopGame(game.WinPolice)
+	assert.True(t, r.InSeries())
+
+	msgs := drainMessages(clients[0])
+	over := findMessageByType(msgs, ws.TypeGameOver)
+	require.NotNil(t, over)
+	assert.Contains(t, string(over.Data), `"next_round_in"`)
+
+	waitForState(t, r, game.StatePlaying)
+
+	r.mu.RLock()
+	assert.Equal(t, game.RoleThief, r.Players["p1"].Role)
+	assert.Equal(t, game.RolePolice, r.Players["p2"].Role)
+	assert.Equal(t, 2, r.series.Round)
+	r.mu.RUnlock()
+
+	// p1 wins again as thief: 2-0 ends the series early
+	r.StopGame(game.WinThief)
+	assert.False(t, r.InSeries())
+
+	// Back in the lobby everyone gets their chosen roles again
+	r.Reset()
+	assert.Equal(t, game.RolePolice, r.Players["p1"].Role)
+	assert.Equal(t, game.RoleThief, r.Players["p2"].Role)
+}
+
+func TestRoom_SeriesCancelledWhenTeamEmpties(t *testing.T) {
+	r, clients := setupTestRoom()
+	require.True(t, r.SetSeriesLength(3))
+	r.intermission = 10 * time.Millisecond
+	r.countdownStep = time.Millisecond
+
+	r.StartRound()
+	waitForState(t, r, game.StatePlaying)
+	r.StopGame(game.WinPolice)
+	r.RemovePlayer("p2")
+
+	require.Eventually(t, func() bool { return !r.InSeries() }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, game.StateEnded, r.State)
+	msgs := drainMessages(clients[0])
+	assert.NotNil(t, findMessageByType(msgs, ws.TypeError))
+}
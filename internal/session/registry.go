@@ -0,0 +1,60 @@
+package session
+
+import (
+	"sync"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+// Registry maps account IDs to their single active client.
+// A newer session for the same account replaces the older one.
+type Registry struct {
+	clients map[string]*ws.Client // account ID -> active client
+	mu      sync.RWMutex
+}
+
+// NewRegistry creates an empty session registry.
+func NewRegistry() *Registry {
+	return &Registry{clients: make(map[string]*ws.Client)}
+}
+
+// Register makes client the active session of its account.
+// Returns the session it replaced, or nil if there was none.
+func (r *Registry) Register(client *ws.Client) *ws.Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev := r.clients[client.AccountID]
+	r.clients[client.AccountID] = client
+	if prev == client {
+		return nil
+	}
+	return prev
+}
+
+// Unregister removes client if it is still its account's active session.
+// Returns false if the session was already replaced or never registered.
+func (r *Registry) Unregister(client *ws.Client) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.clients[client.AccountID] != client {
+		return false
+	}
+	delete(r.clients, client.AccountID)
+	return true
+}
+
+// Get returns the active client of an account, or nil if offline.
+func (r *Registry) Get(accountID string) *ws.Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.clients[accountID]
+}
+
+// Count returns the number of active sessions.
+func (r *Registry) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.clients)
+}
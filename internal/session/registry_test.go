@@ -0,0 +1,27 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/ws"
+)
+
+func TestRegistry_NewestSessionWins(t *testing.T) {
+	reg := NewRegistry()
+	first := &ws.Client{ID: "c1", AccountID: "acc"}
+	second := &ws.Client{ID: "c2", AccountID: "acc"}
+
+	assert.Nil(t, reg.Register(first))
+	assert.Nil(t, reg.Register(first), "re-registering is not a replacement")
+	assert.Same(t, first, reg.Register(second))
+	assert.Same(t, second, reg.Get("acc"))
+
+	assert.False(t, reg.Unregister(first), "replaced session does not remove its successor")
+	assert.Same(t, second, reg.Get("acc"))
+
+	assert.True(t, reg.Unregister(second))
+	assert.Nil(t, reg.Get("acc"))
+	assert.Equal(t, 0, reg.Count())
+}
@@ -0,0 +1,126 @@
+package social
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FriendStatus is the state of a friendship between two accounts.
+type FriendStatus string
+
+const (
+	FriendPending  FriendStatus = "pending"  // request sent, not yet accepted
+	FriendAccepted FriendStatus = "accepted" // mutual friends
+)
+
+// MaxFriends caps friends plus outstanding requests per account.
+const MaxFriends = 100
+
+// InviteTTL is how long a room invite stays valid.
+const InviteTTL = 60 * time.Second
+
+var (
+	ErrSelfRequest      = errors.New("cannot befriend yourself")
+	ErrAlreadyFriends   = errors.New("already friends")
+	ErrAlreadyRequested = errors.New("friend request already sent")
+	ErrRequestNotFound  = errors.New("friend request not found")
+	ErrNotFriends       = errors.New("not friends")
+	ErrTooManyFriends   = errors.New("friend list is full")
+	ErrInviteNotFound   = errors.New("invite not found")
+	ErrInviteExpired    = errors.New("invite expired")
+)
+
+// PresenceStatus describes where an account currently is.
+type PresenceStatus string
+
+const (
+	PresenceOffline PresenceStatus = "offline"
+	PresenceOnline  PresenceStatus = "online"  // connected, not in a room
+	PresenceLobby   PresenceStatus = "lobby"   // in a room that is waiting
+	PresenceInGame  PresenceStatus = "in_game" // in a room with a match under way
+)
+
+// Presence is an account's current status, derived from its connection.
+type Presence struct {
+	Status   PresenceStatus `json:"status"`
+	RoomCode string         `json:"room_code,omitempty"`
+}
+
+// Friend is one entry in an account's friend list.
+// Incoming is set for pending requests the account has received.
+type Friend struct {
+	AccountID string       `json:"account_id"`
+	Nickname  string       `json:"nickname"`
+	Status    FriendStatus `json:"status"`
+	Incoming  bool         `json:"incoming,omitempty"`
+	Presence  *Presence    `json:"presence,omitempty"` // accepted friends only
+}
+
+// Invite asks an account to join the inviter's room.
+type Invite struct {
+	ID            string    `json:"invite_id"`
+	FromAccountID string    `json:"from_account_id"`
+	FromNickname  string    `json:"from_nickname"`
+	ToAccountID   string    `json:"-"`
+	RoomCode      string    `json:"room_code"`
+	RoomID        string    `json:"-"` // the room instance, since codes are reused
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// InviteBook holds outstanding room invites in memory.
+type InviteBook struct {
+	invites map[string]Invite
+	ttl     time.Duration
+	mu      sync.Mutex
+}
+
+// NewInviteBook creates an invite book whose invites expire after ttl.
+func NewInviteBook(ttl time.Duration) *InviteBook {
+	return &InviteBook{
+		invites: make(map[string]Invite),
+		ttl:     ttl,
+	}
+}
+
+// Add records an invite, replacing any earlier invite between the same accounts.
+func (b *InviteBook) Add(from, fromNickname, to, roomCode, roomID string, now time.Time) Invite {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for id, inv := range b.invites {
+		if !now.Before(inv.ExpiresAt) || (inv.FromAccountID == from && inv.ToAccountID == to) {
+			delete(b.invites, id)
+		}
+	}
+
+	inv := Invite{
+		ID:            uuid.New().String(),
+		FromAccountID: from,
+		FromNickname:  fromNickname,
+		ToAccountID:   to,
+		RoomCode:      roomCode,
+		RoomID:        roomID,
+		ExpiresAt:     now.Add(b.ttl),
+	}
+	b.invites[inv.ID] = inv
+	return inv
+}
+
+// Take removes and returns the invite with the given ID if it was sent to accountID.
+func (b *InviteBook) Take(id, accountID string, now time.Time) (Invite, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	inv, ok := b.invites[id]
+	if !ok || inv.ToAccountID != accountID {
+		return Invite{}, ErrInviteNotFound
+	}
+	delete(b.invites, id)
+	if !now.Before(inv.ExpiresAt) {
+		return Invite{}, ErrInviteExpired
+	}
+	return inv, nil
+}
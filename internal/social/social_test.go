@@ -0,0 +1,49 @@
+package social
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInviteBook_TakeOnce(t *testing.T) {
+	b := NewInviteBook(time.Minute)
+	now := time.Now()
+	inv := b.Add("a", "Alice", "b", "ABCD", "room-ABCD", now)
+
+	_, err := b.Take(inv.ID, "c", now)
+	assert.ErrorIs(t, err, ErrInviteNotFound, "only the recipient may take an invite")
+
+	got, err := b.Take(inv.ID, "b", now)
+	require.NoError(t, err)
+	assert.Equal(t, "ABCD", got.RoomCode)
+	assert.Equal(t, "Alice", got.FromNickname)
+
+	_, err = b.Take(inv.ID, "b", now)
+	assert.ErrorIs(t, err, ErrInviteNotFound)
+}
+
+func TestInviteBook_Expires(t *testing.T) {
+	b := NewInviteBook(time.Minute)
+	now := time.Now()
+	inv := b.Add("a", "Alice", "b", "ABCD", "room-ABCD", now)
+
+	_, err := b.Take(inv.ID, "b", now.Add(time.Minute))
+	assert.ErrorIs(t, err, ErrInviteExpired)
+}
+
+func TestInviteBook_ReplacesEarlierInvite(t *testing.T) {
+	b := NewInviteBook(time.Minute)
+	now := time.Now()
+	first := b.Add("a", "Alice", "b", "ABCD", "room-ABCD", now)
+	second := b.Add("a", "Alice", "b", "EFGH", "room-EFGH", now)
+
+	_, err := b.Take(first.ID, "b", now)
+	assert.ErrorIs(t, err, ErrInviteNotFound)
+
+	got, err := b.Take(second.ID, "b", now)
+	require.NoError(t, err)
+	assert.Equal(t, "EFGH", got.RoomCode)
+}
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/account"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+)
+
+// identitySchema links accounts to external identities. Accounts created
+// before the table existed are backfilled from their Game Center ID.
+const identitySchema = `
+CREATE TABLE IF NOT EXISTS account_identities (
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    account_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, subject)
+);
+CREATE INDEX IF NOT EXISTS idx_account_identities_account ON account_identities(account_id);
+
+INSERT INTO account_identities (provider, subject, account_id)
+SELECT 'game_center', game_center_id, id FROM accounts WHERE game_center_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+`
+
+// FindByIdentity looks up the account linked to an external identity.
+func (s *PostgresStore) FindByIdentity(ctx context.Context, provider, subject string) (*account.Account, error) {
+	row := s.pool.QueryRow(ctx,
+		`SELECT a.id, a.game_center_id, a.nickname, a.is_guest, a.created_at, a.last_login_at, a.nickname_changed_at
+		 FROM account_identities i JOIN accounts a ON a.id = i.account_id
+		 WHERE i.provider = $1 AND i.subject = $2`, provider, subject)
+
+	acc, err := scanAccount(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return acc, err
+}
+
+// CreateWithIdentity inserts a new account linked to an external identity.
+func (s *PostgresStore) CreateWithIdentity(ctx context.Context, acc *account.Account, provider, subject string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO accounts (id, game_center_id, nickname, is_guest, created_at, last_login_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		acc.ID, acc.GameCenterID, acc.Nickname, acc.IsGuest, acc.CreatedAt, acc.LastLoginAt); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO account_identities (provider, subject, account_id) VALUES ($1, $2, $3)`,
+		provider, subject, acc.ID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// LinkIdentity links an external identity to an existing account and marks
+// it as a full account. A Game Center identity also sets game_center_id, and
+// the account's guest credential stops working.
+func (s *PostgresStore) LinkIdentity(ctx context.Context, accountID, provider, subject string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO account_identities (provider, subject, account_id) VALUES ($1, $2, $3)`,
+		provider, subject, accountID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM account_identities WHERE account_id = $1 AND provider = 'guest'`, accountID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE accounts SET is_guest = false,
+		     game_center_id = CASE WHEN $2 = 'game_center' THEN $3 ELSE game_center_id END
+		 WHERE id = $1`, accountID, provider, subject); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// MergeAccounts moves the progress of fromID into intoID and deletes fromID.
+// Achievements keep the furthest progress and earliest unlock, season
+// standings add up with the busier account's rating, archived seasons and
+// friendships are kept where intoID has none. Bans, reports and flags move to intoID
+// so moderation history survives the merge, which is itself audited.
+func (s *PostgresStore) MergeAccounts(ctx context.Context, fromID, intoID string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	stmts := []string{
+		`INSERT INTO achievement_progress (account_id, achievement_id, progress, unlocked_at, updated_at)
+		 SELECT $2, achievement_id, progress, unlocked_at, updated_at FROM achievement_progress WHERE account_id = $1
+		 ON CONFLICT (account_id, achievement_id) DO UPDATE SET
+		     progress = GREATEST(achievement_progress.progress, EXCLUDED.progress),
+		     unlocked_at = LEAST(achievement_progress.unlocked_at, EXCLUDED.unlocked_at),
+		     updated_at = NOW()`,
+		`INSERT INTO standings (season, account_id, games, wins, arrests, rescues, rating, updated_at)
+		 SELECT season, $2, games, wins, arrests, rescues, rating, updated_at FROM standings WHERE account_id = $1
+		 ON CONFLICT (season, account_id) DO UPDATE SET
+		     games = standings.games + EXCLUDED.games,
+		     wins = standings.wins + EXCLUDED.wins,
+		     arrests = standings.arrests + EXCLUDED.arrests,
+		     rescues = standings.rescues + EXCLUDED.rescues,
+		     rating = CASE WHEN EXCLUDED.games > standings.games THEN EXCLUDED.rating ELSE standings.rating END,
+		     updated_at = NOW()`,
+		`INSERT INTO season_archive (season, account_id, final_rank, games, wins, arrests, rescues, rating, archived_at)
+		 SELECT season, $2, final_rank, games, wins, arrests, rescues, rating, archived_at FROM season_archive WHERE account_id = $1
+		 ON CONFLICT DO NOTHING`,
+		`INSERT INTO friendships (requester_id, addressee_id, status, created_at, accepted_at)
+		 SELECT CASE WHEN requester_id = $1 THEN $2 ELSE requester_id END,
+		        CASE WHEN addressee_id = $1 THEN $2 ELSE addressee_id END,
+		        status, created_at, accepted_at
+		 FROM friendships
+		 WHERE (requester_id = $1 OR addressee_id = $1) AND requester_id <> $2 AND addressee_id <> $2
+		 ON CONFLICT DO NOTHING`,
+		`UPDATE bans SET account_id = $2 WHERE account_id = $1`,
+		`UPDATE account_flags SET account_id = $2 WHERE account_id = $1`,
+		// Reports between the two accounts, and duplicates of intoID's own, are dropped
+		`UPDATE reports SET reporter_id = $2
+		 WHERE reporter_id = $1 AND target_id <> $2
+		   AND NOT EXISTS (SELECT 1 FROM reports r
+		                   WHERE r.reporter_id = $2 AND r.target_id = reports.target_id AND r.match_id = reports.match_id)`,
+		`UPDATE reports SET target_id = $2
+		 WHERE target_id = $1 AND reporter_id <> $2
+		   AND NOT EXISTS (SELECT 1 FROM reports r
+		                   WHERE r.reporter_id = reports.reporter_id AND r.target_id = $2 AND r.match_id = reports.match_id)`,
+		`DELETE FROM accounts WHERE id = $1`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(ctx, stmt, fromID, intoID); err != nil {
+			return err
+		}
+	}
+	entry := moderation.NewAuditEntry(fromID, moderation.ActionMergeAccounts, intoID, map[string]string{"from": fromID})
+	if err := insertAudit(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/account"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/achievement"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/leaderboard"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+)
+
+func TestPostgresStore_Identity(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	found, err := s.FindByIdentity(ctx, "apple", "001234.abc")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	acc := account.NewAccount("사과")
+	require.NoError(t, s.CreateWithIdentity(ctx, acc, "apple", "001234.abc"))
+
+	found, err = s.FindByIdentity(ctx, "apple", "001234.abc")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, acc.ID, found.ID)
+
+	// The same identity cannot be linked twice
+	assert.Error(t, s.CreateWithIdentity(ctx, account.NewAccount("다른"), "apple", "001234.abc"))
+
+	found, err = s.FindByIdentity(ctx, "game_center", "001234.abc")
+	require.NoError(t, err)
+	assert.Nil(t, found, "identities are scoped by provider")
+}
+
+func TestPostgresStore_LinkIdentity(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	guest := account.NewGuestAccount("손님")
+	require.NoError(t, s.CreateWithIdentity(ctx, guest, "guest", guest.ID))
+	require.NoError(t, s.LinkIdentity(ctx, guest.ID, "game_center", "G:link-001"))
+
+	found, err := s.FindByGameCenterID(ctx, "G:link-001")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, guest.ID, found.ID)
+	assert.False(t, found.IsGuest)
+
+	found, err = s.FindByIdentity(ctx, "guest", guest.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found, "guest credential no longer signs in to a linked account")
+}
+
+func TestPostgresStore_MergeAccounts(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	guest := account.NewGuestAccount("손님")
+	main := account.NewGameCenterAccount("G:merge-001", "본계정")
+	friend := account.NewGuestAccount("친구")
+	for _, acc := range []*account.Account{guest, main, friend} {
+		require.NoError(t, s.Create(ctx, acc))
+	}
+
+	require.NoError(t, s.SaveAchievementProgress(ctx, guest.ID, []achievement.Progress{{AchievementID: "marathon", Value: 2400}}))
+	require.NoError(t, s.SaveAchievementProgress(ctx, main.ID, []achievement.Progress{{AchievementID: "marathon", Value: 1200}}))
+	require.NoError(t, s.RecordResults(ctx, "", []leaderboard.Result{
+		{AccountID: guest.ID, Won: true, RatingDelta: 16},
+		{AccountID: main.ID, Won: true, RatingDelta: 16},
+	}))
+	_, err := s.RequestFriend(ctx, guest.ID, friend.ID)
+	require.NoError(t, err)
+	ban := moderation.NewBan(guest.ID, "speed hack", "admin-1", time.Hour)
+	require.NoError(t, s.IssueBan(ctx, ban))
+	require.NoError(t, s.CreateReport(ctx, moderation.NewReport(friend.ID, guest.ID, moderation.CategoryCheating, "", "ABCD", "match-1", nil)))
+	require.NoError(t, s.CreateReport(ctx, moderation.NewReport(guest.ID, friend.ID, moderation.CategoryAFK, "", "ABCD", "match-1", nil)))
+
+	require.NoError(t, s.MergeAccounts(ctx, guest.ID, main.ID))
+
+	gone, err := s.FindByID(ctx, guest.ID)
+	require.NoError(t, err)
+	assert.Nil(t, gone)
+
+	progress, err := s.LoadAchievementProgress(ctx, main.ID)
+	require.NoError(t, err)
+	require.Len(t, progress, 1)
+	assert.Equal(t, 2400.0, progress[0].Value)
+
+	self, err := s.StandingOf(ctx, leaderboard.Query{Season: leaderboard.SeasonAllTime, Metric: leaderboard.MetricWins}, main.ID)
+	require.NoError(t, err)
+	require.NotNil(t, self)
+	assert.Equal(t, 2, self.Wins)
+
+	friends, err := s.ListFriends(ctx, friend.ID)
+	require.NoError(t, err)
+	require.Len(t, friends, 1)
+	assert.Equal(t, main.ID, friends[0].AccountID)
+
+	// Moderation history follows the merge instead of cascading away
+	active, err := s.ActiveBan(ctx, main.ID)
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	assert.Equal(t, ban.ID, active.ID)
+
+	against, err := s.ListReports(ctx, moderation.ReportQuery{TargetID: main.ID})
+	require.NoError(t, err)
+	assert.Len(t, against, 1)
+	filed, err := s.ListReports(ctx, moderation.ReportQuery{TargetID: friend.ID})
+	require.NoError(t, err)
+	require.Len(t, filed, 1)
+	assert.Equal(t, main.ID, filed[0].ReporterID)
+
+	audit, err := s.ListAudit(ctx, moderation.AuditQuery{TargetID: main.ID, Actor: guest.ID})
+	require.NoError(t, err)
+	require.Len(t, audit, 1)
+	assert.Equal(t, moderation.ActionMergeAccounts, audit[0].Action)
+}
@@ -0,0 +1,242 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/leaderboard"
+)
+
+const leaderboardSchema = `
+CREATE TABLE IF NOT EXISTS seasons (
+    id TEXT PRIMARY KEY,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS standings (
+    season TEXT NOT NULL,
+    account_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    games INTEGER NOT NULL DEFAULT 0,
+    wins INTEGER NOT NULL DEFAULT 0,
+    arrests INTEGER NOT NULL DEFAULT 0,
+    rescues INTEGER NOT NULL DEFAULT 0,
+    rating DOUBLE PRECISION NOT NULL DEFAULT 1000,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (season, account_id)
+);
+CREATE INDEX IF NOT EXISTS idx_standings_season_rating ON standings(season, rating DESC);
+
+CREATE TABLE IF NOT EXISTS season_archive (
+    season TEXT NOT NULL REFERENCES seasons(id),
+    account_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    final_rank INTEGER NOT NULL,
+    games INTEGER NOT NULL,
+    wins INTEGER NOT NULL,
+    arrests INTEGER NOT NULL,
+    rescues INTEGER NOT NULL,
+    rating DOUBLE PRECISION NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (season, account_id)
+);
+`
+
+// metricColumns maps leaderboard metrics to standings columns.
+// Only these values are ever interpolated into SQL.
+var metricColumns = map[leaderboard.Metric]string{
+	leaderboard.MetricWins:    "wins",
+	leaderboard.MetricRating:  "rating",
+	leaderboard.MetricArrests: "arrests",
+	leaderboard.MetricRescues: "rescues",
+}
+
+// CurrentSeason returns the active season ID, or empty string if none has started.
+func (s *PostgresStore) CurrentSeason(ctx context.Context) (string, error) {
+	var id string
+	err := s.pool.QueryRow(ctx,
+		`SELECT id FROM seasons WHERE ended_at IS NULL ORDER BY started_at DESC LIMIT 1`).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return id, err
+}
+
+// StartSeason archives the active season's standings and makes seasonID active.
+// It does nothing if seasonID is already active, and returns
+// leaderboard.ErrSeasonEnded if seasonID is a season that has ended.
+func (s *PostgresStore) StartSeason(ctx context.Context, seasonID string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize rollovers, e.g. several servers starting with a new season at once
+	if _, err := tx.Exec(ctx, `LOCK TABLE seasons IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+	var endedAt *time.Time
+	err = tx.QueryRow(ctx, `SELECT ended_at FROM seasons WHERE id = $1`, seasonID).Scan(&endedAt)
+	switch {
+	case err == nil && endedAt == nil:
+		return nil
+	case err == nil:
+		return fmt.Errorf("%w: %s", leaderboard.ErrSeasonEnded, seasonID)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return err
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO season_archive (season, account_id, final_rank, games, wins, arrests, rescues, rating)
+		 SELECT st.season, st.account_id, RANK() OVER (PARTITION BY st.season ORDER BY st.rating DESC),
+		        st.games, st.wins, st.arrests, st.rescues, st.rating
+		 FROM standings st JOIN seasons se ON se.id = st.season
+		 WHERE se.ended_at IS NULL
+		 ON CONFLICT (season, account_id) DO NOTHING`)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE seasons SET ended_at = NOW() WHERE ended_at IS NULL`); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `INSERT INTO seasons (id) VALUES ($1)`, seasonID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// LoadRatings returns ratings for the given accounts in a season.
+func (s *PostgresStore) LoadRatings(ctx context.Context, season string, accountIDs []string) (map[string]float64, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT account_id, rating FROM standings WHERE season = $1 AND account_id = ANY($2)`,
+		season, accountIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := make(map[string]float64, len(accountIDs))
+	for rows.Next() {
+		var id string
+		var rating float64
+		if err := rows.Scan(&id, &rating); err != nil {
+			return nil, err
+		}
+		ratings[id] = rating
+	}
+	return ratings, rows.Err()
+}
+
+// RecordResults applies match results to the all-time standings and, if
+// season is set, that season's standings, in one transaction.
+func (s *PostgresStore) RecordResults(ctx context.Context, season string, results []leaderboard.Result) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, r := range results {
+		queueStanding(batch, leaderboard.SeasonAllTime, r, r.RatingDelta)
+		if season != "" {
+			queueStanding(batch, season, r, r.SeasonRatingDelta)
+		}
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// queueStanding adds one match result to an account's standing in a season.
+func queueStanding(batch *pgx.Batch, season string, r leaderboard.Result, ratingDelta float64) {
+	wins := 0
+	if r.Won {
+		wins = 1
+	}
+	batch.Queue(
+		`INSERT INTO standings (season, account_id, games, wins, arrests, rescues, rating, updated_at)
+		 VALUES ($1, $2, 1, $3, $4, $5, $6, NOW())
+		 ON CONFLICT (season, account_id) DO UPDATE SET
+		     games = standings.games + 1,
+		     wins = standings.wins + EXCLUDED.wins,
+		     arrests = standings.arrests + EXCLUDED.arrests,
+		     rescues = standings.rescues + EXCLUDED.rescues,
+		     rating = standings.rating + $7::DOUBLE PRECISION,
+		     updated_at = NOW()`,
+		season, r.AccountID, wins, r.Arrests, r.Rescues, leaderboard.InitialRating+ratingDelta, ratingDelta)
+}
+
+// TopStandings returns a page of ranked standings and the total number of ranked accounts.
+func (s *PostgresStore) TopStandings(ctx context.Context, q leaderboard.Query) ([]leaderboard.Entry, int, error) {
+	col, ok := metricColumns[q.Metric]
+	if !ok {
+		return nil, 0, leaderboard.ErrUnknownMetric
+	}
+
+	var total int
+	if err := s.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM standings WHERE season = $1`, q.Season).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(
+		`SELECT RANK() OVER (ORDER BY st.%[1]s DESC), st.account_id, a.nickname,
+		        st.games, st.wins, st.arrests, st.rescues, st.rating
+		 FROM standings st JOIN accounts a ON a.id = st.account_id
+		 WHERE st.season = $1
+		 ORDER BY st.%[1]s DESC, st.account_id
+		 OFFSET $2 LIMIT $3`, col),
+		q.Season, q.Offset, q.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var entries []leaderboard.Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, *e)
+	}
+	return entries, total, rows.Err()
+}
+
+// StandingOf returns an account's ranked standing, or nil if the account is unranked.
+func (s *PostgresStore) StandingOf(ctx context.Context, q leaderboard.Query, accountID string) (*leaderboard.Entry, error) {
+	col, ok := metricColumns[q.Metric]
+	if !ok {
+		return nil, leaderboard.ErrUnknownMetric
+	}
+
+	row := s.pool.QueryRow(ctx, fmt.Sprintf(
+		`SELECT (SELECT COUNT(*) + 1 FROM standings o WHERE o.season = st.season AND o.%[1]s > st.%[1]s),
+		        st.account_id, a.nickname, st.games, st.wins, st.arrests, st.rescues, st.rating
+		 FROM standings st JOIN accounts a ON a.id = st.account_id
+		 WHERE st.season = $1 AND st.account_id = $2`, col),
+		q.Season, accountID)
+
+	e, err := scanEntry(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return e, err
+}
+
+func scanEntry(row pgx.Row) (*leaderboard.Entry, error) {
+	var e leaderboard.Entry
+	err := row.Scan(&e.Rank, &e.AccountID, &e.Nickname, &e.Games, &e.Wins, &e.Arrests, &e.Rescues, &e.Rating)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/account"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/leaderboard"
+)
+
+func TestPostgresStore_RecordAndRankStandings(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	a := account.NewGuestAccount("A")
+	b := account.NewGuestAccount("B")
+	require.NoError(t, s.Create(ctx, a))
+	require.NoError(t, s.Create(ctx, b))
+
+	err := s.RecordResults(ctx, "", []leaderboard.Result{
+		{AccountID: a.ID, Won: true, Arrests: 2, RatingDelta: 16},
+		{AccountID: b.ID, Won: false, Rescues: 1, RatingDelta: -16},
+	})
+	require.NoError(t, err)
+
+	q := leaderboard.Query{Season: leaderboard.SeasonAllTime, Metric: leaderboard.MetricRating, Limit: 10}
+	entries, total, err := s.TopStandings(ctx, q)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, entries, 2)
+	assert.Equal(t, a.ID, entries[0].AccountID)
+	assert.Equal(t, 1, entries[0].Rank)
+	assert.Equal(t, 1016.0, entries[0].Rating)
+	assert.Equal(t, "B", entries[1].Nickname)
+
+	self, err := s.StandingOf(ctx, q, b.ID)
+	require.NoError(t, err)
+	require.NotNil(t, self)
+	assert.Equal(t, 2, self.Rank)
+
+	ratings, err := s.LoadRatings(ctx, leaderboard.SeasonAllTime, []string{a.ID, b.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 984.0, ratings[b.ID])
+}
+
+func TestPostgresStore_RecordResultsUpdatesBothBoards(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	acc := account.NewGuestAccount("양쪽")
+	require.NoError(t, s.Create(ctx, acc))
+
+	require.NoError(t, s.RecordResults(ctx, "2026-s1", []leaderboard.Result{
+		{AccountID: acc.ID, Won: true, RatingDelta: 10, SeasonRatingDelta: 16},
+	}))
+
+	allTime, err := s.LoadRatings(ctx, leaderboard.SeasonAllTime, []string{acc.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1010.0, allTime[acc.ID])
+	season, err := s.LoadRatings(ctx, "2026-s1", []string{acc.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1016.0, season[acc.ID])
+}
+
+func TestPostgresStore_StartSeasonArchivesPrevious(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	acc := account.NewGuestAccount("시즌유저")
+	require.NoError(t, s.Create(ctx, acc))
+
+	require.NoError(t, s.StartSeason(ctx, "2026-s1"))
+	current, err := s.CurrentSeason(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-s1", current)
+
+	require.NoError(t, s.RecordResults(ctx, "2026-s1", []leaderboard.Result{{AccountID: acc.ID, Won: true}}))
+
+	require.NoError(t, s.StartSeason(ctx, "2026-s2"))
+	current, err = s.CurrentSeason(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-s2", current)
+
+	// Restarting with the active season is a no-op; an ended one is refused
+	require.NoError(t, s.StartSeason(ctx, "2026-s2"))
+	assert.ErrorIs(t, s.StartSeason(ctx, "2026-s1"), leaderboard.ErrSeasonEnded)
+	current, err = s.CurrentSeason(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-s2", current)
+
+	var rank int
+	err = s.pool.QueryRow(ctx,
+		`SELECT final_rank FROM season_archive WHERE season = $1 AND account_id = $2`, "2026-s1", acc.ID).Scan(&rank)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rank)
+}
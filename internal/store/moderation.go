@@ -0,0 +1,403 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+)
+
+const moderationSchema = `
+CREATE TABLE IF NOT EXISTS bans (
+    id TEXT PRIMARY KEY,
+    account_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL DEFAULT '',
+    issued_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ,
+    permanent BOOLEAN NOT NULL DEFAULT false,
+    lifted_at TIMESTAMPTZ,
+    lifted_by TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_bans_account ON bans(account_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    target_id TEXT NOT NULL DEFAULT '',
+    detail JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log(target_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS reports (
+    id TEXT PRIMARY KEY,
+    reporter_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    target_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    target_nickname TEXT NOT NULL DEFAULT '',
+    category TEXT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    room_code TEXT NOT NULL,
+    match_id TEXT NOT NULL,
+    evidence JSONB,
+    status TEXT NOT NULL DEFAULT 'open',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_by TEXT NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMPTZ,
+    review_note TEXT NOT NULL DEFAULT '',
+    UNIQUE (reporter_id, target_id, match_id)
+);
+CREATE INDEX IF NOT EXISTS idx_reports_queue ON reports(status, created_at);
+
+CREATE TABLE IF NOT EXISTS account_flags (
+    id TEXT PRIMARY KEY,
+    account_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    evidence JSONB,
+    status TEXT NOT NULL DEFAULT 'open',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_by TEXT NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMPTZ,
+    review_note TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_account_flags_queue ON account_flags(status, created_at);
+`
+
+const banColumns = `id, account_id, reason, issued_by, created_at, expires_at, permanent, lifted_at, lifted_by`
+
+// IssueBan stores a ban and records it in the audit log.
+func (s *PostgresStore) IssueBan(ctx context.Context, ban *moderation.Ban) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO bans (id, account_id, reason, issued_by, created_at, expires_at, permanent)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		ban.ID, ban.AccountID, ban.Reason, ban.IssuedBy, ban.CreatedAt, ban.ExpiresAt, ban.Permanent); err != nil {
+		return err
+	}
+	entry := moderation.NewAuditEntry(ban.IssuedBy, moderation.ActionBan, ban.AccountID, ban)
+	if err := insertAudit(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// LiftBan ends a ban early and records it in the audit log.
+func (s *PostgresStore) LiftBan(ctx context.Context, banID, actor string) (*moderation.Ban, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	ban, err := scanBan(tx.QueryRow(ctx,
+		`SELECT `+banColumns+` FROM bans WHERE id = $1 FOR UPDATE`, banID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, moderation.ErrBanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ban.LiftedAt != nil {
+		return nil, moderation.ErrAlreadyLifted
+	}
+
+	now := time.Now()
+	if _, err := tx.Exec(ctx,
+		`UPDATE bans SET lifted_at = $2, lifted_by = $3 WHERE id = $1`, banID, now, actor); err != nil {
+		return nil, err
+	}
+	ban.LiftedAt = &now
+	ban.LiftedBy = actor
+
+	entry := moderation.NewAuditEntry(actor, moderation.ActionUnban, ban.AccountID, map[string]string{"ban_id": ban.ID})
+	if err := insertAudit(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+	return ban, tx.Commit(ctx)
+}
+
+// ActiveBan returns the ban currently keeping an account out, or nil if none.
+// A permanent ban wins over temporary ones, then the latest expiry.
+func (s *PostgresStore) ActiveBan(ctx context.Context, accountID string) (*moderation.Ban, error) {
+	ban, err := scanBan(s.pool.QueryRow(ctx,
+		`SELECT `+banColumns+` FROM bans
+		 WHERE account_id = $1 AND lifted_at IS NULL AND (permanent OR expires_at > NOW())
+		 ORDER BY permanent DESC, expires_at DESC
+		 LIMIT 1`, accountID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return ban, err
+}
+
+// ListBans returns bans matching q, newest first.
+func (s *PostgresStore) ListBans(ctx context.Context, q moderation.BanQuery) ([]moderation.Ban, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+banColumns+` FROM bans
+		 WHERE ($1 = '' OR account_id = $1)
+		   AND (NOT $2 OR (lifted_at IS NULL AND (permanent OR expires_at > NOW())))
+		 ORDER BY created_at DESC
+		 LIMIT $3 OFFSET $4`,
+		q.AccountID, q.ActiveOnly, moderation.NormalizeLimit(q.Limit), max(q.Offset, 0))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bans []moderation.Ban
+	for rows.Next() {
+		ban, err := scanBan(rows)
+		if err != nil {
+			return nil, err
+		}
+		bans = append(bans, *ban)
+	}
+	return bans, rows.Err()
+}
+
+// RecordAudit appends an entry to the audit log.
+func (s *PostgresStore) RecordAudit(ctx context.Context, entry moderation.AuditEntry) error {
+	return insertAudit(ctx, s.pool, entry)
+}
+
+// RecordAudits appends entries to the audit log in one batch.
+func (s *PostgresStore) RecordAudits(ctx context.Context, entries []moderation.AuditEntry) error {
+	batch := &pgx.Batch{}
+	for _, e := range entries {
+		batch.Queue(insertAuditSQL, e.Actor, e.Action, e.TargetID, e.Detail, e.CreatedAt)
+	}
+	return s.pool.SendBatch(ctx, batch).Close()
+}
+
+// ListAudit returns audit entries matching q, newest first.
+func (s *PostgresStore) ListAudit(ctx context.Context, q moderation.AuditQuery) ([]moderation.AuditEntry, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, actor, action, target_id, detail, created_at FROM audit_log
+		 WHERE ($1 = '' OR actor = $1) AND ($2 = '' OR target_id = $2)
+		 ORDER BY id DESC
+		 LIMIT $3 OFFSET $4`,
+		q.Actor, q.TargetID, moderation.NormalizeLimit(q.Limit), max(q.Offset, 0))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []moderation.AuditEntry
+	for rows.Next() {
+		var e moderation.AuditEntry
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &e.TargetID, &e.Detail, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+const reportColumns = `id, reporter_id, target_id, target_nickname, category, comment, room_code, match_id,
+	evidence, status, created_at, reviewed_by, reviewed_at, review_note`
+
+// CreateReport stores a player report. Returns moderation.ErrDuplicateReport
+// if the reporter already reported the target in the same match.
+func (s *PostgresStore) CreateReport(ctx context.Context, r *moderation.Report) error {
+	tag, err := s.pool.Exec(ctx,
+		`INSERT INTO reports (id, reporter_id, target_id, target_nickname, category, comment, room_code, match_id, evidence, status, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		 ON CONFLICT (reporter_id, target_id, match_id) DO NOTHING`,
+		r.ID, r.ReporterID, r.TargetID, r.TargetNickname, r.Category, r.Comment, r.RoomCode, r.MatchID, r.Evidence, r.Status, r.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return moderation.ErrDuplicateReport
+	}
+	return nil
+}
+
+// ListReports returns reports matching q, oldest first.
+func (s *PostgresStore) ListReports(ctx context.Context, q moderation.ReportQuery) ([]moderation.Report, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+reportColumns+` FROM reports
+		 WHERE ($1 = '' OR status = $1) AND ($2 = '' OR target_id = $2)
+		 ORDER BY created_at, id
+		 LIMIT $3 OFFSET $4`,
+		string(q.Status), q.TargetID, moderation.NormalizeLimit(q.Limit), max(q.Offset, 0))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []moderation.Report
+	for rows.Next() {
+		r, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, *r)
+	}
+	return reports, rows.Err()
+}
+
+// ReviewReport closes an open report with the given outcome and records
+// it in the audit log.
+func (s *PostgresStore) ReviewReport(ctx context.Context, reportID, reviewer string, status moderation.ReportStatus, note string) (*moderation.Report, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	r, err := scanReport(tx.QueryRow(ctx,
+		`SELECT `+reportColumns+` FROM reports WHERE id = $1 FOR UPDATE`, reportID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, moderation.ErrReportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.Status.Closed() {
+		return nil, moderation.ErrReportClosed
+	}
+
+	now := time.Now()
+	if _, err := tx.Exec(ctx,
+		`UPDATE reports SET status = $2, reviewed_by = $3, reviewed_at = $4, review_note = $5 WHERE id = $1`,
+		reportID, status, reviewer, now, note); err != nil {
+		return nil, err
+	}
+	r.Status = status
+	r.ReviewedBy = reviewer
+	r.ReviewedAt = &now
+	r.ReviewNote = note
+
+	entry := moderation.NewAuditEntry(reviewer, moderation.ActionReviewReport, r.TargetID,
+		map[string]string{"report_id": r.ID, "status": string(status), "note": note})
+	if err := insertAudit(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+	return r, tx.Commit(ctx)
+}
+
+const flagColumns = `id, account_id, source, reason, evidence, status, created_at, reviewed_by, reviewed_at, review_note`
+
+// CreateFlag stores an account flag.
+func (s *PostgresStore) CreateFlag(ctx context.Context, f *moderation.Flag) error {
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO account_flags (id, account_id, source, reason, evidence, status, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		f.ID, f.AccountID, f.Source, f.Reason, f.Evidence, f.Status, f.CreatedAt)
+	return err
+}
+
+// ListFlags returns flags matching q, oldest first.
+func (s *PostgresStore) ListFlags(ctx context.Context, q moderation.FlagQuery) ([]moderation.Flag, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+flagColumns+` FROM account_flags
+		 WHERE ($1 = '' OR status = $1) AND ($2 = '' OR account_id = $2)
+		 ORDER BY created_at, id
+		 LIMIT $3 OFFSET $4`,
+		string(q.Status), q.AccountID, moderation.NormalizeLimit(q.Limit), max(q.Offset, 0))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flags []moderation.Flag
+	for rows.Next() {
+		f, err := scanFlag(rows)
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, *f)
+	}
+	return flags, rows.Err()
+}
+
+// ReviewFlag closes an open flag with the given outcome and records it in
+// the audit log.
+func (s *PostgresStore) ReviewFlag(ctx context.Context, flagID, reviewer string, status moderation.ReportStatus, note string) (*moderation.Flag, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	f, err := scanFlag(tx.QueryRow(ctx,
+		`SELECT `+flagColumns+` FROM account_flags WHERE id = $1 FOR UPDATE`, flagID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, moderation.ErrFlagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if f.Status.Closed() {
+		return nil, moderation.ErrFlagClosed
+	}
+
+	now := time.Now()
+	if _, err := tx.Exec(ctx,
+		`UPDATE account_flags SET status = $2, reviewed_by = $3, reviewed_at = $4, review_note = $5 WHERE id = $1`,
+		flagID, status, reviewer, now, note); err != nil {
+		return nil, err
+	}
+	f.Status = status
+	f.ReviewedBy = reviewer
+	f.ReviewedAt = &now
+	f.ReviewNote = note
+
+	entry := moderation.NewAuditEntry(reviewer, moderation.ActionReviewFlag, f.AccountID,
+		map[string]string{"flag_id": f.ID, "status": string(status), "note": note})
+	if err := insertAudit(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+	return f, tx.Commit(ctx)
+}
+
+// execer is satisfied by both the pool and a transaction.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+const insertAuditSQL = `INSERT INTO audit_log (actor, action, target_id, detail, created_at) VALUES ($1, $2, $3, $4, $5)`
+
+func insertAudit(ctx context.Context, db execer, e moderation.AuditEntry) error {
+	_, err := db.Exec(ctx, insertAuditSQL, e.Actor, e.Action, e.TargetID, e.Detail, e.CreatedAt)
+	return err
+}
+
+func scanBan(row pgx.Row) (*moderation.Ban, error) {
+	var b moderation.Ban
+	err := row.Scan(&b.ID, &b.AccountID, &b.Reason, &b.IssuedBy, &b.CreatedAt, &b.ExpiresAt, &b.Permanent, &b.LiftedAt, &b.LiftedBy)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func scanReport(row pgx.Row) (*moderation.Report, error) {
+	var r moderation.Report
+	err := row.Scan(&r.ID, &r.ReporterID, &r.TargetID, &r.TargetNickname, &r.Category, &r.Comment, &r.RoomCode, &r.MatchID,
+		&r.Evidence, &r.Status, &r.CreatedAt, &r.ReviewedBy, &r.ReviewedAt, &r.ReviewNote)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func scanFlag(row pgx.Row) (*moderation.Flag, error) {
+	var f moderation.Flag
+	err := row.Scan(&f.ID, &f.AccountID, &f.Source, &f.Reason, &f.Evidence, &f.Status, &f.CreatedAt, &f.ReviewedBy, &f.ReviewedAt, &f.ReviewNote)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/account"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+)
+
+func TestPostgresStore_BanLifecycle(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	acc := account.NewGuestAccount("치터")
+	require.NoError(t, s.Create(ctx, acc))
+
+	active, err := s.ActiveBan(ctx, acc.ID)
+	require.NoError(t, err)
+	assert.Nil(t, active)
+
+	temp := moderation.NewBan(acc.ID, "speed hack", "admin-1", time.Hour)
+	perm := moderation.NewBan(acc.ID, "repeat offense", "admin-1", 0)
+	require.NoError(t, s.IssueBan(ctx, temp))
+	require.NoError(t, s.IssueBan(ctx, perm))
+
+	active, err = s.ActiveBan(ctx, acc.ID)
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	assert.Equal(t, perm.ID, active.ID, "permanent bans take precedence")
+
+	lifted, err := s.LiftBan(ctx, perm.ID, "admin-2")
+	require.NoError(t, err)
+	assert.Equal(t, "admin-2", lifted.LiftedBy)
+	_, err = s.LiftBan(ctx, perm.ID, "admin-2")
+	assert.ErrorIs(t, err, moderation.ErrAlreadyLifted)
+	_, err = s.LiftBan(ctx, "missing", "admin-2")
+	assert.ErrorIs(t, err, moderation.ErrBanNotFound)
+
+	active, err = s.ActiveBan(ctx, acc.ID)
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	assert.Equal(t, temp.ID, active.ID)
+
+	bans, err := s.ListBans(ctx, moderation.BanQuery{AccountID: acc.ID, ActiveOnly: true})
+	require.NoError(t, err)
+	assert.Len(t, bans, 1)
+
+	audit, err := s.ListAudit(ctx, moderation.AuditQuery{TargetID: acc.ID})
+	require.NoError(t, err)
+	require.Len(t, audit, 3)
+	assert.Equal(t, moderation.ActionUnban, audit[0].Action)
+	assert.Equal(t, "admin-2", audit[0].Actor)
+}
+
+func TestPostgresStore_ReportQueue(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	reporter := account.NewGuestAccount("신고자")
+	target := account.NewGuestAccount("대상")
+	require.NoError(t, s.Create(ctx, reporter))
+	require.NoError(t, s.Create(ctx, target))
+
+	evidence := map[string]string{"state": "ended"}
+	first := moderation.NewReport(reporter.ID, target.ID, moderation.CategoryCheating, "too fast", "ABCD", "match-1", evidence)
+	require.NoError(t, s.CreateReport(ctx, first))
+
+	again := moderation.NewReport(reporter.ID, target.ID, moderation.CategoryAFK, "", "ABCD", "match-1", evidence)
+	assert.ErrorIs(t, s.CreateReport(ctx, again), moderation.ErrDuplicateReport)
+
+	next := moderation.NewReport(reporter.ID, target.ID, moderation.CategoryAFK, "", "ABCD", "match-2", evidence)
+	require.NoError(t, s.CreateReport(ctx, next))
+
+	queue, err := s.ListReports(ctx, moderation.ReportQuery{Status: moderation.StatusOpen, TargetID: target.ID})
+	require.NoError(t, err)
+	require.Len(t, queue, 2)
+	assert.Equal(t, first.ID, queue[0].ID, "oldest reports first")
+	assert.JSONEq(t, `{"state":"ended"}`, string(queue[0].Evidence))
+
+	reviewed, err := s.ReviewReport(ctx, first.ID, "admin-1", moderation.StatusDismissed, "lag")
+	require.NoError(t, err)
+	assert.Equal(t, moderation.StatusDismissed, reviewed.Status)
+	_, err = s.ReviewReport(ctx, first.ID, "admin-1", moderation.StatusActioned, "")
+	assert.ErrorIs(t, err, moderation.ErrReportClosed)
+	_, err = s.ReviewReport(ctx, "missing", "admin-1", moderation.StatusActioned, "")
+	assert.ErrorIs(t, err, moderation.ErrReportNotFound)
+
+	queue, err = s.ListReports(ctx, moderation.ReportQuery{Status: moderation.StatusOpen, TargetID: target.ID})
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.Equal(t, next.ID, queue[0].ID)
+
+	audit, err := s.ListAudit(ctx, moderation.AuditQuery{TargetID: target.ID})
+	require.NoError(t, err)
+	require.Len(t, audit, 1)
+	assert.Equal(t, moderation.ActionReviewReport, audit[0].Action)
+}
+
+func TestPostgresStore_FlagQueue(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	acc := account.NewGuestAccount("의심")
+	require.NoError(t, s.Create(ctx, acc))
+
+	flag := moderation.NewFlag(acc.ID, "anticheat", "speed", map[string]float64{"score": 95})
+	require.NoError(t, s.CreateFlag(ctx, flag))
+
+	queue, err := s.ListFlags(ctx, moderation.FlagQuery{Status: moderation.StatusOpen, AccountID: acc.ID})
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.Equal(t, flag.ID, queue[0].ID)
+	assert.Equal(t, "speed", queue[0].Reason)
+	assert.JSONEq(t, `{"score":95}`, string(queue[0].Evidence))
+
+	reviewed, err := s.ReviewFlag(ctx, flag.ID, "admin-1", moderation.StatusActioned, "banned")
+	require.NoError(t, err)
+	assert.Equal(t, moderation.StatusActioned, reviewed.Status)
+	assert.Equal(t, "admin-1", reviewed.ReviewedBy)
+	_, err = s.ReviewFlag(ctx, flag.ID, "admin-1", moderation.StatusDismissed, "")
+	assert.ErrorIs(t, err, moderation.ErrFlagClosed)
+	_, err = s.ReviewFlag(ctx, "missing", "admin-1", moderation.StatusDismissed, "")
+	assert.ErrorIs(t, err, moderation.ErrFlagNotFound)
+
+	queue, err = s.ListFlags(ctx, moderation.FlagQuery{Status: moderation.StatusOpen, AccountID: acc.ID})
+	require.NoError(t, err)
+	assert.Empty(t, queue)
+
+	audit, err := s.ListAudit(ctx, moderation.AuditQuery{TargetID: acc.ID})
+	require.NoError(t, err)
+	require.Len(t, audit, 1)
+	assert.Equal(t, moderation.ActionReviewFlag, audit[0].Action)
+}
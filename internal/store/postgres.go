@@ -8,6 +8,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/account"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/achievement"
 )
 
 const schema = `
@@ -20,9 +21,19 @@ CREATE TABLE IF NOT EXISTS accounts (
     last_login_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
 CREATE INDEX IF NOT EXISTS idx_accounts_game_center_id ON accounts(game_center_id);
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS nickname_changed_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS achievement_progress (
+    account_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    achievement_id TEXT NOT NULL,
+    progress DOUBLE PRECISION NOT NULL DEFAULT 0,
+    unlocked_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, achievement_id)
+);
 `
 
-// PostgresStore implements AccountStore using PostgreSQL.
+// PostgresStore implements AccountStore, AchievementStore, LeaderboardStore, FriendStore and ModerationStore using PostgreSQL.
 type PostgresStore struct {
 	pool *pgxpool.Pool
 }
@@ -39,9 +50,11 @@ func NewPostgresStore(ctx context.Context, databaseURL string) (*PostgresStore,
 		return nil, err
 	}
 
-	if _, err := pool.Exec(ctx, schema); err != nil {
-		pool.Close()
-		return nil, err
+	for _, ddl := range []string{schema, identitySchema, leaderboardSchema, socialSchema, moderationSchema, sessionSchema} {
+		if _, err := pool.Exec(ctx, ddl); err != nil {
+			pool.Close()
+			return nil, err
+		}
 	}
 
 	return &PostgresStore{pool: pool}, nil
@@ -50,7 +63,7 @@ func NewPostgresStore(ctx context.Context, databaseURL string) (*PostgresStore,
 // FindByGameCenterID looks up an account by Game Center player ID.
 func (s *PostgresStore) FindByGameCenterID(ctx context.Context, gcID string) (*account.Account, error) {
 	row := s.pool.QueryRow(ctx,
-		`SELECT id, game_center_id, nickname, is_guest, created_at, last_login_at
+		`SELECT id, game_center_id, nickname, is_guest, created_at, last_login_at, nickname_changed_at
 		 FROM accounts WHERE game_center_id = $1`, gcID)
 
 	acc, err := scanAccount(row)
@@ -63,7 +76,7 @@ func (s *PostgresStore) FindByGameCenterID(ctx context.Context, gcID string) (*a
 // FindByID looks up an account by internal ID.
 func (s *PostgresStore) FindByID(ctx context.Context, id string) (*account.Account, error) {
 	row := s.pool.QueryRow(ctx,
-		`SELECT id, game_center_id, nickname, is_guest, created_at, last_login_at
+		`SELECT id, game_center_id, nickname, is_guest, created_at, last_login_at, nickname_changed_at
 		 FROM accounts WHERE id = $1`, id)
 
 	acc, err := scanAccount(row)
@@ -89,13 +102,48 @@ func (s *PostgresStore) UpdateLastLogin(ctx context.Context, id string) error {
 	return err
 }
 
-// UpdateNickname updates the account nickname.
+// UpdateNickname updates the account nickname and records when it changed.
 func (s *PostgresStore) UpdateNickname(ctx context.Context, id string, nickname string) error {
 	_, err := s.pool.Exec(ctx,
-		`UPDATE accounts SET nickname = $1 WHERE id = $2`, nickname, id)
+		`UPDATE accounts SET nickname = $1, nickname_changed_at = $2 WHERE id = $3`, nickname, time.Now(), id)
 	return err
 }
 
+// LoadAchievementProgress returns all progress entries for an account.
+func (s *PostgresStore) LoadAchievementProgress(ctx context.Context, accountID string) ([]achievement.Progress, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT achievement_id, progress, unlocked_at
+		 FROM achievement_progress WHERE account_id = $1`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []achievement.Progress
+	for rows.Next() {
+		var p achievement.Progress
+		if err := rows.Scan(&p.AchievementID, &p.Value, &p.UnlockedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return result, rows.Err()
+}
+
+// SaveAchievementProgress upserts progress entries for an account.
+func (s *PostgresStore) SaveAchievementProgress(ctx context.Context, accountID string, progress []achievement.Progress) error {
+	batch := &pgx.Batch{}
+	for _, p := range progress {
+		batch.Queue(
+			`INSERT INTO achievement_progress (account_id, achievement_id, progress, unlocked_at, updated_at)
+			 VALUES ($1, $2, $3, $4, NOW())
+			 ON CONFLICT (account_id, achievement_id)
+			 DO UPDATE SET progress = EXCLUDED.progress, unlocked_at = EXCLUDED.unlocked_at, updated_at = NOW()`,
+			accountID, p.AchievementID, p.Value, p.UnlockedAt)
+	}
+	return s.pool.SendBatch(ctx, batch).Close()
+}
+
 // Close releases database resources.
 func (s *PostgresStore) Close() error {
 	s.pool.Close()
@@ -104,7 +152,7 @@ func (s *PostgresStore) Close() error {
 
 func scanAccount(row pgx.Row) (*account.Account, error) {
 	var acc account.Account
-	err := row.Scan(&acc.ID, &acc.GameCenterID, &acc.Nickname, &acc.IsGuest, &acc.CreatedAt, &acc.LastLoginAt)
+	err := row.Scan(&acc.ID, &acc.GameCenterID, &acc.Nickname, &acc.IsGuest, &acc.CreatedAt, &acc.LastLoginAt, &acc.NicknameChangedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/account"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/achievement"
 )
 
 func getTestDatabaseURL(t *testing.T) string {
@@ -27,9 +29,11 @@ func setupTestStore(t *testing.T) *PostgresStore {
 	s, err := NewPostgresStore(ctx, url)
 	require.NoError(t, err)
 
-	// Clean up accounts table for test isolation
-	_, err = s.pool.Exec(ctx, "DELETE FROM accounts")
-	require.NoError(t, err)
+	// Clean up tables for test isolation
+	for _, table := range []string{"audit_log", "season_archive", "seasons", "accounts"} {
+		_, err = s.pool.Exec(ctx, "DELETE FROM "+table)
+		require.NoError(t, err)
+	}
 
 	t.Cleanup(func() {
 		s.Close()
@@ -120,6 +124,7 @@ func TestPostgresStore_UpdateNickname(t *testing.T) {
 	found, err := s.FindByID(ctx, acc.ID)
 	require.NoError(t, err)
 	assert.Equal(t, "새닉네임", found.Nickname)
+	assert.NotNil(t, found.NicknameChangedAt)
 }
 
 func TestPostgresStore_UpdateLastLogin(t *testing.T) {
@@ -150,3 +155,36 @@ func TestPostgresStore_DuplicateGameCenterID(t *testing.T) {
 	err = s.Create(ctx, acc2)
 	assert.Error(t, err)
 }
+
+func TestPostgresStore_AchievementProgress(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	acc := account.NewGuestAccount("업적유저")
+	require.NoError(t, s.Create(ctx, acc))
+
+	unlockedAt := time.Now().Truncate(time.Microsecond)
+	err := s.SaveAchievementProgress(ctx, acc.ID, []achievement.Progress{
+		{AchievementID: "first_arrest", Value: 1, UnlockedAt: &unlockedAt},
+		{AchievementID: "marathon", Value: 1200},
+	})
+	require.NoError(t, err)
+
+	// Upsert overwrites existing progress
+	err = s.SaveAchievementProgress(ctx, acc.ID, []achievement.Progress{
+		{AchievementID: "marathon", Value: 2400},
+	})
+	require.NoError(t, err)
+
+	progress, err := s.LoadAchievementProgress(ctx, acc.ID)
+	require.NoError(t, err)
+	require.Len(t, progress, 2)
+
+	byID := make(map[string]achievement.Progress)
+	for _, p := range progress {
+		byID[p.AchievementID] = p
+	}
+	assert.True(t, byID["first_arrest"].Unlocked())
+	assert.Equal(t, 2400.0, byID["marathon"].Value)
+	assert.False(t, byID["marathon"].Unlocked())
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+)
+
+// Account revocations keep the revocation time in Unix nanoseconds, the
+// resolution session tokens record when they were issued.
+const sessionSchema = `
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    token_id TEXT PRIMARY KEY,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS account_revocations (
+    account_id TEXT PRIMARY KEY,
+    revoked_at BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+`
+
+// SaveRevocation stores a session token revocation. A later account
+// revocation replaces an earlier one.
+func (s *PostgresStore) SaveRevocation(ctx context.Context, r auth.Revocation) error {
+	if r.TokenID != "" {
+		_, err := s.pool.Exec(ctx,
+			`INSERT INTO revoked_tokens (token_id, expires_at) VALUES ($1, $2)
+			 ON CONFLICT (token_id) DO NOTHING`,
+			r.TokenID, r.ExpiresAt)
+		return err
+	}
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO account_revocations (account_id, revoked_at, expires_at) VALUES ($1, $2, $3)
+		 ON CONFLICT (account_id) DO UPDATE SET
+		     revoked_at = GREATEST(account_revocations.revoked_at, EXCLUDED.revoked_at),
+		     expires_at = GREATEST(account_revocations.expires_at, EXCLUDED.expires_at)`,
+		r.AccountID, r.At.UnixNano(), r.ExpiresAt)
+	return err
+}
+
+// LoadRevocations returns the revocations that have not expired at now,
+// deleting the ones that have.
+func (s *PostgresStore) LoadRevocations(ctx context.Context, now time.Time) ([]auth.Revocation, error) {
+	if _, err := s.pool.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at <= $1`, now); err != nil {
+		return nil, err
+	}
+	if _, err := s.pool.Exec(ctx, `DELETE FROM account_revocations WHERE expires_at <= $1`, now); err != nil {
+		return nil, err
+	}
+
+	var revocations []auth.Revocation
+	rows, err := s.pool.Query(ctx, `SELECT token_id, expires_at FROM revoked_tokens`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var r auth.Revocation
+		if err := rows.Scan(&r.TokenID, &r.ExpiresAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		revocations = append(revocations, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.pool.Query(ctx, `SELECT account_id, revoked_at, expires_at FROM account_revocations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r auth.Revocation
+		var at int64
+		if err := rows.Scan(&r.AccountID, &at, &r.ExpiresAt); err != nil {
+			return nil, err
+		}
+		r.At = time.Unix(0, at)
+		revocations = append(revocations, r)
+	}
+	return revocations, rows.Err()
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/auth"
+)
+
+func TestPostgresStore_Revocations(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+	for _, table := range []string{"revoked_tokens", "account_revocations"} {
+		_, err := s.pool.Exec(ctx, "DELETE FROM "+table)
+		require.NoError(t, err)
+	}
+
+	now := time.Now()
+	earlier := now.Add(-time.Minute)
+	require.NoError(t, s.SaveRevocation(ctx, auth.Revocation{TokenID: "tok-1", At: now, ExpiresAt: now.Add(time.Hour)}))
+	require.NoError(t, s.SaveRevocation(ctx, auth.Revocation{TokenID: "tok-old", At: now, ExpiresAt: now.Add(-time.Second)}))
+	require.NoError(t, s.SaveRevocation(ctx, auth.Revocation{AccountID: "acc-1", At: now, ExpiresAt: now.Add(time.Hour)}))
+	require.NoError(t, s.SaveRevocation(ctx, auth.Revocation{AccountID: "acc-1", At: earlier, ExpiresAt: earlier.Add(time.Hour)}))
+
+	revocations, err := s.LoadRevocations(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, revocations, 2)
+	assert.Equal(t, "tok-1", revocations[0].TokenID)
+	assert.Equal(t, "acc-1", revocations[1].AccountID)
+	assert.Equal(t, now.UnixNano(), revocations[1].At.UnixNano(), "the later revocation is kept at full resolution")
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/social"
+)
+
+const socialSchema = `
+CREATE TABLE IF NOT EXISTS friendships (
+    requester_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    addressee_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    accepted_at TIMESTAMPTZ,
+    PRIMARY KEY (requester_id, addressee_id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_friendships_pair
+    ON friendships (LEAST(requester_id, addressee_id), GREATEST(requester_id, addressee_id));
+CREATE INDEX IF NOT EXISTS idx_friendships_addressee ON friendships(addressee_id);
+`
+
+// RequestFriend sends a friend request, or accepts it if the other account
+// already asked. Returns the resulting status.
+func (s *PostgresStore) RequestFriend(ctx context.Context, from, to string) (social.FriendStatus, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	var requester string
+	var status social.FriendStatus
+	err = tx.QueryRow(ctx,
+		`SELECT requester_id, status FROM friendships
+		 WHERE (requester_id = $1 AND addressee_id = $2) OR (requester_id = $2 AND addressee_id = $1)
+		 FOR UPDATE`, from, to).Scan(&requester, &status)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		_, err = tx.Exec(ctx,
+			`INSERT INTO friendships (requester_id, addressee_id, status) VALUES ($1, $2, $3)`,
+			from, to, social.FriendPending)
+		if err != nil {
+			return "", err
+		}
+		status = social.FriendPending
+	case err != nil:
+		return "", err
+	case status == social.FriendAccepted:
+		return "", social.ErrAlreadyFriends
+	case requester == from:
+		return "", social.ErrAlreadyRequested
+	default:
+		// The other account already asked: accept
+		_, err = tx.Exec(ctx,
+			`UPDATE friendships SET status = $3, accepted_at = NOW()
+			 WHERE requester_id = $1 AND addressee_id = $2`,
+			to, from, social.FriendAccepted)
+		if err != nil {
+			return "", err
+		}
+		status = social.FriendAccepted
+	}
+
+	return status, tx.Commit(ctx)
+}
+
+// AcceptFriend accepts a pending request that requesterID sent to accountID.
+func (s *PostgresStore) AcceptFriend(ctx context.Context, accountID, requesterID string) error {
+	tag, err := s.pool.Exec(ctx,
+		`UPDATE friendships SET status = $3, accepted_at = NOW()
+		 WHERE requester_id = $1 AND addressee_id = $2 AND status = $4`,
+		requesterID, accountID, social.FriendAccepted, social.FriendPending)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return social.ErrRequestNotFound
+	}
+	return nil
+}
+
+// RemoveFriend removes a friendship or pending request in either direction.
+func (s *PostgresStore) RemoveFriend(ctx context.Context, a, b string) error {
+	tag, err := s.pool.Exec(ctx,
+		`DELETE FROM friendships
+		 WHERE (requester_id = $1 AND addressee_id = $2) OR (requester_id = $2 AND addressee_id = $1)`,
+		a, b)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return social.ErrNotFriends
+	}
+	return nil
+}
+
+// ListFriends returns an account's friends and pending requests, accepted first.
+func (s *PostgresStore) ListFriends(ctx context.Context, accountID string) ([]social.Friend, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT a.id, a.nickname, f.status, f.addressee_id = $1
+		 FROM friendships f
+		 JOIN accounts a ON a.id = CASE WHEN f.requester_id = $1 THEN f.addressee_id ELSE f.requester_id END
+		 WHERE f.requester_id = $1 OR f.addressee_id = $1
+		 ORDER BY f.status = $2 DESC, a.nickname, a.id`,
+		accountID, social.FriendAccepted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var friends []social.Friend
+	for rows.Next() {
+		var f social.Friend
+		var incoming bool
+		if err := rows.Scan(&f.AccountID, &f.Nickname, &f.Status, &incoming); err != nil {
+			return nil, err
+		}
+		f.Incoming = incoming && f.Status == social.FriendPending
+		friends = append(friends, f)
+	}
+	return friends, rows.Err()
+}
+
+// AreFriends reports whether two accounts are accepted friends.
+func (s *PostgresStore) AreFriends(ctx context.Context, a, b string) (bool, error) {
+	var ok bool
+	err := s.pool.QueryRow(ctx,
+		`SELECT EXISTS (
+		     SELECT 1 FROM friendships
+		     WHERE ((requester_id = $1 AND addressee_id = $2) OR (requester_id = $2 AND addressee_id = $1))
+		       AND status = $3)`,
+		a, b, social.FriendAccepted).Scan(&ok)
+	return ok, err
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/account"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/social"
+)
+
+func TestPostgresStore_FriendRequestLifecycle(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	a := account.NewGuestAccount("A")
+	b := account.NewGuestAccount("B")
+	require.NoError(t, s.Create(ctx, a))
+	require.NoError(t, s.Create(ctx, b))
+
+	status, err := s.RequestFriend(ctx, a.ID, b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, social.FriendPending, status)
+
+	_, err = s.RequestFriend(ctx, a.ID, b.ID)
+	assert.ErrorIs(t, err, social.ErrAlreadyRequested)
+
+	incoming, err := s.ListFriends(ctx, b.ID)
+	require.NoError(t, err)
+	require.Len(t, incoming, 1)
+	assert.Equal(t, a.ID, incoming[0].AccountID)
+	assert.True(t, incoming[0].Incoming)
+
+	require.NoError(t, s.AcceptFriend(ctx, b.ID, a.ID))
+	assert.ErrorIs(t, s.AcceptFriend(ctx, b.ID, a.ID), social.ErrRequestNotFound)
+
+	ok, err := s.AreFriends(ctx, b.ID, a.ID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = s.RequestFriend(ctx, b.ID, a.ID)
+	assert.ErrorIs(t, err, social.ErrAlreadyFriends)
+
+	require.NoError(t, s.RemoveFriend(ctx, b.ID, a.ID))
+	assert.ErrorIs(t, s.RemoveFriend(ctx, a.ID, b.ID), social.ErrNotFriends)
+}
+
+func TestPostgresStore_CrossedRequestsAccept(t *testing.T) {
+	s := setupTestStore(t)
+	ctx := context.Background()
+
+	a := account.NewGuestAccount("A")
+	b := account.NewGuestAccount("B")
+	require.NoError(t, s.Create(ctx, a))
+	require.NoError(t, s.Create(ctx, b))
+
+	_, err := s.RequestFriend(ctx, a.ID, b.ID)
+	require.NoError(t, err)
+	status, err := s.RequestFriend(ctx, b.ID, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, social.FriendAccepted, status)
+
+	friends, err := s.ListFriends(ctx, a.ID)
+	require.NoError(t, err)
+	require.Len(t, friends, 1)
+	assert.Equal(t, social.FriendAccepted, friends[0].Status)
+	assert.False(t, friends[0].Incoming)
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 
 	"github.com/ugaemi/gyeongdohalsaram-server/internal/account"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/achievement"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/leaderboard"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/moderation"
+	"github.com/ugaemi/gyeongdohalsaram-server/internal/social"
 )
 
 // AccountStore defines the interface for persistent account storage.
@@ -14,10 +18,96 @@ type AccountStore interface {
 	FindByID(ctx context.Context, id string) (*account.Account, error)
 	// Create inserts a new account.
 	Create(ctx context.Context, acc *account.Account) error
+	// FindByIdentity looks up the account linked to an external identity,
+	// returning nil if none is linked.
+	FindByIdentity(ctx context.Context, provider, subject string) (*account.Account, error)
+	// CreateWithIdentity inserts a new account linked to an external identity.
+	CreateWithIdentity(ctx context.Context, acc *account.Account, provider, subject string) error
+	// LinkIdentity links an external identity to an existing account,
+	// upgrading it from a guest.
+	LinkIdentity(ctx context.Context, accountID, provider, subject string) error
+	// MergeAccounts moves fromID's progress into intoID and deletes fromID.
+	MergeAccounts(ctx context.Context, fromID, intoID string) error
 	// UpdateLastLogin updates the last login timestamp.
 	UpdateLastLogin(ctx context.Context, id string) error
-	// UpdateNickname updates the account nickname.
+	// UpdateNickname updates the account nickname and records when it changed.
 	UpdateNickname(ctx context.Context, id string, nickname string) error
 	// Close releases database resources.
 	Close() error
 }
+
+// AchievementStore defines the interface for per-account achievement progress.
+type AchievementStore interface {
+	// LoadAchievementProgress returns all progress entries for an account.
+	LoadAchievementProgress(ctx context.Context, accountID string) ([]achievement.Progress, error)
+	// SaveAchievementProgress upserts progress entries for an account.
+	SaveAchievementProgress(ctx context.Context, accountID string, progress []achievement.Progress) error
+}
+
+// LeaderboardStore defines the interface for season and all-time standings.
+type LeaderboardStore interface {
+	// CurrentSeason returns the active season ID, or empty string if none has started.
+	CurrentSeason(ctx context.Context) (string, error)
+	// StartSeason archives the active season's standings and makes seasonID active.
+	// It does nothing if seasonID is already active, and returns
+	// leaderboard.ErrSeasonEnded if seasonID is a season that has ended.
+	StartSeason(ctx context.Context, seasonID string) error
+	// LoadRatings returns ratings for the given accounts in a season.
+	// Accounts without standings are omitted.
+	LoadRatings(ctx context.Context, season string, accountIDs []string) (map[string]float64, error)
+	// RecordResults applies match results to the all-time standings and, if
+	// season is set, that season's standings, in one transaction.
+	RecordResults(ctx context.Context, season string, results []leaderboard.Result) error
+	// TopStandings returns a page of ranked standings and the total number of ranked accounts.
+	TopStandings(ctx context.Context, q leaderboard.Query) ([]leaderboard.Entry, int, error)
+	// StandingOf returns an account's ranked standing, or nil if the account is unranked.
+	StandingOf(ctx context.Context, q leaderboard.Query, accountID string) (*leaderboard.Entry, error)
+}
+
+// FriendStore defines the interface for friendships between accounts.
+type FriendStore interface {
+	// RequestFriend sends a friend request, or accepts it if the other account already asked.
+	// Returns the resulting status.
+	RequestFriend(ctx context.Context, from, to string) (social.FriendStatus, error)
+	// AcceptFriend accepts a pending request that requesterID sent to accountID.
+	AcceptFriend(ctx context.Context, accountID, requesterID string) error
+	// RemoveFriend removes a friendship or pending request in either direction.
+	RemoveFriend(ctx context.Context, a, b string) error
+	// ListFriends returns an account's friends and pending requests, accepted first.
+	ListFriends(ctx context.Context, accountID string) ([]social.Friend, error)
+	// AreFriends reports whether two accounts are accepted friends.
+	AreFriends(ctx context.Context, a, b string) (bool, error)
+}
+
+// ModerationStore defines the interface for bans and the moderation audit log.
+type ModerationStore interface {
+	// IssueBan stores a ban and records it in the audit log.
+	IssueBan(ctx context.Context, ban *moderation.Ban) error
+	// LiftBan ends a ban early and records it in the audit log.
+	LiftBan(ctx context.Context, banID, actor string) (*moderation.Ban, error)
+	// ActiveBan returns the ban currently in force for an account, or nil if none.
+	ActiveBan(ctx context.Context, accountID string) (*moderation.Ban, error)
+	// ListBans returns bans matching q, newest first.
+	ListBans(ctx context.Context, q moderation.BanQuery) ([]moderation.Ban, error)
+	// RecordAudit appends an entry to the audit log.
+	RecordAudit(ctx context.Context, entry moderation.AuditEntry) error
+	// RecordAudits appends entries to the audit log in one batch.
+	RecordAudits(ctx context.Context, entries []moderation.AuditEntry) error
+	// ListAudit returns audit entries matching q, newest first.
+	ListAudit(ctx context.Context, q moderation.AuditQuery) ([]moderation.AuditEntry, error)
+
+	// CreateReport stores a player report. Returns moderation.ErrDuplicateReport
+	// if the reporter already reported the target in the same match.
+	CreateReport(ctx context.Context, r *moderation.Report) error
+	// ListReports returns reports matching q, oldest first.
+	ListReports(ctx context.Context, q moderation.ReportQuery) ([]moderation.Report, error)
+	// ReviewReport closes an open report and records it in the audit log.
+	ReviewReport(ctx context.Context, reportID, reviewer string, status moderation.ReportStatus, note string) (*moderation.Report, error)
+
+	// CreateFlag stores an account flag.
+	CreateFlag(ctx context.Context, f *moderation.Flag) error
+	// ListFlags returns flags matching q, oldest first.
+	ListFlags(ctx context.Context, q moderation.FlagQuery) ([]moderation.Flag, error)
+	// ReviewFlag closes an open flag and records it in the audit log.
+	ReviewFlag(ctx context.Context, flagID, reviewer string, status moderation.ReportStatus, note string) (*moderation.Flag, error)
+}
@@ -3,6 +3,7 @@ package ws
 import (
 	"encoding/json"
 	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -17,27 +18,101 @@ const (
 
 // Client represents a single WebSocket connection.
 type Client struct {
-	ID            string
-	AccountID     string // Set after authentication
-	Authenticated bool
-	Hub           *Hub
-	Conn          *websocket.Conn
-	Send          chan []byte
+	ID        string
+	AccountID string // Set after authentication
+	Nickname  string // Account nickname, set after authentication
+	IsGuest   bool   // Whether the account is a guest account
+	Hub       *Hub
+	Conn      *websocket.Conn
+	Send      chan []byte
+
+	// quit is closed by Close to end the connection after pending sends
+	quit   chan struct{}
+	closed atomic.Bool
+
+	// authenticated is read by the hub and written by admin requests and
+	// timers as well, so it is atomic
+	authenticated atomic.Bool
+
+	// limiter throttles incoming messages, nil for no limits
+	limiter *rateLimiter
+
+	// Admission holds the connection's admission slot, released when the
+	// connection closes. Nil when admission control is off.
+	Admission Ticket
+}
+
+// Ticket is a connection's slot in admission control.
+type Ticket interface {
+	// Authenticated frees the slot's place in the unauthenticated budget.
+	Authenticated()
+	// Release frees the slot.
+	Release()
 }
 
 // NewClient creates a new Client.
 func NewClient(id string, hub *Hub, conn *websocket.Conn) *Client {
-	return &Client{
+	c := &Client{
 		ID:   id,
 		Hub:  hub,
 		Conn: conn,
 		Send: make(chan []byte, 256),
+		quit: make(chan struct{}),
+	}
+	if hub != nil && hub.RateLimits != nil {
+		c.limiter = newRateLimiter(*hub.RateLimits)
 	}
+	return c
+}
+
+// Close ends the connection once already queued messages are written.
+// It is safe to call more than once.
+func (c *Client) Close() {
+	if c.closed.CompareAndSwap(false, true) && c.quit != nil {
+		close(c.quit)
+	}
+}
+
+// MarkAuthenticated flags the client as signed in.
+func (c *Client) MarkAuthenticated() {
+	c.authenticated.Store(true)
+	if c.Admission != nil {
+		c.Admission.Authenticated()
+	}
+}
+
+// Authenticated reports whether the client is signed in. It is safe to
+// call from any goroutine.
+func (c *Client) Authenticated() bool {
+	return c.authenticated.Load()
+}
+
+// Deauthenticate signs the client out, so its messages are refused from now
+// on, including ones already queued. It is safe to call from any goroutine.
+func (c *Client) Deauthenticate() {
+	c.authenticated.Store(false)
+}
+
+// Kick signs the client out, sends msg as its last message and closes the
+// connection. It is safe to call from any goroutine.
+func (c *Client) Kick(msg Message) {
+	c.Deauthenticate()
+	c.SendMessage(msg)
+	c.Close()
+}
+
+// Closed reports whether Close has been called.
+func (c *Client) Closed() bool {
+	return c.closed.Load()
 }
 
 // ReadPump pumps messages from the WebSocket connection to the hub.
 func (c *Client) ReadPump() {
 	defer func() {
+		c.logRateStats()
+		if c.Admission != nil {
+			c.Admission.Release()
+		}
 		c.Hub.Unregister <- c
 		c.Conn.Close()
 	}()
@@ -57,10 +132,63 @@ func (c *Client) ReadPump() {
 			}
 			break
 		}
+		if !c.admit(message, time.Now()) {
+			continue
+		}
 		c.Hub.Incoming <- &ClientMessage{Client: c, Data: message}
 	}
 }
 
+// admit applies the client's rate limits to an incoming message. Throttled
+// messages are dropped with an occasional throttled response; a client that
+// keeps exceeding its limits is disconnected. Messages over the connection
+// limit are also reported to the hub's OnFlood.
+func (c *Client) admit(message []byte, now time.Time) bool {
+	if c.limiter == nil {
+		return true
+	}
+	if c.Closed() {
+		return false
+	}
+
+	msgType := messageType(message)
+	v, retry := c.limiter.allow(msgType, now)
+	if v == verdictAllow {
+		return true
+	}
+	if c.Hub != nil {
+		c.Hub.throttled.Add(1)
+	}
+
+	if v == verdictDisconnect {
+		slog.Warn("disconnecting client for sustained flooding", "client", c.ID, "account_id", c.AccountID,
+			"throttled", c.limiter.totalThrottled())
+		if c.Hub != nil {
+			c.Hub.rateLimitDisconnects.Add(1)
+		}
+		msg, _ := NewMessage(TypeKicked, map[string]string{"reason": "flood"})
+		c.Kick(msg)
+		return false
+	}
+	if v == verdictFlood && c.Hub != nil && c.limiter.reportFlood(now) {
+		c.Hub.flooded(c)
+	}
+	if c.limiter.notify(now) {
+		msg, _ := NewMessage(TypeThrottled, ThrottledMessage{Type: msgType, RetryAfterMs: retry.Milliseconds()})
+		c.SendMessage(msg)
+	}
+	return false
+}
+
+// logRateStats logs the client's throttling counters, if it was throttled.
+func (c *Client) logRateStats() {
+	if c.limiter == nil || len(c.limiter.throttled) == 0 {
+		return
+	}
+	slog.Info("client rate limit summary", "client", c.ID, "account_id", c.AccountID,
+		"allowed", c.limiter.allowed, "throttled", c.limiter.throttled)
+}
+
 // WritePump pumps messages from the hub to the WebSocket connection.
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)
@@ -87,6 +215,11 @@ func (c *Client) WritePump() {
 			if err := w.Close(); err != nil {
 				return
 			}
+		case <-c.quit:
+			c.flush()
+			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+			c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+			return
 		case <-ticker.C:
 			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
@@ -96,6 +229,24 @@ func (c *Client) WritePump() {
 	}
 }
 
+// flush writes messages already queued on Send. Called from WritePump only.
+func (c *Client) flush() {
+	for {
+		select {
+		case message, ok := <-c.Send:
+			if !ok {
+				return
+			}
+			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.Conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
 // SendMessage sends a Message to this client.
 func (c *Client) SendMessage(msg Message) {
 	data, err := json.Marshal(msg)
@@ -0,0 +1,26 @@
+package ws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_KickFromAnotherGoroutine(t *testing.T) {
+	c := NewClient("c1", nil, nil)
+	c.MarkAuthenticated()
+
+	done := make(chan struct{})
+	go func() {
+		msg, _ := NewMessage(TypeSessionReplaced, nil)
+		c.Kick(msg)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return !c.Authenticated() }, time.Second, time.Millisecond)
+	<-done
+
+	assert.True(t, c.Closed())
+	require.Len(t, c.Send, 1)
+}
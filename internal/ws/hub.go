@@ -3,6 +3,7 @@ package ws
 import (
 	"log/slog"
 	"sync"
+	"sync/atomic"
 )
 
 // Hub maintains the set of active clients and routes messages.
@@ -11,21 +12,41 @@ type Hub struct {
 	Register   chan *Client
 	Unregister chan *Client
 	Incoming   chan *ClientMessage
+	floods     chan *Client
 	mu         sync.RWMutex
 
 	// OnMessage is called for each incoming client message.
 	OnMessage func(cm *ClientMessage)
 	// OnDisconnect is called when a client disconnects.
 	OnDisconnect func(client *Client)
+	// OnFlood is called, at most once a second per client, when a client
+	// sends messages faster than its RateLimits.Connection allows.
+	OnFlood func(client *Client)
+
+	// RateLimits applies to clients created after it is set; nil disables them.
+	RateLimits *RateLimits
+
+	throttled            atomic.Uint64
+	rateLimitDisconnects atomic.Uint64
+}
+
+// HubStats counts connections and rate limiting across all clients.
+type HubStats struct {
+	Clients              int    `json:"clients"`
+	Throttled            uint64 `json:"throttled"`              // messages dropped by rate limits
+	RateLimitDisconnects uint64 `json:"rate_limit_disconnects"` // clients disconnected for flooding
 }
 
 // NewHub creates a new Hub.
 func NewHub() *Hub {
+	limits := DefaultRateLimits()
 	return &Hub{
 		Clients:    make(map[*Client]bool),
 		Register:   make(chan *Client),
 		Unregister: make(chan *Client),
 		Incoming:   make(chan *ClientMessage, 256),
+		floods:     make(chan *Client, 64),
+		RateLimits: &limits,
 	}
 }
 
@@ -55,10 +76,24 @@ func (h *Hub) Run() {
 			if h.OnMessage != nil {
 				h.OnMessage(cm)
 			}
+
+		case client := <-h.floods:
+			if h.OnFlood != nil {
+				h.OnFlood(client)
+			}
 		}
 	}
 }
 
+// flooded queues a flooding client for OnFlood. It never blocks the
+// client's read pump; reports are dropped while the queue is full.
+func (h *Hub) flooded(client *Client) {
+	select {
+	case h.floods <- client:
+	default:
+	}
+}
+
 // Broadcast sends a message to all connected clients.
 func (h *Hub) Broadcast(data []byte) {
 	h.mu.RLock()
@@ -78,3 +113,12 @@ func (h *Hub) ClientCount() int {
 	defer h.mu.RUnlock()
 	return len(h.Clients)
 }
+
+// Stats returns the hub's connection and rate limiting counters.
+func (h *Hub) Stats() HubStats {
+	return HubStats{
+		Clients:              h.ClientCount(),
+		Throttled:            h.throttled.Load(),
+		RateLimitDisconnects: h.rateLimitDisconnects.Load(),
+	}
+}
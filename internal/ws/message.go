@@ -10,33 +10,106 @@ type Message struct {
 
 // Message types - Lobby
 const (
-	TypeCreateRoom = "create_room"
-	TypeJoinRoom   = "join_room"
-	TypeLeaveRoom  = "leave_room"
-	TypeSelectTeam = "select_team"
+	TypeCreateRoom    = "create_room"
+	TypeJoinRoom      = "join_room"
+	TypeLeaveRoom     = "leave_room"
+	TypeSelectTeam    = "select_team"
 	TypePlayerReady   = "player_ready"
 	TypeReturnToLobby = "return_to_lobby"
 	TypeRandomJoin    = "random_join"
+	TypeRoomSettings  = "room_settings"
+	TypeListRooms     = "list_rooms"
 )
 
 // Message types - Gameplay
 const (
-	TypePlayerMove = "player_move"
-	TypeGameState  = "game_state"
-	TypeGameOver   = "game_over"
-	TypeGameStart  = "game_start"
+	TypePlayerMove         = "player_move"
+	TypeGameState          = "game_state"
+	TypeGameOver           = "game_over"
+	TypeGameStart          = "game_start"
+	TypeCountdown          = "countdown"
+	TypeCountdownCancelled = "countdown_cancelled"
+	TypeClientLoaded       = "client_loaded"
+	TypePositionCorrection = "position_correction"
+)
+
+// Message types - Game events (server -> client, mirrors game.EventType)
+const (
+	TypeThiefArrested      = "thief_arrested"
+	TypeThiefInfected      = "thief_infected"
+	TypeThievesRescued     = "thieves_rescued"
+	TypeItemPickedUp       = "item_picked_up"
+	TypeStoneTripped       = "stone_tripped"
+	TypeInvincibilityEnded = "invincibility_ended"
+)
+
+// Message types - Achievements
+const (
+	TypeGetAchievements     = "get_achievements"
+	TypeAchievementUnlocked = "achievement_unlocked"
+)
+
+// Message types - Leaderboard
+const (
+	TypeGetLeaderboard = "get_leaderboard"
+)
+
+// Message types - Social
+const (
+	TypeListFriends           = "list_friends"
+	TypeFriendRequest         = "friend_request"
+	TypeFriendAccept          = "friend_accept"
+	TypeFriendRemove          = "friend_remove"
+	TypeFriendRequestReceived = "friend_request_received"
+	TypeFriendAccepted        = "friend_accepted"
+	TypeInviteToRoom          = "invite_to_room"
+	TypeRoomInvite            = "room_invite"
+	TypeAcceptInvite          = "accept_invite"
+)
+
+// Message types - Party
+const (
+	TypePartyCreate         = "party_create"
+	TypePartyInvite         = "party_invite"
+	TypePartyInviteReceived = "party_invite_received"
+	TypePartyJoin           = "party_join"
+	TypePartyLeave          = "party_leave"
+	TypePartyKick           = "party_kick"
+	TypePartyUpdate         = "party_update"
+	TypePartyCreateRoom     = "party_create_room"
+	TypePartyRandomJoin     = "party_random_join"
+)
+
+// Message types - Account
+const (
+	TypeChangeNickname = "change_nickname"
+)
+
+// Message types - Moderation
+const (
+	TypeReportPlayer = "report_player"
 )
 
 // Message types - Auth
 const (
 	TypeAuthenticate = "authenticate"
 	TypeAuthResult   = "auth_result"
+	TypeLinkAccount  = "link_account"
+	TypeRefreshToken = "refresh_token"
+	TypeRevokeToken  = "revoke_token"
 )
 
 // Message types - System
 const (
-	TypeError    = "error"
-	TypeRoomInfo = "room_info"
+	TypeError      = "error"
+	TypeRoomInfo   = "room_info"
+	TypeAFKWarning = "afk_warning"
+	TypeAFKRemoved = "afk_removed"
+
+	TypeSessionReplaced = "session_replaced"
+	TypeBanned          = "banned"
+	TypeKicked          = "kicked"
+	TypeThrottled       = "throttled"
 )
 
 // ErrorMessage is sent when an error occurs.
@@ -0,0 +1,200 @@
+package ws
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Limit is a token bucket: Rate tokens per second, holding at most Burst.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimits bounds how fast a single connection may send messages.
+type RateLimits struct {
+	// Connection bounds all messages from a client.
+	Connection Limit
+	// PerType bounds individual message types on top of Connection.
+	PerType map[string]Limit
+	// A client throttled MaxStrikes times within StrikeWindow is disconnected.
+	MaxStrikes   int
+	StrikeWindow time.Duration
+}
+
+// DefaultRateLimits allows steady 20 Hz movement with room for jitter while
+// keeping account and room operations to a handful per few seconds.
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		Connection: Limit{Rate: 40, Burst: 80},
+		PerType: map[string]Limit{
+			TypePlayerMove:     {Rate: 30, Burst: 60},
+			TypeAuthenticate:   {Rate: 0.2, Burst: 3},
+			TypeLinkAccount:    {Rate: 0.2, Burst: 3},
+			TypeRefreshToken:   {Rate: 0.2, Burst: 3},
+			TypeChangeNickname: {Rate: 0.2, Burst: 3},
+			TypeCreateRoom:     {Rate: 0.2, Burst: 3},
+			TypeJoinRoom:       {Rate: 1, Burst: 5},
+			TypeRandomJoin:     {Rate: 1, Burst: 5},
+			TypeListRooms:      {Rate: 1, Burst: 5},
+			TypePartyCreate:    {Rate: 0.2, Burst: 3},
+			TypeFriendRequest:  {Rate: 0.5, Burst: 5},
+			TypeInviteToRoom:   {Rate: 0.5, Burst: 5},
+			TypePartyInvite:    {Rate: 0.5, Burst: 5},
+			TypeReportPlayer:   {Rate: 0.2, Burst: 3},
+		},
+		MaxStrikes:   100,
+		StrikeWindow: 10 * time.Second,
+	}
+}
+
+// verdict is a rate limiter's decision on one message.
+type verdict int
+
+const (
+	verdictAllow    verdict = iota
+	verdictThrottle         // dropped by a per-type limit
+	verdictFlood            // dropped by the connection limit
+	verdictDisconnect
+)
+
+// throttleNoticeInterval spaces out throttled responses so that replying
+// does not amplify a flood.
+const throttleNoticeInterval = time.Second
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills the bucket for the time since its last use and takes a token.
+func (b *bucket) take(l Limit, now time.Time) bool {
+	if b.last.IsZero() {
+		b.tokens = float64(l.Burst)
+	} else {
+		b.tokens = min(float64(l.Burst), b.tokens+now.Sub(b.last).Seconds()*l.Rate)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// retryAfter returns how long until the bucket holds a token again.
+func (b *bucket) retryAfter(l Limit) time.Duration {
+	if l.Rate <= 0 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / l.Rate * float64(time.Second))
+}
+
+// rateLimiter applies RateLimits to one client. It is used only by the
+// client's ReadPump goroutine.
+type rateLimiter struct {
+	limits     RateLimits
+	conn       bucket
+	types      map[string]*bucket
+	strikes    []time.Time // throttles within the strike window, oldest first
+	lastNotice time.Time
+	lastFlood  time.Time
+
+	// Counters, logged when the client disconnects
+	allowed   int
+	throttled map[string]int
+}
+
+func newRateLimiter(limits RateLimits) *rateLimiter {
+	return &rateLimiter{
+		limits:    limits,
+		types:     make(map[string]*bucket),
+		throttled: make(map[string]int),
+	}
+}
+
+// allow decides whether a message of msgType may be handled. A per-type
+// limit is checked first so that a strict type does not use up the
+// connection's tokens.
+func (l *rateLimiter) allow(msgType string, now time.Time) (verdict, time.Duration) {
+	if limit, ok := l.limits.PerType[msgType]; ok {
+		b := l.types[msgType]
+		if b == nil {
+			b = &bucket{}
+			l.types[msgType] = b
+		}
+		if !b.take(limit, now) {
+			return l.strike(msgType, now, verdictThrottle), b.retryAfter(limit)
+		}
+	}
+	if !l.conn.take(l.limits.Connection, now) {
+		return l.strike(msgType, now, verdictFlood), l.conn.retryAfter(l.limits.Connection)
+	}
+	l.allowed++
+	return verdictAllow, 0
+}
+
+// strike counts a dropped message and returns v, or verdictDisconnect once
+// the client has been throttled too often.
+func (l *rateLimiter) strike(msgType string, now time.Time, v verdict) verdict {
+	// Count only limited types by name; the rest come from the client and are unbounded
+	if _, ok := l.limits.PerType[msgType]; !ok {
+		msgType = "other"
+	}
+	l.throttled[msgType]++
+
+	cutoff := now.Add(-l.limits.StrikeWindow)
+	i := 0
+	for i < len(l.strikes) && !l.strikes[i].After(cutoff) {
+		i++
+	}
+	l.strikes = append(l.strikes[i:], now)
+	if l.limits.MaxStrikes > 0 && len(l.strikes) >= l.limits.MaxStrikes {
+		return verdictDisconnect
+	}
+	return v
+}
+
+// notify reports whether a throttled response should be sent now.
+func (l *rateLimiter) notify(now time.Time) bool {
+	if now.Sub(l.lastNotice) < throttleNoticeInterval {
+		return false
+	}
+	l.lastNotice = now
+	return true
+}
+
+// reportFlood reports whether a connection flood should be passed on to the
+// hub now, at most once per throttleNoticeInterval.
+func (l *rateLimiter) reportFlood(now time.Time) bool {
+	if now.Sub(l.lastFlood) < throttleNoticeInterval {
+		return false
+	}
+	l.lastFlood = now
+	return true
+}
+
+// totalThrottled returns the number of messages throttled so far.
+func (l *rateLimiter) totalThrottled() int {
+	n := 0
+	for _, c := range l.throttled {
+		n += c
+	}
+	return n
+}
+
+// ThrottledMessage tells a client its message was dropped for exceeding a rate limit.
+type ThrottledMessage struct {
+	Type         string `json:"type"` // type of the dropped message
+	RetryAfterMs int64  `json:"retry_after_ms"`
+}
+
+// messageType extracts the type of a raw message without decoding its data.
+// Malformed messages have an empty type and count only against the connection.
+func messageType(data []byte) string {
+	var head struct {
+		Type string `json:"type"`
+	}
+	json.Unmarshal(data, &head)
+	return head.Type
+}
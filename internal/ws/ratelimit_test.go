@@ -0,0 +1,140 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLimits() RateLimits {
+	return RateLimits{
+		Connection:   Limit{Rate: 10, Burst: 5},
+		PerType:      map[string]Limit{TypeCreateRoom: {Rate: 1, Burst: 1}},
+		MaxStrikes:   4,
+		StrikeWindow: 10 * time.Second,
+	}
+}
+
+func TestRateLimiter_ConnectionBucket(t *testing.T) {
+	l := newRateLimiter(testLimits())
+	now := time.Now()
+
+	for i := 0; i < 5; i++ {
+		v, _ := l.allow(TypePlayerMove, now)
+		require.Equal(t, verdictAllow, v)
+	}
+	v, retry := l.allow(TypePlayerMove, now)
+	assert.Equal(t, verdictFlood, v)
+	assert.Equal(t, 100*time.Millisecond, retry)
+
+	// Tokens refill at the configured rate
+	v, _ = l.allow(TypePlayerMove, now.Add(100*time.Millisecond))
+	assert.Equal(t, verdictAllow, v)
+	assert.Equal(t, 6, l.allowed)
+	assert.Equal(t, map[string]int{"other": 1}, l.throttled)
+}
+
+func TestRateLimiter_PerType(t *testing.T) {
+	l := newRateLimiter(testLimits())
+	now := time.Now()
+
+	v, _ := l.allow(TypeCreateRoom, now)
+	require.Equal(t, verdictAllow, v)
+	v, retry := l.allow(TypeCreateRoom, now)
+	assert.Equal(t, verdictThrottle, v)
+	assert.Equal(t, time.Second, retry)
+
+	// Other types still pass
+	v, _ = l.allow(TypePlayerMove, now)
+	assert.Equal(t, verdictAllow, v)
+	assert.Equal(t, 1, l.throttled[TypeCreateRoom])
+}
+
+func TestRateLimiter_DisconnectOnSustainedAbuse(t *testing.T) {
+	l := newRateLimiter(testLimits())
+	now := time.Now()
+	l.allow(TypeCreateRoom, now)
+
+	for i := 0; i < 3; i++ {
+		v, _ := l.allow(TypeCreateRoom, now)
+		require.Equal(t, verdictThrottle, v)
+	}
+	// Strikes older than the window are forgotten
+	later := now.Add(11 * time.Second)
+	l.allow(TypeCreateRoom, later)
+	v, _ := l.allow(TypeCreateRoom, later)
+	assert.Equal(t, verdictThrottle, v)
+
+	for i := 0; i < 3; i++ {
+		v, _ = l.allow(TypeCreateRoom, later)
+	}
+	assert.Equal(t, verdictDisconnect, v)
+}
+
+func TestClient_Admit(t *testing.T) {
+	limits := testLimits()
+	hub := NewHub()
+	hub.RateLimits = &limits
+	c := NewClient("c1", hub, nil)
+	now := time.Now()
+	create := []byte(`{"type":"create_room","data":{}}`)
+
+	assert.True(t, c.admit(create, now))
+	assert.False(t, c.admit(create, now))
+	assert.False(t, c.admit(create, now))
+
+	// Only one throttled notice per interval
+	require.Len(t, c.Send, 1)
+	var msg Message
+	require.NoError(t, json.Unmarshal(<-c.Send, &msg))
+	assert.Equal(t, TypeThrottled, msg.Type)
+	var throttled ThrottledMessage
+	require.NoError(t, json.Unmarshal(msg.Data, &throttled))
+	assert.Equal(t, TypeCreateRoom, throttled.Type)
+	assert.Equal(t, int64(1000), throttled.RetryAfterMs)
+
+	assert.False(t, c.admit(create, now))
+	assert.False(t, c.admit(create, now))
+	assert.True(t, c.Closed(), "sustained flooding disconnects")
+	require.NoError(t, json.Unmarshal(<-c.Send, &msg))
+	assert.Equal(t, TypeKicked, msg.Type)
+	assert.False(t, c.admit(create, now.Add(time.Minute)), "nothing is admitted after disconnecting")
+
+	stats := hub.Stats()
+	assert.Equal(t, uint64(4), stats.Throttled)
+	assert.Equal(t, uint64(1), stats.RateLimitDisconnects)
+}
+
+func TestClient_AdmitReportsFlood(t *testing.T) {
+	limits := testLimits()
+	limits.MaxStrikes = 0
+	hub := NewHub()
+	hub.RateLimits = &limits
+	c := NewClient("c1", hub, nil)
+	now := time.Now()
+	move := []byte(`{"type":"player_move","data":{}}`)
+	create := []byte(`{"type":"create_room","data":{}}`)
+
+	// Per-type limits alone are not a flood
+	c.admit(create, now)
+	c.admit(create, now)
+	assert.Empty(t, hub.floods)
+
+	for i := 0; i < 4; i++ {
+		require.True(t, c.admit(move, now), "the burst is admitted")
+	}
+	assert.Empty(t, hub.floods)
+
+	assert.False(t, c.admit(move, now))
+	assert.False(t, c.admit(move, now))
+	require.Len(t, hub.floods, 1, "reported once per interval")
+	assert.Same(t, c, <-hub.floods)
+
+	later := now.Add(time.Second)
+	for c.admit(move, later) {
+	}
+	assert.Len(t, hub.floods, 1, "reported again in the next interval")
+}